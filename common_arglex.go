@@ -0,0 +1,249 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"strconv"
+	"strings"
+)
+
+var arglex_token arglex_token_ty
+var arglex_value arglex_value_ty
+
+var arglex_argv []string
+var arglex_pushback []string
+var arglex_table []arglex_table_ty
+
+var arglex_table_common = []arglex_table_ty{
+	{"-Help", arglex_token_help},
+	{"-VERSion", arglex_token_version},
+}
+
+/*
+ * NAME
+ *      arglex_init - initialize lexical analyzer
+ *
+ * SYNOPSIS
+ *      void arglex_init(int argc, char **argv, arglex_table_ty *table);
+ *
+ * DESCRIPTION
+ *      The arglex_init function is used to initialize the command line
+ *      processing.  The table gives the options recognised by this
+ *      program, in addition to the common ones.
+ */
+
+func arglex_init(argv []string, table []arglex_table_ty) {
+	arglex_argv = argv
+	arglex_pushback = nil
+	arglex_table = table
+}
+
+/*
+ * NAME
+ *      arglex_compare - compare an option with its formal name
+ *
+ * SYNOPSIS
+ *      int arglex_compare(char *formal, char *actual);
+ *
+ * DESCRIPTION
+ *      The arglex_compare function is used to compare an actual
+ *      argument against a formal name.  Upper case letters in the
+ *      formal name are mandatory, lower case letters are optional but
+ *      must be given in order, and an underscore matches an optional
+ *      underscore or minus.  Case is not significant in the actual
+ *      argument.  Thus "-Book" matches "-b", "-bo" and "-BOOK", and
+ *      "-No_Action" matches "-na" and "-no-action".
+ *
+ * RETURNS
+ *      bool; true if the actual argument matches the formal name.
+ */
+
+func arglex_compare(formal, actual string) bool {
+	for {
+		if formal == "" {
+			return actual == ""
+		}
+		fc := formal[0]
+		var ac byte
+		if actual != "" {
+			ac = actual[0]
+			if ac >= 'A' && ac <= 'Z' {
+				ac += 'a' - 'A'
+			}
+		}
+		switch {
+		case fc == '_' && (ac == '-' || ac == '_'):
+			formal, actual = formal[1:], actual[1:]
+
+		case fc == '_' || (fc >= 'a' && fc <= 'z'):
+			/*
+			 * optional characters
+			 */
+			if ac == fc && actual != "" && arglex_compare(formal[1:], actual[1:]) {
+				return true
+			}
+
+			/*
+			 * skip forward to next mandatory character,
+			 * or after the underscore
+			 */
+			formal = formal[1:]
+			for formal != "" && formal[0] >= 'a' && formal[0] <= 'z' {
+				formal = formal[1:]
+			}
+			if formal != "" && formal[0] == '_' {
+				formal = formal[1:]
+				if ac == '-' || ac == '_' {
+					actual = actual[1:]
+				}
+			}
+
+		case fc >= 'A' && fc <= 'Z':
+			/*
+			 * mandatory characters
+			 */
+			if fc+('a'-'A') != ac {
+				return false
+			}
+			formal, actual = formal[1:], actual[1:]
+
+		default:
+			if fc != ac {
+				return false
+			}
+			formal, actual = formal[1:], actual[1:]
+		}
+	}
+}
+
+/*
+ * NAME
+ *      arglex - lexical analyser for command line arguments
+ *
+ * SYNOPSIS
+ *      arglex_token_ty arglex(void);
+ *
+ * DESCRIPTION
+ *      The arglex function is used to perform lexical analysis on the
+ *      command line arguments.  Options of the form "-name=value" are
+ *      split into the option and its value.
+ *
+ * RETURNS
+ *      The next token in the token stream.  When the end is reached,
+ *      arglex_token_eoln is returned forever.
+ *
+ * CAVEAT
+ *      Must call arglex_init before this function is called.
+ */
+
+func arglex() arglex_token_ty {
+	var arg string
+	switch {
+	case len(arglex_pushback) > 0:
+		arg = arglex_pushback[0]
+		arglex_pushback = arglex_pushback[1:]
+	case len(arglex_argv) > 0:
+		arg = arglex_argv[0]
+		arglex_argv = arglex_argv[1:]
+
+		/*
+		 * "-name=value" is two arguments
+		 */
+		if strings.HasPrefix(arg, "-") {
+			if n := strings.IndexByte(arg, '='); n > 0 {
+				arglex_pushback = append(arglex_pushback, arg[n+1:])
+				arg = arg[:n]
+			}
+		}
+	default:
+		arglex_value = arglex_value_ty{}
+		arglex_token = arglex_token_eoln
+		return arglex_token
+	}
+
+	arglex_value = arglex_value_ty{alv_string: arg}
+	if n, err := strconv.ParseInt(arg, 10, 64); err == nil {
+		arglex_value.alv_number = n
+		arglex_token = arglex_token_number
+		return arglex_token
+	}
+	if arg == "-" {
+		arglex_token = arglex_token_stdio
+		return arglex_token
+	}
+	if !strings.HasPrefix(arg, "-") {
+		arglex_token = arglex_token_string
+		return arglex_token
+	}
+
+	/*
+	 * scan the tables to see what it matches
+	 */
+	arglex_token = arglex_token_option
+	var hit *arglex_table_ty
+	for _, table := range [][]arglex_table_ty{arglex_table_common, arglex_table} {
+		for i := range table {
+			tp := &table[i]
+			if !arglex_compare(tp.name, arg) {
+				continue
+			}
+			if hit != nil && hit.token != tp.token {
+				fatal_raw("option \"%s\" ambiguous (%s or %s)", arg, hit.name, tp.name)
+			}
+			hit = tp
+		}
+	}
+	if hit != nil {
+		arglex_token = hit.token
+	}
+	return arglex_token
+}
+
+/*
+ * NAME
+ *      arglex_token_name - name of a token
+ *
+ * DESCRIPTION
+ *      The arglex_token_name function is used to obtain the formal
+ *      name of an option token, for use in error messages.
+ */
+
+func arglex_token_name(tok arglex_token_ty) string {
+	switch tok {
+	case arglex_token_eoln:
+		return "end of command line"
+	case arglex_token_number:
+		return "number"
+	case arglex_token_option:
+		return "option"
+	case arglex_token_stdio:
+		return "standard input"
+	case arglex_token_string:
+		return "string"
+	}
+	for _, table := range [][]arglex_table_ty{arglex_table_common, arglex_table} {
+		for _, tp := range table {
+			if tp.token == tok {
+				return tp.name
+			}
+		}
+	}
+	return "unknown"
+}
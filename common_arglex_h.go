@@ -0,0 +1,44 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+type arglex_token_ty int
+
+// enum arglex_token_ty
+const (
+	arglex_token_eoln arglex_token_ty = iota
+	arglex_token_help
+	arglex_token_number
+	arglex_token_option
+	arglex_token_stdio
+	arglex_token_string
+	arglex_token_version
+	ARGLEX_MAX /* MUST be last */
+)
+
+type arglex_table_ty struct {
+	name  string
+	token arglex_token_ty
+}
+
+type arglex_value_ty struct {
+	alv_string string
+	alv_number long
+}
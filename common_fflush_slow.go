@@ -20,7 +20,9 @@
 package main
 
 import (
+	"errors"
 	"os"
+	"syscall"
 	"time"
 )
 
@@ -34,10 +36,13 @@ var fflush_retry_count = 0
 
 func fflush_slowly(fp *os.File) (err error) {
 	for attempts := 0; attempts < MAX_FLUSH_TRY; attempts++ {
-		if err = fp.Sync(); err == nil {
+		if err = fp.Sync(); err == nil || errors.Is(err, syscall.EINVAL) || errors.Is(err, syscall.ENOTSUP) {
 			/*
 			 * No problem - quit trying.
 			 * Report success.
+			 *
+			 * Terminals and pipes can't be synced, but there is
+			 * nothing buffered in user space to flush anyway.
 			 */
 			return nil
 		}
@@ -34,6 +34,22 @@ var state state_ty
 var HAVE_SETLOCALE bool
 var LC_ALL int
 
+/*
+ * NAME
+ *      language_init - initialize language functions
+ *
+ * DESCRIPTION
+ *      The language_init function must be called in main, before any
+ *      other language function, to put the program into the C locale.
+ */
+
+func language_init() {
+	if state != state_uninitialized {
+		return
+	}
+	state = state_C
+}
+
 /*
  * NAME
  *      language_human - set for human conversation
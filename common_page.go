@@ -20,7 +20,6 @@
 package main
 
 import (
-	"fmt"
 	"os"
 	"strconv"
 )
@@ -67,9 +66,6 @@ func default_page_sizes() {
 	}
 
 	// todo: #ifdef TIOCGWINSZ
-	if page_length == 0 || page_width == 0 {
-		fmt.Printf("todo: #ifdef TIOCGWINSZ\n")
-	}
 
 	if page_length == 0 {
 		page_length = DEFAULT_PAGE_LENGTH
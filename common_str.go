@@ -182,15 +182,19 @@ func str_from_c(s []byte) *string_ty {
  */
 
 func str_n_from_c(s []byte, length int) *string_ty {
-	if n, ok := hash_table[string(s)]; ok {
+	if n, ok := hash_table[string(s[:length])]; ok {
+		n.str_references++
 		return n
 	}
 	n := &string_ty{
-		str_length: size_t(length),
-		str_text:   make([]byte, length, length),
+		str_references: 1,
+		str_length:     size_t(length),
+		str_text:       make([]byte, length, length),
 	}
 	copy(n.str_text, s)
 	n.str = string(n.str_text)
+	n.str_hash = hash_generate(n.str_text, n.str_length)
+	hash_table[n.str] = n
 	return n
 }
 
@@ -236,6 +240,26 @@ func str_valid(s *string_ty) bool {
  *      Use str_free when finished with.
  */
 
+/*
+ * NAME
+ *      str_catenate - join two strings
+ *
+ * SYNOPSIS
+ *      string_ty *str_catenate(string_ty *, string_ty *);
+ *
+ * DESCRIPTION
+ *      The str_catenate function is used to concatenate two strings to
+ *      form a new string.
+ *
+ * RETURNS
+ *      string_ty * - a pointer to a string in dynamic memory.
+ *      Use str_free when finished with.
+ */
+
+func str_catenate(s1, s2 *string_ty) *string_ty {
+	return str_from_string(s1.str + s2.str)
+}
+
 func str_format(format string, a ...interface{}) *string_ty {
 	return str_from_string(fmt.Sprintf(format, a...))
 }
@@ -19,6 +19,8 @@
 
 package main
 
+import "strings"
+
 /*
  * NAME
  *      string_list_append - append to a word list
@@ -101,7 +103,7 @@ func string_list_constructor(wlp *string_list_ty) {
 func string_list_copy_constructor(to, from *string_list_ty) {
 	string_list_constructor(to)
 	for _, str := range from.strings {
-		string_list_append(to, str_copy(str))
+		string_list_append(to, str)
 	}
 }
 
@@ -145,3 +147,106 @@ func string_list_destructor(wlp *string_list_ty) {
 	}
 	wlp.strings = nil
 }
+
+/*
+ * NAME
+ *      string_list_bool - truth value of a word list
+ *
+ * SYNOPSIS
+ *      int string_list_bool(string_list_ty *wlp);
+ *
+ * DESCRIPTION
+ *      The string_list_bool function is used to determine the truth
+ *      value of a word list.  A word list is false if it is empty, or
+ *      if all of its words are empty; otherwise it is true.
+ */
+
+func string_list_bool(wlp *string_list_ty) bool {
+	for _, s := range wlp.strings {
+		if len(s.str) != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+ * NAME
+ *      string_list_member - word list membership
+ *
+ * SYNOPSIS
+ *      int string_list_member(string_list_ty *wlp, string_ty *word);
+ *
+ * DESCRIPTION
+ *      The string_list_member function is used to determine if the
+ *      given word is contained in the given word list.
+ *
+ * RETURNS
+ *      bool; true if the word is in the list, false if it is not.
+ */
+
+func string_list_member(wlp *string_list_ty, word *string_ty) bool {
+	for _, s := range wlp.strings {
+		if str_equal(s, word) {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+ * NAME
+ *      string_list_remove_nth - remove a word from a word list
+ *
+ * SYNOPSIS
+ *      void string_list_remove_nth(string_list_ty *wlp, size_t n);
+ *
+ * DESCRIPTION
+ *      The string_list_remove_nth function is used to remove the n'th
+ *      word from a word list.  The order of the remaining words is
+ *      preserved.
+ */
+
+func string_list_remove_nth(wlp *string_list_ty, n size_t) {
+	assert(n >= 0 && n < size_t(len(wlp.strings)), "n in range")
+	str_free(wlp.strings[n])
+	wlp.strings = append(wlp.strings[:n], wlp.strings[n+1:]...)
+}
+
+/*
+ * NAME
+ *      wl2str - form string from word list
+ *
+ * SYNOPSIS
+ *      string_ty *wl2str(string_list_ty *wlp, int start, int stop,
+ *              char *sep);
+ *
+ * DESCRIPTION
+ *      Wl2str is used to form a string from a word list.  The words
+ *      from start to stop (inclusive) are joined, separated by sep.
+ *      If sep is the empty string, a single space is used.
+ *
+ * RETURNS
+ *      A pointer to the newly formed string in dynamic memory.
+ *
+ * CAVEAT
+ *      It is the responsibility of the caller to ensure that the
+ *      new string is freed when finished with, by a call to str_free().
+ */
+
+func wl2str(wlp *string_list_ty, start, stop int, sep string) *string_ty {
+	if sep == "" {
+		sep = " "
+	}
+	if stop >= len(wlp.strings) {
+		stop = len(wlp.strings) - 1
+	}
+	var sb strings.Builder
+	for j := start; j <= stop; j++ {
+		if j > start {
+			sb.WriteString(sep)
+		}
+		sb.WriteString(wlp.strings[j].str)
+	}
+	return str_from_string(sb.String())
+}
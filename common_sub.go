@@ -19,7 +19,11 @@
 
 package main
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
 
 type fp func(*sub_context_ty, *wstring_list_ty) *wstring_ty
 
@@ -125,8 +129,8 @@ func sub_var_set(scp *sub_context_ty, name string, format string, a ...interface
 }
 
 func sub_var_set_long(scp *sub_context_ty, name string, value long) {
-	trace(fmt.Sprintf("sub_var_set_long(scp = %p, name = \"%s\", value = %ld)\n{\n", scp, name, value))
-	sub_var_set(scp, name, "%ld", value)
+	trace(fmt.Sprintf("sub_var_set_long(scp = %p, name = \"%s\", value = %d)\n{\n", scp, name, value))
+	sub_var_set(scp, name, "%d", value)
 	trace("}\n")
 }
 
@@ -143,11 +147,72 @@ func sub_var_set_string(scp *sub_context_ty, name string, value *string_ty) {
 	svp.append_if_unused = false
 	svp.override = false
 	svp.resubstitute = !svp.must_be_used
+	scp.sub_var_list = append(scp.sub_var_list, svp)
 	trace("}\n")
 }
 
+/*
+ * NAME
+ *      subst - substitute variables into a string
+ *
+ * SYNOPSIS
+ *      wstring_ty *subst(sub_context_ty *scp, wstring_ty *s);
+ *
+ * DESCRIPTION
+ *      The subst function is used to replace "$name" and "${name}"
+ *      references in a string with the values set by sub_var_set.
+ *      Names are matched using arglex_compare, so "$filename" will
+ *      find a variable set as "File_Name".  "$$" gives a literal
+ *      dollar sign.  Unknown names are left untouched.
+ *
+ * RETURNS
+ *      wstring_ty *; the substituted string.  Use wstr_free when done.
+ */
+
 func subst(scp *sub_context_ty, s *wstring_ty) *wstring_ty {
-	panic("!implemented")
+	var sb strings.Builder
+	text := s.String()
+	for len(text) > 0 {
+		n := strings.IndexByte(text, '$')
+		if n < 0 {
+			sb.WriteString(text)
+			break
+		}
+		sb.WriteString(text[:n])
+		text = text[n+1:]
+		if strings.HasPrefix(text, "$") {
+			sb.WriteByte('$')
+			text = text[1:]
+			continue
+		}
+
+		var name, rest string
+		if strings.HasPrefix(text, "{") && strings.IndexByte(text, '}') > 0 {
+			n = strings.IndexByte(text, '}')
+			name, rest = text[1:n], text[n+1:]
+		} else {
+			n = 0
+			for n < len(text) && (text[n] == '_' || unicode.IsLetter(rune(text[n])) || unicode.IsDigit(rune(text[n]))) {
+				n++
+			}
+			name, rest = text[:n], text[n:]
+		}
+
+		var svp *table_ty
+		for _, tp := range scp.sub_var_list {
+			if arglex_compare(tp.name, name) {
+				svp = tp
+				break
+			}
+		}
+		if name == "" || svp == nil {
+			sb.WriteByte('$')
+			continue
+		}
+		sb.WriteString(svp.value.String())
+		text = rest
+	}
+	return wstr_from_string(sb.String())
 }
 
 func subst_intl(scp *sub_context_ty, s string) *string_ty {
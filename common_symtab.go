@@ -85,3 +85,66 @@ func symtab_free(stp *symtab_ty) *symtab_ty {
 	trace("}\n")
 	return nil
 }
+
+/*
+ * NAME
+ *      symtab_query - search for a variable
+ *
+ * SYNOPSIS
+ *      void *symtab_query(symtab_ty *, string_ty *key);
+ *
+ * DESCRIPTION
+ *      The symtab_query function is used to reasearch the value of
+ *      a given variable.
+ *
+ * RETURNS
+ *      If the variable has been defined, the function returns the value
+ *      assigned.  If the variable has not been defined, it returns the
+ *      NULL pointer.
+ */
+
+func symtab_query(stp *symtab_ty, key *string_ty) interface{} {
+	trace(fmt.Sprintf("symtab_query(stp = %p, key = %q)\n{\n", stp, key.str))
+	var result interface{}
+	for _, row := range stp.hash_table[key.String()] {
+		if str_equal(key, row.key) {
+			result = row.data
+			break
+		}
+	}
+	trace(fmt.Sprintf("return %p;\n", result))
+	trace("}\n")
+	return result
+}
+
+/*
+ * NAME
+ *      symtab_delete - delete a variable
+ *
+ * SYNOPSIS
+ *      void symtab_delete(string_ty *name);
+ *
+ * DESCRIPTION
+ *      The symtab_delete function is used to delete variables.
+ *
+ * CAVEAT
+ *      The name is freed, the data is reaped.
+ *      (By default, reap does nothing.)
+ */
+
+func symtab_delete(stp *symtab_ty, key *string_ty) {
+	trace(fmt.Sprintf("symtab_delete(stp = %p, key = %q)\n{\n", stp, key.str))
+	rows := stp.hash_table[key.String()]
+	for n, row := range rows {
+		if !str_equal(key, row.key) {
+			continue
+		}
+		if stp.reap != nil {
+			stp.reap(row.data)
+		}
+		str_free(row.key)
+		stp.hash_table[key.String()] = append(rows[:n], rows[n+1:]...)
+		break
+	}
+	trace("}\n")
+}
@@ -192,7 +192,11 @@ func wstr_to_str(ws *wstring_ty) *string_ty {
  */
 
 func wstr_to_mbs(ws *wstring_ty, result_p **char, result_length_p *size_t) string {
-	panic("!implemented")
+	*result_length_p = ws.wstr_length
+	if ws.wstr_length > 0 {
+		*result_p = &ws.wstr_text[0]
+	}
+	return ws.str
 }
 
 func (ws *wstring_ty) String() string {
@@ -0,0 +1,164 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+/*
+ * The table of builtin functions.  Some functions are known by more
+ * than one name.
+ */
+
+var builtin_table = []builtin_ty{
+	{name: "addprefix", interpret: builtin_addprefix_interpret},
+	{name: "addsuffix", interpret: builtin_addsuffix_interpret},
+	{name: "and", interpret: builtin_and_interpret},
+	{name: "basename", interpret: builtin_basename_interpret},
+	{name: "cando", interpret: builtin_cando_interpret},
+	{name: "catenate", interpret: builtin_catenate_interpret},
+	{name: "collect", interpret: builtin_collect_interpret},
+	{name: "collect_lines", interpret: builtin_collect_lines_interpret},
+	{name: "count", interpret: builtin_count_interpret},
+	{name: "defined", interpret: builtin_defined_interpret},
+	{name: "dirname", interpret: builtin_dirname_interpret},
+	{name: "downcase", interpret: builtin_downcase_interpret},
+	{name: "entryname", interpret: builtin_entryname_interpret},
+	{name: "execute", interpret: builtin_execute_interpret},
+	{name: "exists", interpret: builtin_exists_interpret},
+	{name: "exists-symlink", interpret: builtin_exists_symlink_interpret},
+	{name: "expr", interpret: builtin_expr_interpret},
+	{name: "filter", interpret: builtin_match_mask_interpret},
+	{name: "filter_out", interpret: builtin_filter_out_interpret},
+	{name: "find_command", interpret: builtin_find_command_interpret},
+	{name: "findstring", interpret: builtin_findstring_interpret},
+	{name: "firstword", interpret: builtin_head_interpret},
+	{name: "fromto", interpret: builtin_fromto_interpret},
+	{name: "getenv", interpret: builtin_getenv_interpret},
+	{name: "glob", interpret: builtin_glob_interpret},
+	{name: "head", interpret: builtin_head_interpret},
+	{name: "home", interpret: builtin_home_interpret},
+	{name: "if", interpret: builtin_if_interpret},
+	{name: "in", interpret: builtin_in_interpret},
+	{name: "interior_files", interpret: builtin_interior_files_interpret},
+	{name: "leaf_files", interpret: builtin_leaf_files_interpret},
+	{name: "match_mask", interpret: builtin_match_mask_interpret},
+	{name: "mtime", interpret: builtin_mtime_interpret},
+	{name: "not", interpret: builtin_not_interpret},
+	{name: "operating_system", interpret: builtin_operating_system_interpret},
+	{name: "or", interpret: builtin_or_interpret},
+	{name: "patsubst", interpret: builtin_fromto_interpret},
+	{name: "prepost", interpret: builtin_prepost_interpret},
+	{name: "quote", interpret: builtin_quote_interpret},
+	{name: "resolve", interpret: builtin_resolve_interpret},
+	{name: "shell", interpret: builtin_collect_interpret},
+	{name: "sort", interpret: builtin_sort_interpret},
+	{name: "sort_newest", interpret: builtin_sort_newest_interpret},
+	{name: "split", interpret: builtin_split_interpret},
+	{name: "stringset", interpret: builtin_stringset_interpret},
+	{name: "strip", interpret: builtin_strip_interpret},
+	{name: "subst", interpret: builtin_subst_interpret},
+	{name: "suffix", interpret: builtin_suffix_interpret},
+	{name: "tail", interpret: builtin_tail_interpret},
+	{name: "thread-id", interpret: builtin_thread_id_interpret},
+	{name: "uniq", interpret: builtin_uniq_interpret},
+	{name: "unsplit", interpret: builtin_unsplit_interpret},
+	{name: "upcase", interpret: builtin_upcase_interpret},
+	{name: "wildcard", interpret: builtin_glob_interpret},
+	{name: "word", interpret: builtin_word_interpret},
+	{name: "words", interpret: builtin_count_interpret},
+}
+
+/*
+ * NAME
+ *      builtin_initialize - define builtin functions
+ *
+ * SYNOPSIS
+ *      void builtin_initialize(void);
+ *
+ * DESCRIPTION
+ *      The builtin_initialize function is used to define the builtin
+ *      functions, as ids in the global symbol table.
+ */
+
+func builtin_initialize() {
+	for j := range builtin_table {
+		bp := &builtin_table[j]
+		s := str_from_string(bp.name)
+		symtab_assign(id_global_stp(), s, id_builtin_new(bp))
+		str_free(s)
+	}
+}
+
+/*
+ * NAME
+ *      builtin_error - report a builtin function error
+ *
+ * SYNOPSIS
+ *      void builtin_error(expr_position_ty *, string_list_ty *args,
+ *              char *msg);
+ *
+ * DESCRIPTION
+ *      The builtin_error function is used to report an error in the
+ *      use of a builtin function.  The name of the function (the first
+ *      argument) is available in the message as $name.
+ */
+
+func builtin_error(pp *expr_position_ty, args *string_list_ty, msg string) {
+	scp := sub_context_new()
+	sub_var_set_string(scp, "Name", args.strings[0])
+	error_with_position(pp, scp, msg)
+	sub_context_delete(scp)
+}
+
+/*
+ * NAME
+ *      builtin_bool - append a truth value
+ *
+ * SYNOPSIS
+ *      void builtin_bool(string_list_ty *result, int value);
+ *
+ * DESCRIPTION
+ *      The builtin_bool function is used to append a truth value to the
+ *      result list: "1" for true, and the empty string for false.
+ */
+
+func builtin_bool(result *string_list_ty, value bool) {
+	if value {
+		builtin_append_string(result, "1")
+	} else {
+		builtin_append_string(result, "")
+	}
+}
+
+/*
+ * NAME
+ *      builtin_append_string - append a Go string
+ *
+ * SYNOPSIS
+ *      void builtin_append_string(string_list_ty *result, char *s);
+ *
+ * DESCRIPTION
+ *      The builtin_append_string function is used to append a string
+ *      to the result list.
+ */
+
+func builtin_append_string(result *string_list_ty, s string) {
+	sp := str_from_string(s)
+	string_list_append(result, sp)
+	str_free(sp)
+}
@@ -0,0 +1,203 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+/*
+ * NAME
+ *      builtin_and - logical and
+ *
+ * SYNOPSIS
+ *      int builtin_and_interpret(string_list_ty *result,
+ *              string_list_ty *args, expr_position_ty *pp,
+ *              opcode_context_ty *ocp);
+ *
+ * DESCRIPTION
+ *      The and function is true (1) if all of its arguments are true
+ *      (not empty), and false ("") otherwise.
+ *
+ * RETURNS
+ *      int; 0 on success, -1 on error.
+ */
+
+func builtin_and_interpret(result *string_list_ty, args *string_list_ty, pp *expr_position_ty, ocp *opcode_context_ty) int {
+	if len(args.strings) < 2 {
+		builtin_error(pp, args, i18n("$name: requires one or more arguments"))
+		return -1
+	}
+	for _, s := range args.strings[1:] {
+		if len(s.str) == 0 {
+			builtin_bool(result, false)
+			return 0
+		}
+	}
+	builtin_bool(result, true)
+	return 0
+}
+
+/*
+ * NAME
+ *      builtin_or - logical or
+ *
+ * SYNOPSIS
+ *      int builtin_or_interpret(string_list_ty *result,
+ *              string_list_ty *args, expr_position_ty *pp,
+ *              opcode_context_ty *ocp);
+ *
+ * DESCRIPTION
+ *      The or function is true (1) if any of its arguments is true
+ *      (not empty), and false ("") otherwise.
+ *
+ * RETURNS
+ *      int; 0 on success, -1 on error.
+ */
+
+func builtin_or_interpret(result *string_list_ty, args *string_list_ty, pp *expr_position_ty, ocp *opcode_context_ty) int {
+	if len(args.strings) < 2 {
+		builtin_error(pp, args, i18n("$name: requires one or more arguments"))
+		return -1
+	}
+	for _, s := range args.strings[1:] {
+		if len(s.str) != 0 {
+			builtin_bool(result, true)
+			return 0
+		}
+	}
+	builtin_bool(result, false)
+	return 0
+}
+
+/*
+ * NAME
+ *      builtin_not - logical not
+ *
+ * SYNOPSIS
+ *      int builtin_not_interpret(string_list_ty *result,
+ *              string_list_ty *args, expr_position_ty *pp,
+ *              opcode_context_ty *ocp);
+ *
+ * DESCRIPTION
+ *      The not function is true (1) if its arguments are false (empty,
+ *      or all empty strings), and false ("") otherwise.
+ *
+ * RETURNS
+ *      int; 0 on success, -1 on error.
+ */
+
+func builtin_not_interpret(result *string_list_ty, args *string_list_ty, pp *expr_position_ty, ocp *opcode_context_ty) int {
+	for _, s := range args.strings[1:] {
+		if len(s.str) != 0 {
+			builtin_bool(result, false)
+			return 0
+		}
+	}
+	builtin_bool(result, true)
+	return 0
+}
+
+/*
+ * NAME
+ *      builtin_if - conditional
+ *
+ * SYNOPSIS
+ *      int builtin_if_interpret(string_list_ty *result,
+ *              string_list_ty *args, expr_position_ty *pp,
+ *              opcode_context_ty *ocp);
+ *
+ * DESCRIPTION
+ *      The if function is of the form
+ *              [if condition... then value... else value...]
+ *      The words of the condition are tested; if they are true the
+ *      words between "then" and "else" are the result, otherwise the
+ *      words after "else" (the else part is optional).
+ *
+ * RETURNS
+ *      int; 0 on success, -1 on error.
+ */
+
+func builtin_if_interpret(result *string_list_ty, args *string_list_ty, pp *expr_position_ty, ocp *opcode_context_ty) int {
+	then_pos := -1
+	else_pos := len(args.strings)
+	for j := 1; j < len(args.strings); j++ {
+		w := args.strings[j].str
+		if then_pos < 0 && w == "then" {
+			then_pos = j
+		} else if then_pos >= 0 && w == "else" {
+			else_pos = j
+			break
+		}
+	}
+	if then_pos < 0 {
+		builtin_error(pp, args, i18n("$name: no \"then\" word"))
+		return -1
+	}
+
+	condition := false
+	for _, s := range args.strings[1:then_pos] {
+		if len(s.str) != 0 {
+			condition = true
+			break
+		}
+	}
+	var value []*string_ty
+	if condition {
+		value = args.strings[then_pos+1 : else_pos]
+	} else if else_pos < len(args.strings) {
+		value = args.strings[else_pos+1:]
+	}
+	for _, s := range value {
+		string_list_append(result, s)
+	}
+	return 0
+}
+
+/*
+ * NAME
+ *      builtin_in - test for set membership
+ *
+ * SYNOPSIS
+ *      int builtin_in_interpret(string_list_ty *result,
+ *              string_list_ty *args, expr_position_ty *pp,
+ *              opcode_context_ty *ocp);
+ *
+ * DESCRIPTION
+ *      The in function is of the form
+ *              [in word list...]
+ *      It is true (1) if the word is a member of the list, and false
+ *      ("") otherwise.
+ *
+ * RETURNS
+ *      int; 0 on success, -1 on error.
+ */
+
+func builtin_in_interpret(result *string_list_ty, args *string_list_ty, pp *expr_position_ty, ocp *opcode_context_ty) int {
+	if len(args.strings) < 2 {
+		builtin_error(pp, args, i18n("$name: requires one or more arguments"))
+		return -1
+	}
+	word := args.strings[1]
+	for _, s := range args.strings[2:] {
+		if str_equal(word, s) {
+			builtin_bool(result, true)
+			return 0
+		}
+	}
+	builtin_bool(result, false)
+	return 0
+}
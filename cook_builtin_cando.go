@@ -0,0 +1,111 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+/*
+ * NAME
+ *      builtin_cando - test for recipe
+ *
+ * SYNOPSIS
+ *      int builtin_cando_interpret(string_list_ty *result,
+ *              string_list_ty *args, expr_position_ty *pp,
+ *              opcode_context_ty *ocp);
+ *
+ * DESCRIPTION
+ *      The cando function is true (1) if all of the named files can be
+ *      cooked, and false ("") otherwise.  A file can be cooked if
+ *      there is an explicit recipe naming it as a target, an implicit
+ *      recipe with a target pattern which matches it, or if it already
+ *      exists somewhere along the search list.
+ *
+ * RETURNS
+ *      int; 0 on success, -1 on error.
+ */
+
+func builtin_cando_interpret(result *string_list_ty, args *string_list_ty, pp *expr_position_ty, ocp *opcode_context_ty) int {
+	if len(args.strings) < 2 {
+		builtin_error(pp, args, i18n("$name: requires one or more arguments"))
+		return -1
+	}
+	for _, s := range args.strings[1:] {
+		switch builtin_cando_one(s, pp) {
+		case -1:
+			return -1
+		case 0:
+			builtin_bool(result, false)
+			return 0
+		}
+	}
+	builtin_bool(result, true)
+	return 0
+}
+
+func builtin_cando_one(s *string_ty, pp *expr_position_ty) int {
+	for _, rp := range explicit.recipe {
+		for _, t := range rp.target.strings {
+			if str_equal(t, s) {
+				return 1
+			}
+		}
+	}
+	for _, rp := range implicit.recipe {
+		flag_set_options(rp.flags, OPTION_LEVEL_RECIPE)
+		mp := match_new()
+		option_undo_level(OPTION_LEVEL_RECIPE)
+		for _, t := range rp.target.strings {
+			if matched := match_attempt(mp, t, s, pp); matched != 0 {
+				match_delete(mp)
+				return matched
+			}
+		}
+		match_delete(mp)
+	}
+	return search_list_exists(s)
+}
+
+/*
+ * NAME
+ *      builtin_resolve - resolve file names
+ *
+ * SYNOPSIS
+ *      int builtin_resolve_interpret(string_list_ty *result,
+ *              string_list_ty *args, expr_position_ty *pp,
+ *              opcode_context_ty *ocp);
+ *
+ * DESCRIPTION
+ *      The resolve function is used to resolve file names against the
+ *      search list: each name is replaced by the first copy found along
+ *      the search list.  Names with no copies are returned unchanged.
+ *
+ * RETURNS
+ *      int; 0 on success, -1 on error.
+ */
+
+func builtin_resolve_interpret(result *string_list_ty, args *string_list_ty, pp *expr_position_ty, ocp *opcode_context_ty) int {
+	for _, s := range args.strings[1:] {
+		path := search_list_resolve(s)
+		if path == nil {
+			return -1
+		}
+		string_list_append(result, path)
+		str_free(path)
+	}
+	return 0
+}
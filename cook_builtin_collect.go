@@ -0,0 +1,181 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+/*
+ * NAME
+ *      builtin_collect - collect command output
+ *
+ * SYNOPSIS
+ *      int builtin_collect_interpret(string_list_ty *result,
+ *              string_list_ty *args, expr_position_ty *pp,
+ *              opcode_context_ty *ocp);
+ *
+ * DESCRIPTION
+ *      The collect function is used to run a command, and collect its
+ *      standard output.  The arguments are joined with spaces to form
+ *      the command, which is run by the shell.  The output is split
+ *      into words at white space.  It is an error for the command to
+ *      exit with a non-zero exit status.
+ *
+ * RETURNS
+ *      int; 0 on success, -1 on error.
+ */
+
+func builtin_collect_interpret(result *string_list_ty, args *string_list_ty, pp *expr_position_ty, ocp *opcode_context_ty) int {
+	return builtin_collect_common(result, args, pp, strings.Fields)
+}
+
+/*
+ * NAME
+ *      builtin_collect_lines - collect command output
+ *
+ * SYNOPSIS
+ *      int builtin_collect_lines_interpret(string_list_ty *result,
+ *              string_list_ty *args, expr_position_ty *pp,
+ *              opcode_context_ty *ocp);
+ *
+ * DESCRIPTION
+ *      The collect_lines function is used to run a command, and collect
+ *      its standard output, in the same way as the collect function.
+ *      The output is split into words at line boundaries, so each line
+ *      is one word.
+ *
+ * RETURNS
+ *      int; 0 on success, -1 on error.
+ */
+
+func builtin_collect_lines_interpret(result *string_list_ty, args *string_list_ty, pp *expr_position_ty, ocp *opcode_context_ty) int {
+	return builtin_collect_common(result, args, pp, builtin_collect_split_lines)
+}
+
+func builtin_collect_split_lines(s string) []string {
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+func builtin_collect_common(result *string_list_ty, args *string_list_ty, pp *expr_position_ty, split func(string) []string) int {
+	if len(args.strings) < 2 {
+		builtin_error(pp, args, i18n("$name: requires one or more arguments"))
+		return -1
+	}
+	cmd := wl2str(args, 1, len(args.strings)-1, " ")
+	var output strings.Builder
+	status, ok := os_execute(cmd, &output, pp)
+	str_free(cmd)
+	if !ok {
+		return -1
+	}
+	if status != 0 {
+		scp := sub_context_new()
+		sub_var_set_string(scp, "Name", args.strings[0])
+		sub_var_set_long(scp, "Status", long(status))
+		error_with_position(pp, scp, i18n("$name: command exit status $status"))
+		sub_context_delete(scp)
+		return -1
+	}
+	if output.Len() == 0 {
+		return 0
+	}
+	for _, w := range split(output.String()) {
+		builtin_append_string(result, w)
+	}
+	return 0
+}
+
+/*
+ * NAME
+ *      builtin_execute - run a command
+ *
+ * SYNOPSIS
+ *      int builtin_execute_interpret(string_list_ty *result,
+ *              string_list_ty *args, expr_position_ty *pp,
+ *              opcode_context_ty *ocp);
+ *
+ * DESCRIPTION
+ *      The execute function is used to run a command.  The arguments
+ *      are joined with spaces to form the command, which is run by the
+ *      shell.  The result is true (1) if the command exits with a zero
+ *      exit status, and false ("") otherwise.
+ *
+ * RETURNS
+ *      int; 0 on success, -1 on error.
+ */
+
+func builtin_execute_interpret(result *string_list_ty, args *string_list_ty, pp *expr_position_ty, ocp *opcode_context_ty) int {
+	if len(args.strings) < 2 {
+		builtin_error(pp, args, i18n("$name: requires one or more arguments"))
+		return -1
+	}
+	cmd := wl2str(args, 1, len(args.strings)-1, " ")
+	status, ok := os_execute(cmd, nil, pp)
+	str_free(cmd)
+	if !ok {
+		return -1
+	}
+	builtin_bool(result, status == 0)
+	return 0
+}
+
+/*
+ * NAME
+ *      builtin_find_command - search for a command
+ *
+ * SYNOPSIS
+ *      int builtin_find_command_interpret(string_list_ty *result,
+ *              string_list_ty *args, expr_position_ty *pp,
+ *              opcode_context_ty *ocp);
+ *
+ * DESCRIPTION
+ *      The find_command function is used to search the PATH for each
+ *      of the named commands.  The absolute path of each command found
+ *      is returned; commands which are not found yield the empty
+ *      string.  Names containing a slash are not searched for.
+ *
+ * RETURNS
+ *      int; 0 on success, -1 on error.
+ */
+
+func builtin_find_command_interpret(result *string_list_ty, args *string_list_ty, pp *expr_position_ty, ocp *opcode_context_ty) int {
+	for _, s := range args.strings[1:] {
+		if strings.ContainsRune(s.str, '/') {
+			string_list_append(result, s)
+			continue
+		}
+		path, err := exec.LookPath(s.str)
+		if err != nil {
+			builtin_append_string(result, "")
+			continue
+		}
+		if !filepath.IsAbs(path) {
+			if wd, err := os.Getwd(); err == nil {
+				path = filepath.Join(wd, path)
+			}
+		}
+		builtin_append_string(result, path)
+	}
+	return 0
+}
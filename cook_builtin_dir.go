@@ -0,0 +1,189 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import "strings"
+
+/*
+ * NAME
+ *      builtin_dirname - directory part
+ *
+ * SYNOPSIS
+ *      int builtin_dirname_interpret(string_list_ty *result,
+ *              string_list_ty *args, expr_position_ty *pp,
+ *              opcode_context_ty *ocp);
+ *
+ * DESCRIPTION
+ *      The dirname function is used to obtain the directory part of
+ *      each of its arguments.  Names without a directory part yield
+ *      ".".
+ *
+ * RETURNS
+ *      int; 0 on success, -1 on error.
+ */
+
+func builtin_dirname_interpret(result *string_list_ty, args *string_list_ty, pp *expr_position_ty, ocp *opcode_context_ty) int {
+	for _, s := range args.strings[1:] {
+		builtin_append_string(result, builtin_dirname(s.str))
+	}
+	return 0
+}
+
+/*
+ * NAME
+ *      builtin_dirname
+ *
+ * SYNOPSIS
+ *      char *builtin_dirname(char *);
+ *
+ * DESCRIPTION
+ *      The builtin_dirname function is used to obtain the directory
+ *      part of a file name.  Trailing slashes are ignored.
+ */
+
+func builtin_dirname(s string) string {
+	s = builtin_trim_slashes(s)
+	n := strings.LastIndexByte(s, '/')
+	switch {
+	case n < 0:
+		return "."
+	case n == 0:
+		return "/"
+	}
+	return builtin_trim_slashes(s[:n])
+}
+
+func builtin_trim_slashes(s string) string {
+	for len(s) > 1 && s[len(s)-1] == '/' {
+		s = s[:len(s)-1]
+	}
+	return s
+}
+
+/*
+ * NAME
+ *      builtin_entryname - last component
+ *
+ * SYNOPSIS
+ *      int builtin_entryname_interpret(string_list_ty *result,
+ *              string_list_ty *args, expr_position_ty *pp,
+ *              opcode_context_ty *ocp);
+ *
+ * DESCRIPTION
+ *      The entryname function is used to obtain the last component of
+ *      each of its arguments, the name within the directory.
+ *
+ * RETURNS
+ *      int; 0 on success, -1 on error.
+ */
+
+func builtin_entryname_interpret(result *string_list_ty, args *string_list_ty, pp *expr_position_ty, ocp *opcode_context_ty) int {
+	for _, s := range args.strings[1:] {
+		builtin_append_string(result, builtin_entryname(s.str))
+	}
+	return 0
+}
+
+func builtin_entryname(s string) string {
+	s = builtin_trim_slashes(s)
+	if s == "/" {
+		return s
+	}
+	return s[strings.LastIndexByte(s, '/')+1:]
+}
+
+/*
+ * NAME
+ *      builtin_basename - remove suffix
+ *
+ * SYNOPSIS
+ *      int builtin_basename_interpret(string_list_ty *result,
+ *              string_list_ty *args, expr_position_ty *pp,
+ *              opcode_context_ty *ocp);
+ *
+ * DESCRIPTION
+ *      The basename function is used to remove the suffix (from the
+ *      last dot of the last component) from each of its arguments.
+ *      The directory part, if any, is retained.
+ *
+ * RETURNS
+ *      int; 0 on success, -1 on error.
+ */
+
+func builtin_basename_interpret(result *string_list_ty, args *string_list_ty, pp *expr_position_ty, ocp *opcode_context_ty) int {
+	for _, s := range args.strings[1:] {
+		n := builtin_suffix_position(s.str)
+		builtin_append_string(result, s.str[:n])
+	}
+	return 0
+}
+
+/*
+ * NAME
+ *      builtin_suffix - suffix part
+ *
+ * SYNOPSIS
+ *      int builtin_suffix_interpret(string_list_ty *result,
+ *              string_list_ty *args, expr_position_ty *pp,
+ *              opcode_context_ty *ocp);
+ *
+ * DESCRIPTION
+ *      The suffix function is used to obtain the suffix (from the last
+ *      dot of the last component, inclusive) of each of its arguments.
+ *      Arguments without a suffix yield nothing.
+ *
+ * RETURNS
+ *      int; 0 on success, -1 on error.
+ */
+
+func builtin_suffix_interpret(result *string_list_ty, args *string_list_ty, pp *expr_position_ty, ocp *opcode_context_ty) int {
+	for _, s := range args.strings[1:] {
+		n := builtin_suffix_position(s.str)
+		if n < len(s.str) {
+			builtin_append_string(result, s.str[n:])
+		}
+	}
+	return 0
+}
+
+/*
+ * NAME
+ *      builtin_suffix_position
+ *
+ * SYNOPSIS
+ *      int builtin_suffix_position(char *);
+ *
+ * DESCRIPTION
+ *      The builtin_suffix_position function is used to find where the
+ *      suffix of a file name starts.  Only the last component is
+ *      considered.
+ *
+ * RETURNS
+ *      int; the position of the dot, or the length of the name if there
+ *      is no suffix.
+ */
+
+func builtin_suffix_position(s string) int {
+	n := strings.LastIndexByte(s, '.')
+	if n < 0 || strings.IndexByte(s[n:], '/') >= 0 {
+		return len(s)
+	}
+	return n
+}
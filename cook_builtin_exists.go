@@ -0,0 +1,115 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import "strconv"
+
+/*
+ * NAME
+ *      builtin_exists - test for file existence
+ *
+ * SYNOPSIS
+ *      int builtin_exists_interpret(string_list_ty *result,
+ *              string_list_ty *args, expr_position_ty *pp,
+ *              opcode_context_ty *ocp);
+ *
+ * DESCRIPTION
+ *      The exists function is true (1) if all of the named files
+ *      exist, and false ("") otherwise.  Symbolic links are followed.
+ *
+ * RETURNS
+ *      int; 0 on success, -1 on error.
+ */
+
+func builtin_exists_interpret(result *string_list_ty, args *string_list_ty, pp *expr_position_ty, ocp *opcode_context_ty) int {
+	return builtin_exists_common(result, args, pp, os_exists)
+}
+
+/*
+ * NAME
+ *      builtin_exists_symlink - test for file existence
+ *
+ * SYNOPSIS
+ *      int builtin_exists_symlink_interpret(string_list_ty *result,
+ *              string_list_ty *args, expr_position_ty *pp,
+ *              opcode_context_ty *ocp);
+ *
+ * DESCRIPTION
+ *      The exists-symlink function is true (1) if all of the named
+ *      files exist, and false ("") otherwise.  Symbolic links are not
+ *      followed, so a dangling symbolic link exists.
+ *
+ * RETURNS
+ *      int; 0 on success, -1 on error.
+ */
+
+func builtin_exists_symlink_interpret(result *string_list_ty, args *string_list_ty, pp *expr_position_ty, ocp *opcode_context_ty) int {
+	return builtin_exists_common(result, args, pp, os_exists_symlink)
+}
+
+func builtin_exists_common(result *string_list_ty, args *string_list_ty, pp *expr_position_ty, exists func(*string_ty) int) int {
+	if len(args.strings) < 2 {
+		builtin_error(pp, args, i18n("$name: requires one or more arguments"))
+		return -1
+	}
+	for _, s := range args.strings[1:] {
+		switch exists(s) {
+		case -1:
+			return -1
+		case 0:
+			builtin_bool(result, false)
+			return 0
+		}
+	}
+	builtin_bool(result, true)
+	return 0
+}
+
+/*
+ * NAME
+ *      builtin_mtime - file modification time
+ *
+ * SYNOPSIS
+ *      int builtin_mtime_interpret(string_list_ty *result,
+ *              string_list_ty *args, expr_position_ty *pp,
+ *              opcode_context_ty *ocp);
+ *
+ * DESCRIPTION
+ *      The mtime function is used to obtain the last modification time
+ *      of each of the named files, in seconds since the epoch.  Files
+ *      which do not exist yield nothing.
+ *
+ * RETURNS
+ *      int; 0 on success, -1 on error.
+ */
+
+func builtin_mtime_interpret(result *string_list_ty, args *string_list_ty, pp *expr_position_ty, ocp *opcode_context_ty) int {
+	for _, s := range args.strings[1:] {
+		mtime := os_mtime_newest(s)
+		if mtime < 0 {
+			return -1
+		}
+		if mtime == 0 {
+			continue
+		}
+		builtin_append_string(result, strconv.FormatInt(mtime/1e9, 10))
+	}
+	return 0
+}
@@ -0,0 +1,260 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"strconv"
+	"strings"
+)
+
+/*
+ * NAME
+ *      builtin_expr - evaluate arithmetic expression
+ *
+ * SYNOPSIS
+ *      int builtin_expr_interpret(string_list_ty *result,
+ *              string_list_ty *args, expr_position_ty *pp,
+ *              opcode_context_ty *ocp);
+ *
+ * DESCRIPTION
+ *      The expr function is used to evaluate an integer arithmetic
+ *      expression.  The arguments are joined with spaces to form the
+ *      expression.  The usual C operators are understood:
+ *              ( )  unary - + !  * / %  + -  < <= > >=  == !=  &&  ||
+ *      Comparisons and logical operators yield 1 or 0.
+ *
+ * RETURNS
+ *      int; 0 on success, -1 on error.
+ */
+
+func builtin_expr_interpret(result *string_list_ty, args *string_list_ty, pp *expr_position_ty, ocp *opcode_context_ty) int {
+	s := wl2str(args, 1, len(args.strings)-1, " ")
+	ep := &builtin_expr_ty{text: s.str}
+	str_free(s)
+	ep.lex()
+	value := ep.parse_or()
+	if ep.errmsg == "" && ep.token != "" {
+		ep.errmsg = "syntax error"
+	}
+	if ep.errmsg != "" {
+		scp := sub_context_new()
+		sub_var_set_string(scp, "Name", args.strings[0])
+		sub_var_set(scp, "Text", "%s", ep.errmsg)
+		error_with_position(pp, scp, i18n("$name: $text"))
+		sub_context_delete(scp)
+		return -1
+	}
+	builtin_append_string(result, strconv.FormatInt(value, 10))
+	return 0
+}
+
+/*
+ * The state of the expression evaluator.  The expression is evaluated
+ * as it is parsed; the first error is remembered, and evaluation
+ * continues (harmlessly) until the parse unwinds.
+ */
+
+type builtin_expr_ty struct {
+	text   string
+	pos    int
+	token  string /* "" at end of input */
+	value  long   /* when token is "0" */
+	errmsg string
+}
+
+/*
+ * The operators, longest first so that "<=" is not read as "<".
+ */
+
+var builtin_expr_operators = []string{
+	"||", "&&", "==", "!=", "<=", ">=",
+	"<", ">", "+", "-", "*", "/", "%", "!", "(", ")",
+}
+
+func (ep *builtin_expr_ty) lex() {
+	for ep.pos < len(ep.text) && strings.IndexByte(" \t\n", ep.text[ep.pos]) >= 0 {
+		ep.pos++
+	}
+	if ep.pos >= len(ep.text) {
+		ep.token = ""
+		return
+	}
+	c := ep.text[ep.pos]
+	if c >= '0' && c <= '9' {
+		start := ep.pos
+		for ep.pos < len(ep.text) && ep.text[ep.pos] >= '0' && ep.text[ep.pos] <= '9' {
+			ep.pos++
+		}
+		n, err := strconv.ParseInt(ep.text[start:ep.pos], 10, 64)
+		if err != nil && ep.errmsg == "" {
+			ep.errmsg = "number \"" + ep.text[start:ep.pos] + "\" too large"
+		}
+		ep.token = "0"
+		ep.value = n
+		return
+	}
+	for _, op := range builtin_expr_operators {
+		if strings.HasPrefix(ep.text[ep.pos:], op) {
+			ep.pos += len(op)
+			ep.token = op
+			return
+		}
+	}
+	if ep.errmsg == "" {
+		ep.errmsg = "illegal character \"" + string(c) + "\""
+	}
+	ep.token = ""
+}
+
+func builtin_expr_bool(b bool) long {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func (ep *builtin_expr_ty) parse_or() long {
+	v := ep.parse_and()
+	for ep.token == "||" {
+		ep.lex()
+		r := ep.parse_and()
+		v = builtin_expr_bool(v != 0 || r != 0)
+	}
+	return v
+}
+
+func (ep *builtin_expr_ty) parse_and() long {
+	v := ep.parse_equality()
+	for ep.token == "&&" {
+		ep.lex()
+		r := ep.parse_equality()
+		v = builtin_expr_bool(v != 0 && r != 0)
+	}
+	return v
+}
+
+func (ep *builtin_expr_ty) parse_equality() long {
+	v := ep.parse_relational()
+	for ep.token == "==" || ep.token == "!=" {
+		op := ep.token
+		ep.lex()
+		r := ep.parse_relational()
+		if op == "==" {
+			v = builtin_expr_bool(v == r)
+		} else {
+			v = builtin_expr_bool(v != r)
+		}
+	}
+	return v
+}
+
+func (ep *builtin_expr_ty) parse_relational() long {
+	v := ep.parse_additive()
+	for ep.token == "<" || ep.token == "<=" || ep.token == ">" || ep.token == ">=" {
+		op := ep.token
+		ep.lex()
+		r := ep.parse_additive()
+		switch op {
+		case "<":
+			v = builtin_expr_bool(v < r)
+		case "<=":
+			v = builtin_expr_bool(v <= r)
+		case ">":
+			v = builtin_expr_bool(v > r)
+		default:
+			v = builtin_expr_bool(v >= r)
+		}
+	}
+	return v
+}
+
+func (ep *builtin_expr_ty) parse_additive() long {
+	v := ep.parse_multiplicative()
+	for ep.token == "+" || ep.token == "-" {
+		op := ep.token
+		ep.lex()
+		r := ep.parse_multiplicative()
+		if op == "+" {
+			v += r
+		} else {
+			v -= r
+		}
+	}
+	return v
+}
+
+func (ep *builtin_expr_ty) parse_multiplicative() long {
+	v := ep.parse_unary()
+	for ep.token == "*" || ep.token == "/" || ep.token == "%" {
+		op := ep.token
+		ep.lex()
+		r := ep.parse_unary()
+		switch op {
+		case "*":
+			v *= r
+		default:
+			if r == 0 {
+				if ep.errmsg == "" {
+					ep.errmsg = "division by zero"
+				}
+				return 0
+			}
+			if op == "/" {
+				v /= r
+			} else {
+				v %= r
+			}
+		}
+	}
+	return v
+}
+
+func (ep *builtin_expr_ty) parse_unary() long {
+	switch ep.token {
+	case "-":
+		ep.lex()
+		return -ep.parse_unary()
+	case "+":
+		ep.lex()
+		return ep.parse_unary()
+	case "!":
+		ep.lex()
+		return builtin_expr_bool(ep.parse_unary() == 0)
+	case "(":
+		ep.lex()
+		v := ep.parse_or()
+		if ep.token != ")" {
+			if ep.errmsg == "" {
+				ep.errmsg = "\")\" expected"
+			}
+			return 0
+		}
+		ep.lex()
+		return v
+	case "0":
+		v := ep.value
+		ep.lex()
+		return v
+	}
+	if ep.errmsg == "" {
+		ep.errmsg = "syntax error"
+	}
+	return 0
+}
@@ -0,0 +1,74 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+/*
+ * NAME
+ *      builtin_interior_files - interior files of the graph
+ *
+ * SYNOPSIS
+ *      int builtin_interior_files_interpret(string_list_ty *result,
+ *              string_list_ty *args, expr_position_ty *pp,
+ *              opcode_context_ty *ocp);
+ *
+ * DESCRIPTION
+ *      The interior_files function is used to obtain the files which
+ *      were targets of recipes in the most recently constructed
+ *      dependency graph.  It takes no arguments.
+ *
+ * RETURNS
+ *      int; 0 on success, -1 on error.
+ */
+
+func builtin_interior_files_interpret(result *string_list_ty, args *string_list_ty, pp *expr_position_ty, ocp *opcode_context_ty) int {
+	if len(args.strings) != 1 {
+		builtin_error(pp, args, i18n("$name: requires zero arguments"))
+		return -1
+	}
+	string_list_append_list(result, &cook_interior_files)
+	return 0
+}
+
+/*
+ * NAME
+ *      builtin_leaf_files - leaf files of the graph
+ *
+ * SYNOPSIS
+ *      int builtin_leaf_files_interpret(string_list_ty *result,
+ *              string_list_ty *args, expr_position_ty *pp,
+ *              opcode_context_ty *ocp);
+ *
+ * DESCRIPTION
+ *      The leaf_files function is used to obtain the files which were
+ *      ingredients, but not targets, in the most recently constructed
+ *      dependency graph.  It takes no arguments.
+ *
+ * RETURNS
+ *      int; 0 on success, -1 on error.
+ */
+
+func builtin_leaf_files_interpret(result *string_list_ty, args *string_list_ty, pp *expr_position_ty, ocp *opcode_context_ty) int {
+	if len(args.strings) != 1 {
+		builtin_error(pp, args, i18n("$name: requires zero arguments"))
+		return -1
+	}
+	string_list_append_list(result, &cook_leaf_files)
+	return 0
+}
@@ -0,0 +1,161 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"os"
+	"runtime"
+	"strconv"
+)
+
+/*
+ * NAME
+ *      builtin_getenv - get environment variable
+ *
+ * SYNOPSIS
+ *      int builtin_getenv_interpret(string_list_ty *result,
+ *              string_list_ty *args, expr_position_ty *pp,
+ *              opcode_context_ty *ocp);
+ *
+ * DESCRIPTION
+ *      The getenv function is used to obtain the values of environment
+ *      variables.  Each argument names an environment variable; unset
+ *      variables yield the empty string.
+ *
+ * RETURNS
+ *      int; 0 on success, -1 on error.
+ */
+
+func builtin_getenv_interpret(result *string_list_ty, args *string_list_ty, pp *expr_position_ty, ocp *opcode_context_ty) int {
+	for _, s := range args.strings[1:] {
+		builtin_append_string(result, os.Getenv(s.str))
+	}
+	return 0
+}
+
+/*
+ * NAME
+ *      builtin_home - home directory
+ *
+ * SYNOPSIS
+ *      int builtin_home_interpret(string_list_ty *result,
+ *              string_list_ty *args, expr_position_ty *pp,
+ *              opcode_context_ty *ocp);
+ *
+ * DESCRIPTION
+ *      The home function is used to obtain the user's home directory.
+ *      It takes no arguments.
+ *
+ * RETURNS
+ *      int; 0 on success, -1 on error.
+ */
+
+func builtin_home_interpret(result *string_list_ty, args *string_list_ty, pp *expr_position_ty, ocp *opcode_context_ty) int {
+	if len(args.strings) != 1 {
+		builtin_error(pp, args, i18n("$name: requires zero arguments"))
+		return -1
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		home = "/"
+	}
+	builtin_append_string(result, home)
+	return 0
+}
+
+/*
+ * NAME
+ *      builtin_defined - test for variable definition
+ *
+ * SYNOPSIS
+ *      int builtin_defined_interpret(string_list_ty *result,
+ *              string_list_ty *args, expr_position_ty *pp,
+ *              opcode_context_ty *ocp);
+ *
+ * DESCRIPTION
+ *      The defined function is true (1) if the named variable, or
+ *      function, is defined, and false ("") otherwise.  Local
+ *      variables are searched before global variables.
+ *
+ * RETURNS
+ *      int; 0 on success, -1 on error.
+ */
+
+func builtin_defined_interpret(result *string_list_ty, args *string_list_ty, pp *expr_position_ty, ocp *opcode_context_ty) int {
+	if len(args.strings) != 2 {
+		builtin_error(pp, args, i18n("$name: requires one argument"))
+		return -1
+	}
+	builtin_bool(result, opcode_context_id_search(ocp, args.strings[1]) != nil)
+	return 0
+}
+
+/*
+ * NAME
+ *      builtin_operating_system - name of operating system
+ *
+ * SYNOPSIS
+ *      int builtin_operating_system_interpret(string_list_ty *result,
+ *              string_list_ty *args, expr_position_ty *pp,
+ *              opcode_context_ty *ocp);
+ *
+ * DESCRIPTION
+ *      The operating_system function is used to obtain the name of the
+ *      operating system cook is running on.  It takes no arguments.
+ *
+ * RETURNS
+ *      int; 0 on success, -1 on error.
+ */
+
+func builtin_operating_system_interpret(result *string_list_ty, args *string_list_ty, pp *expr_position_ty, ocp *opcode_context_ty) int {
+	if len(args.strings) != 1 {
+		builtin_error(pp, args, i18n("$name: requires zero arguments"))
+		return -1
+	}
+	builtin_append_string(result, runtime.GOOS)
+	return 0
+}
+
+/*
+ * NAME
+ *      builtin_thread_id - thread identifier
+ *
+ * SYNOPSIS
+ *      int builtin_thread_id_interpret(string_list_ty *result,
+ *              string_list_ty *args, expr_position_ty *pp,
+ *              opcode_context_ty *ocp);
+ *
+ * DESCRIPTION
+ *      The thread-id function is used to obtain a number which is
+ *      unique to the currently executing thread.  This is useful for
+ *      making unique temporary file names when running in parallel.
+ *
+ * RETURNS
+ *      int; 0 on success, -1 on error.
+ */
+
+func builtin_thread_id_interpret(result *string_list_ty, args *string_list_ty, pp *expr_position_ty, ocp *opcode_context_ty) int {
+	if len(args.strings) != 1 {
+		builtin_error(pp, args, i18n("$name: requires zero arguments"))
+		return -1
+	}
+	builtin_append_string(result, strconv.FormatInt(ocp.thread_id, 10))
+	return 0
+}
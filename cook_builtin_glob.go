@@ -0,0 +1,69 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+/*
+ * NAME
+ *      builtin_glob - file name expansion
+ *
+ * SYNOPSIS
+ *      int builtin_glob_interpret(string_list_ty *result,
+ *              string_list_ty *args, expr_position_ty *pp,
+ *              opcode_context_ty *ocp);
+ *
+ * DESCRIPTION
+ *      The glob function is used to perform wildcard file name
+ *      expansion, in the manner of the shell.  Each argument is a
+ *      pattern; the matching file names are sorted.  Names starting
+ *      with a dot are only matched by patterns which start with a
+ *      dot.  Patterns which match nothing yield nothing.
+ *
+ * RETURNS
+ *      int; 0 on success, -1 on error.
+ */
+
+func builtin_glob_interpret(result *string_list_ty, args *string_list_ty, pp *expr_position_ty, ocp *opcode_context_ty) int {
+	for _, s := range args.strings[1:] {
+		matches, err := filepath.Glob(s.str)
+		if err != nil {
+			scp := sub_context_new()
+			sub_var_set_string(scp, "Name", args.strings[0])
+			sub_var_set_string(scp, "Text", s)
+			error_with_position(pp, scp, i18n("$name: pattern \"$text\" malformed"))
+			sub_context_delete(scp)
+			return -1
+		}
+		dot := strings.HasPrefix(filepath.Base(s.str), ".")
+		sort.Strings(matches)
+		for _, m := range matches {
+			if !dot && strings.HasPrefix(filepath.Base(m), ".") {
+				continue
+			}
+			builtin_append_string(result, m)
+		}
+	}
+	return 0
+}
@@ -0,0 +1,33 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+/*
+ * A builtin function.  The interpret function is given the arguments
+ * (the first of which is the name of the function) and appends its
+ * results to the result list.  The script function, if not NULL, is
+ * used instead when writing a shell script.
+ */
+
+type builtin_ty struct {
+	name      string
+	interpret func(result *string_list_ty, args *string_list_ty, pp *expr_position_ty, ocp *opcode_context_ty) int
+	script    func(result *string_list_ty, args *string_list_ty, pp *expr_position_ty, ocp *opcode_context_ty) int
+}
@@ -0,0 +1,133 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import "strconv"
+
+/*
+ * NAME
+ *      builtin_head - first word
+ *
+ * SYNOPSIS
+ *      int builtin_head_interpret(string_list_ty *result,
+ *              string_list_ty *args, expr_position_ty *pp,
+ *              opcode_context_ty *ocp);
+ *
+ * DESCRIPTION
+ *      The head function is used to obtain the first word of its
+ *      arguments.  The result is empty if there are no arguments.
+ *
+ * RETURNS
+ *      int; 0 on success, -1 on error.
+ */
+
+func builtin_head_interpret(result *string_list_ty, args *string_list_ty, pp *expr_position_ty, ocp *opcode_context_ty) int {
+	if len(args.strings) >= 2 {
+		string_list_append(result, args.strings[1])
+	}
+	return 0
+}
+
+/*
+ * NAME
+ *      builtin_tail - all but the first word
+ *
+ * SYNOPSIS
+ *      int builtin_tail_interpret(string_list_ty *result,
+ *              string_list_ty *args, expr_position_ty *pp,
+ *              opcode_context_ty *ocp);
+ *
+ * DESCRIPTION
+ *      The tail function is used to obtain all but the first word of
+ *      its arguments.
+ *
+ * RETURNS
+ *      int; 0 on success, -1 on error.
+ */
+
+func builtin_tail_interpret(result *string_list_ty, args *string_list_ty, pp *expr_position_ty, ocp *opcode_context_ty) int {
+	if len(args.strings) >= 3 {
+		for _, s := range args.strings[2:] {
+			string_list_append(result, s)
+		}
+	}
+	return 0
+}
+
+/*
+ * NAME
+ *      builtin_count - count words
+ *
+ * SYNOPSIS
+ *      int builtin_count_interpret(string_list_ty *result,
+ *              string_list_ty *args, expr_position_ty *pp,
+ *              opcode_context_ty *ocp);
+ *
+ * DESCRIPTION
+ *      The count function is used to count its arguments.  The result
+ *      is a single word, the number of arguments in decimal.
+ *
+ * RETURNS
+ *      int; 0 on success, -1 on error.
+ */
+
+func builtin_count_interpret(result *string_list_ty, args *string_list_ty, pp *expr_position_ty, ocp *opcode_context_ty) int {
+	builtin_append_string(result, strconv.Itoa(len(args.strings)-1))
+	return 0
+}
+
+/*
+ * NAME
+ *      builtin_word - select a word
+ *
+ * SYNOPSIS
+ *      int builtin_word_interpret(string_list_ty *result,
+ *              string_list_ty *args, expr_position_ty *pp,
+ *              opcode_context_ty *ocp);
+ *
+ * DESCRIPTION
+ *      The word function is of the form
+ *              [word n words...]
+ *      It is used to obtain the n'th word (counting from 1) of the
+ *      words.  The result is empty if there is no such word.
+ *
+ * RETURNS
+ *      int; 0 on success, -1 on error.
+ */
+
+func builtin_word_interpret(result *string_list_ty, args *string_list_ty, pp *expr_position_ty, ocp *opcode_context_ty) int {
+	if len(args.strings) < 2 {
+		builtin_error(pp, args, i18n("$name: requires one or more arguments"))
+		return -1
+	}
+	n, err := strconv.Atoi(args.strings[1].str)
+	if err != nil || n < 1 {
+		scp := sub_context_new()
+		sub_var_set_string(scp, "Name", args.strings[0])
+		sub_var_set_string(scp, "Number", args.strings[1])
+		error_with_position(pp, scp, i18n("$name: the word number \"$number\" is not a positive integer"))
+		sub_context_delete(scp)
+		return -1
+	}
+	if n+1 < len(args.strings) {
+		string_list_append(result, args.strings[n+1])
+	}
+	return 0
+}
@@ -0,0 +1,141 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+/*
+ * NAME
+ *      builtin_fromto - transform words
+ *
+ * SYNOPSIS
+ *      int builtin_fromto_interpret(string_list_ty *result,
+ *              string_list_ty *args, expr_position_ty *pp,
+ *              opcode_context_ty *ocp);
+ *
+ * DESCRIPTION
+ *      The fromto function is used to transform words.  The first
+ *      argument is the pattern to match, the second argument is the
+ *      replacement pattern; the remaining arguments are transformed.
+ *      Words which do not match the pattern are passed through
+ *      unchanged.  Fields of the replacement pattern which the first
+ *      pattern does not set are taken from the enclosing match, such
+ *      as that of the implicit recipe being cooked.
+ *
+ * RETURNS
+ *      int; 0 on success, -1 on error.
+ */
+
+func builtin_fromto_interpret(result *string_list_ty, args *string_list_ty, pp *expr_position_ty, ocp *opcode_context_ty) int {
+	if len(args.strings) < 3 {
+		builtin_error(pp, args, i18n("$name: requires two or more arguments"))
+		return -1
+	}
+	mp := match_new()
+	opcode_context_match_push(ocp, mp)
+	defer func() {
+		opcode_context_match_pop(ocp)
+		match_delete(mp)
+	}()
+	from := args.strings[1]
+	to := args.strings[2]
+	for _, s := range args.strings[3:] {
+		switch match_attempt(mp, from, s, pp) {
+		case -1:
+			return -1
+		case 0:
+			string_list_append(result, s)
+			continue
+		}
+		s2 := match_reconstruct_rhs(mp, to, pp)
+		if s2 == nil {
+			return -1
+		}
+		string_list_append(result, s2)
+		str_free(s2)
+	}
+	return 0
+}
+
+/*
+ * NAME
+ *      builtin_match_mask - select matching words
+ *
+ * SYNOPSIS
+ *      int builtin_match_mask_interpret(string_list_ty *result,
+ *              string_list_ty *args, expr_position_ty *pp,
+ *              opcode_context_ty *ocp);
+ *
+ * DESCRIPTION
+ *      The match_mask function is used to select those words which
+ *      match a pattern.  The first argument is the pattern, the
+ *      remaining arguments are the words to be tested.
+ *
+ * RETURNS
+ *      int; 0 on success, -1 on error.
+ */
+
+func builtin_match_mask_interpret(result *string_list_ty, args *string_list_ty, pp *expr_position_ty, ocp *opcode_context_ty) int {
+	return builtin_match_mask_common(result, args, pp, ocp, true)
+}
+
+/*
+ * NAME
+ *      builtin_filter_out - reject matching words
+ *
+ * SYNOPSIS
+ *      int builtin_filter_out_interpret(string_list_ty *result,
+ *              string_list_ty *args, expr_position_ty *pp,
+ *              opcode_context_ty *ocp);
+ *
+ * DESCRIPTION
+ *      The filter_out function is used to select those words which do
+ *      not match a pattern.  The first argument is the pattern, the
+ *      remaining arguments are the words to be tested.
+ *
+ * RETURNS
+ *      int; 0 on success, -1 on error.
+ */
+
+func builtin_filter_out_interpret(result *string_list_ty, args *string_list_ty, pp *expr_position_ty, ocp *opcode_context_ty) int {
+	return builtin_match_mask_common(result, args, pp, ocp, false)
+}
+
+func builtin_match_mask_common(result *string_list_ty, args *string_list_ty, pp *expr_position_ty, ocp *opcode_context_ty, keep bool) int {
+	if len(args.strings) < 2 {
+		builtin_error(pp, args, i18n("$name: requires one or more arguments"))
+		return -1
+	}
+	mp := match_new()
+	opcode_context_match_push(ocp, mp)
+	defer func() {
+		opcode_context_match_pop(ocp)
+		match_delete(mp)
+	}()
+	pattern := args.strings[1]
+	for _, s := range args.strings[2:] {
+		matched := match_attempt(mp, pattern, s, pp)
+		if matched < 0 {
+			return -1
+		}
+		if (matched != 0) == keep {
+			string_list_append(result, s)
+		}
+	}
+	return 0
+}
@@ -0,0 +1,71 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+/*
+ * The arguments of functions used in the body of an implicit recipe
+ * are not patterns; the fields of the recipe's match are only visible
+ * as the enclosing match of fromto's replacement pattern.  The command
+ * words themselves are patterns.
+ */
+
+func TestBuiltinMatchInImplicitRecipe(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{"fromto", "echo [fromto %.c %.x a.c [need]]", "a.x foo.x"},
+		{"fromto outer field", "echo [fromto %1.c %1-%.x a.c]", "a-foo.x"},
+		{"match_mask", "echo [match_mask %1.c a.c b.h [need]]", "a.c foo.c"},
+		{"filter_out", "echo [filter_out %.h a.c b.h]", "a.c"},
+		{"command words", "echo %.c %0%.d", "foo.c foo.d"},
+		{"escape", "printf '%%s-%%s' a %", "a-foo"},
+		{"function", "echo [f a.c b.h]", "a.x b.h"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cookbook_test_read(t,
+				"function f = { return [fromto %1.c %1.x [arg]]; }\n"+
+					"%0%.out: %0%.c { "+tt.body+" > [target]; }\n",
+				"foo.c")
+			var status int
+			msg := capture_stderr(t, func() {
+				status = cook(test_string_list("foo.out"))
+			})
+			if status != 0 {
+				t.Fatalf("cook = %d, want 0\n%s", status, msg)
+			}
+			data, err := ioutil.ReadFile("foo.out")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := strings.TrimSpace(string(data)); got != tt.want {
+				t.Errorf("%s gave %q, want %q", tt.body, got, tt.want)
+			}
+		})
+	}
+}
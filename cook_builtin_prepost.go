@@ -0,0 +1,110 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+/*
+ * NAME
+ *      builtin_prepost - add prefix and suffix
+ *
+ * SYNOPSIS
+ *      int builtin_prepost_interpret(string_list_ty *result,
+ *              string_list_ty *args, expr_position_ty *pp,
+ *              opcode_context_ty *ocp);
+ *
+ * DESCRIPTION
+ *      The prepost function is of the form
+ *              [prepost prefix suffix words...]
+ *      Each of the words has the prefix prepended and the suffix
+ *      appended.
+ *
+ * RETURNS
+ *      int; 0 on success, -1 on error.
+ */
+
+func builtin_prepost_interpret(result *string_list_ty, args *string_list_ty, pp *expr_position_ty, ocp *opcode_context_ty) int {
+	if len(args.strings) < 3 {
+		builtin_error(pp, args, i18n("$name: requires two or more arguments"))
+		return -1
+	}
+	prefix := args.strings[1].str
+	suffix := args.strings[2].str
+	for _, s := range args.strings[3:] {
+		builtin_append_string(result, prefix+s.str+suffix)
+	}
+	return 0
+}
+
+/*
+ * NAME
+ *      builtin_addprefix - add prefix
+ *
+ * SYNOPSIS
+ *      int builtin_addprefix_interpret(string_list_ty *result,
+ *              string_list_ty *args, expr_position_ty *pp,
+ *              opcode_context_ty *ocp);
+ *
+ * DESCRIPTION
+ *      The addprefix function is of the form
+ *              [addprefix prefix words...]
+ *      Each of the words has the prefix prepended.
+ *
+ * RETURNS
+ *      int; 0 on success, -1 on error.
+ */
+
+func builtin_addprefix_interpret(result *string_list_ty, args *string_list_ty, pp *expr_position_ty, ocp *opcode_context_ty) int {
+	if len(args.strings) < 2 {
+		builtin_error(pp, args, i18n("$name: requires one or more arguments"))
+		return -1
+	}
+	for _, s := range args.strings[2:] {
+		builtin_append_string(result, args.strings[1].str+s.str)
+	}
+	return 0
+}
+
+/*
+ * NAME
+ *      builtin_addsuffix - add suffix
+ *
+ * SYNOPSIS
+ *      int builtin_addsuffix_interpret(string_list_ty *result,
+ *              string_list_ty *args, expr_position_ty *pp,
+ *              opcode_context_ty *ocp);
+ *
+ * DESCRIPTION
+ *      The addsuffix function is of the form
+ *              [addsuffix suffix words...]
+ *      Each of the words has the suffix appended.
+ *
+ * RETURNS
+ *      int; 0 on success, -1 on error.
+ */
+
+func builtin_addsuffix_interpret(result *string_list_ty, args *string_list_ty, pp *expr_position_ty, ocp *opcode_context_ty) int {
+	if len(args.strings) < 2 {
+		builtin_error(pp, args, i18n("$name: requires one or more arguments"))
+		return -1
+	}
+	for _, s := range args.strings[2:] {
+		builtin_append_string(result, s.str+args.strings[1].str)
+	}
+	return 0
+}
@@ -0,0 +1,196 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import "sort"
+
+/*
+ * NAME
+ *      builtin_sort - sort words
+ *
+ * SYNOPSIS
+ *      int builtin_sort_interpret(string_list_ty *result,
+ *              string_list_ty *args, expr_position_ty *pp,
+ *              opcode_context_ty *ocp);
+ *
+ * DESCRIPTION
+ *      The sort function is used to sort its arguments into ascending
+ *      lexicographic order.
+ *
+ * RETURNS
+ *      int; 0 on success, -1 on error.
+ */
+
+func builtin_sort_interpret(result *string_list_ty, args *string_list_ty, pp *expr_position_ty, ocp *opcode_context_ty) int {
+	words := append([]*string_ty(nil), args.strings[1:]...)
+	sort.SliceStable(words, func(i, j int) bool {
+		return words[i].str < words[j].str
+	})
+	for _, s := range words {
+		string_list_append(result, s)
+	}
+	return 0
+}
+
+/*
+ * NAME
+ *      builtin_sort_newest - sort files by age
+ *
+ * SYNOPSIS
+ *      int builtin_sort_newest_interpret(string_list_ty *result,
+ *              string_list_ty *args, expr_position_ty *pp,
+ *              opcode_context_ty *ocp);
+ *
+ * DESCRIPTION
+ *      The sort_newest function is used to sort its arguments, which
+ *      are file names, by last modification time, newest first.  Files
+ *      which do not exist sort last.
+ *
+ * RETURNS
+ *      int; 0 on success, -1 on error.
+ */
+
+func builtin_sort_newest_interpret(result *string_list_ty, args *string_list_ty, pp *expr_position_ty, ocp *opcode_context_ty) int {
+	words := append([]*string_ty(nil), args.strings[1:]...)
+	mtime := make(map[*string_ty]long)
+	for _, s := range words {
+		t := os_mtime_newest(s)
+		if t < 0 {
+			return -1
+		}
+		mtime[s] = t
+	}
+	sort.SliceStable(words, func(i, j int) bool {
+		return mtime[words[i]] > mtime[words[j]]
+	})
+	for _, s := range words {
+		string_list_append(result, s)
+	}
+	return 0
+}
+
+/*
+ * NAME
+ *      builtin_uniq - remove duplicates
+ *
+ * SYNOPSIS
+ *      int builtin_uniq_interpret(string_list_ty *result,
+ *              string_list_ty *args, expr_position_ty *pp,
+ *              opcode_context_ty *ocp);
+ *
+ * DESCRIPTION
+ *      The uniq function is used to remove duplicate words from its
+ *      arguments.  The first occurrence of each word is kept, and the
+ *      order is otherwise unchanged.
+ *
+ * RETURNS
+ *      int; 0 on success, -1 on error.
+ */
+
+func builtin_uniq_interpret(result *string_list_ty, args *string_list_ty, pp *expr_position_ty, ocp *opcode_context_ty) int {
+	seen := make(map[*string_ty]bool)
+	for _, s := range args.strings[1:] {
+		if seen[s] {
+			continue
+		}
+		seen[s] = true
+		string_list_append(result, s)
+	}
+	return 0
+}
+
+/*
+ * NAME
+ *      builtin_stringset - set operations
+ *
+ * SYNOPSIS
+ *      int builtin_stringset_interpret(string_list_ty *result,
+ *              string_list_ty *args, expr_position_ty *pp,
+ *              opcode_context_ty *ocp);
+ *
+ * DESCRIPTION
+ *      The stringset function is used to perform set operations on
+ *      words.  The arguments are words, and operators:
+ *              a b     union
+ *              a * b   intersection
+ *              a - b   difference
+ *      The operators are evaluated left to right, all with the same
+ *      precedence.  The result has no duplicates, and retains the order
+ *      in which the words first appeared.
+ *
+ * RETURNS
+ *      int; 0 on success, -1 on error.
+ */
+
+func builtin_stringset_interpret(result *string_list_ty, args *string_list_ty, pp *expr_position_ty, ocp *opcode_context_ty) int {
+	var set []*string_ty
+	op := "+"
+	var operand []*string_ty
+	apply := func() {
+		in := make(map[*string_ty]bool)
+		for _, s := range operand {
+			in[s] = true
+		}
+		switch op {
+		case "+":
+			have := make(map[*string_ty]bool)
+			for _, s := range set {
+				have[s] = true
+			}
+			for _, s := range operand {
+				if !have[s] {
+					have[s] = true
+					set = append(set, s)
+				}
+			}
+		case "*":
+			var keep []*string_ty
+			for _, s := range set {
+				if in[s] {
+					keep = append(keep, s)
+				}
+			}
+			set = keep
+		case "-":
+			var keep []*string_ty
+			for _, s := range set {
+				if !in[s] {
+					keep = append(keep, s)
+				}
+			}
+			set = keep
+		}
+		operand = nil
+	}
+	for _, s := range args.strings[1:] {
+		switch s.str {
+		case "*", "-":
+			apply()
+			op = s.str
+		default:
+			operand = append(operand, s)
+		}
+	}
+	apply()
+	for _, s := range set {
+		string_list_append(result, s)
+	}
+	return 0
+}
@@ -0,0 +1,89 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import "strings"
+
+/*
+ * NAME
+ *      builtin_split - split strings into word lists
+ *
+ * SYNOPSIS
+ *      int builtin_split_interpret(string_list_ty *result,
+ *              string_list_ty *args, expr_position_ty *pp,
+ *              opcode_context_ty *ocp);
+ *
+ * DESCRIPTION
+ *      The split function is used to split strings into word lists.
+ *      The first argument is the set of separator characters; each of
+ *      the remaining arguments is split at any of them.  Empty words
+ *      are discarded.
+ *
+ * RETURNS
+ *      int; 0 on success, -1 on error.
+ */
+
+func builtin_split_interpret(result *string_list_ty, args *string_list_ty, pp *expr_position_ty, ocp *opcode_context_ty) int {
+	if len(args.strings) < 2 {
+		builtin_error(pp, args, i18n("$name: requires one or more arguments"))
+		return -1
+	}
+	sep := args.strings[1].str
+	for _, s := range args.strings[2:] {
+		words := strings.FieldsFunc(s.str, func(c rune) bool {
+			return strings.ContainsRune(sep, c)
+		})
+		for _, w := range words {
+			builtin_append_string(result, w)
+		}
+	}
+	return 0
+}
+
+/*
+ * NAME
+ *      builtin_unsplit - join word lists into strings
+ *
+ * SYNOPSIS
+ *      int builtin_unsplit_interpret(string_list_ty *result,
+ *              string_list_ty *args, expr_position_ty *pp,
+ *              opcode_context_ty *ocp);
+ *
+ * DESCRIPTION
+ *      The unsplit function is used to join a word list into a single
+ *      string.  The first argument is the separator, the remaining
+ *      arguments are joined with it.
+ *
+ * RETURNS
+ *      int; 0 on success, -1 on error.
+ */
+
+func builtin_unsplit_interpret(result *string_list_ty, args *string_list_ty, pp *expr_position_ty, ocp *opcode_context_ty) int {
+	if len(args.strings) < 2 {
+		builtin_error(pp, args, i18n("$name: requires one or more arguments"))
+		return -1
+	}
+	words := make([]string, 0, len(args.strings)-2)
+	for _, s := range args.strings[2:] {
+		words = append(words, s.str)
+	}
+	builtin_append_string(result, strings.Join(words, args.strings[1].str))
+	return 0
+}
@@ -0,0 +1,108 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"os"
+	"testing"
+)
+
+func TestBuiltin(t *testing.T) {
+	if err := os.Setenv("COOK_TEST_VAR", "hello world"); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Unsetenv("COOK_TEST_VAR")
+	tests := []struct {
+		expr  string
+		files []string
+		want  string
+	}{
+		{expr: "[fromto %.c %.o a.c b.h c.c]", want: "a.o b.h c.o"},
+		{expr: "[match_mask %.c a.c b.h c.c]", want: "a.c c.c"},
+		{expr: "[filter %.c a.c b.h]", want: "a.c"},
+		{expr: "[filter_out %.c a.c b.h]", want: "b.h"},
+		{expr: "[patsubst %.c %.o a.c b.h]", want: "a.o b.h"},
+		{expr: "[dirname a/b/c.c d.c]", want: "a/b ."},
+		{expr: "[basename a/b/c.c d]", want: "a/b/c d"},
+		{expr: "[entryname a/b/c.c d.c]", want: "c.c d.c"},
+		{expr: "[suffix a/b.c d]", want: ".c"},
+		{expr: "[prepost < > a b]", want: "<a> <b>"},
+		{expr: "[addprefix x a b]", want: "xa xb"},
+		{expr: "[addsuffix x a b]", want: "ax bx"},
+		{expr: "[head a b c]", want: "a"},
+		{expr: "[tail a b c]", want: "b c"},
+		{expr: "[count a b c]", want: "3"},
+		{expr: "[sort c a b]", want: "a b c"},
+		{expr: "[uniq a b a c b]", want: "a b c"},
+		{expr: "[stringset a b c - b]", want: "a c"},
+		{expr: "[words a b c]", want: "3"},
+		{expr: "[word 2 a b c]", want: "b"},
+		{expr: "[firstword a b c]", want: "a"},
+		{expr: "[split \":\" \"a:b::c\"]", want: "a b c"},
+		{expr: "[unsplit \":\" a b c]", want: "a:b:c"},
+		{expr: "[catenate a b c]", want: "abc"},
+		{expr: "[subst a x banana]", want: "bxnxnx"},
+		{expr: "[findstring an banana]", want: "an"},
+		{expr: "[strip a \"\" b]", want: "a b"},
+		{expr: "[upcase abc]", want: "ABC"},
+		{expr: "[downcase ABC]", want: "abc"},
+		{expr: "[in b a b c]", want: "1"},
+		{expr: "[in d a b c]", want: `""`},
+		{expr: "[and a b]", want: "1"},
+		{expr: "[and a \"\"]", want: `""`},
+		{expr: "[or \"\" b]", want: "1"},
+		{expr: "[not \"\"]", want: "1"},
+		{expr: "[if a then b else c]", want: "b"},
+		{expr: "[if \"\" then b else c]", want: "c"},
+		{expr: "[expr 1 + 2 * 3]", want: "7"},
+		{expr: "[defined COOK_TEST_UNDEFINED]", want: `""`},
+		{expr: "[getenv COOK_TEST_VAR]", want: "hello world"},
+		{expr: "[collect echo a b]", want: "a b"},
+		{expr: "[execute true]", want: "1"},
+		{expr: "[glob *.c]", files: []string{"b.c", "a.c", "c.h"}, want: "a.c b.c"},
+		{expr: "[exists a.c]", files: []string{"a.c"}, want: "1"},
+		{expr: "[exists a.c b.c]", files: []string{"a.c"}, want: `""`},
+		{expr: "[resolve a.c]", files: []string{"a.c"}, want: "a.c"},
+		{expr: "[quote a \"b c\"]", want: "a 'b c'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			cookbook_test_read(t, "r = "+tt.expr+";\n", tt.files...)
+			got := id_variable_query("r")
+			if got == nil {
+				t.Fatalf("r is not set")
+			}
+			s := ""
+			for j, w := range got.strings {
+				if j > 0 {
+					s += " "
+				}
+				if w.str == "" {
+					s += "\"\""
+				} else {
+					s += w.str
+				}
+			}
+			if s != tt.want {
+				t.Errorf("%s = %q, want %q", tt.expr, s, tt.want)
+			}
+		})
+	}
+}
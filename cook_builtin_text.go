@@ -0,0 +1,236 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import "strings"
+
+/*
+ * NAME
+ *      builtin_catenate - join words
+ *
+ * SYNOPSIS
+ *      int builtin_catenate_interpret(string_list_ty *result,
+ *              string_list_ty *args, expr_position_ty *pp,
+ *              opcode_context_ty *ocp);
+ *
+ * DESCRIPTION
+ *      The catenate function is used to join all of its arguments into
+ *      a single word, with nothing between them.
+ *
+ * RETURNS
+ *      int; 0 on success, -1 on error.
+ */
+
+func builtin_catenate_interpret(result *string_list_ty, args *string_list_ty, pp *expr_position_ty, ocp *opcode_context_ty) int {
+	var sb strings.Builder
+	for _, s := range args.strings[1:] {
+		sb.WriteString(s.str)
+	}
+	builtin_append_string(result, sb.String())
+	return 0
+}
+
+/*
+ * NAME
+ *      builtin_strip - discard empty words
+ *
+ * SYNOPSIS
+ *      int builtin_strip_interpret(string_list_ty *result,
+ *              string_list_ty *args, expr_position_ty *pp,
+ *              opcode_context_ty *ocp);
+ *
+ * DESCRIPTION
+ *      The strip function is used to discard empty words from its
+ *      arguments; the others are returned unchanged.
+ *
+ * RETURNS
+ *      int; 0 on success, -1 on error.
+ */
+
+func builtin_strip_interpret(result *string_list_ty, args *string_list_ty, pp *expr_position_ty, ocp *opcode_context_ty) int {
+	for _, s := range args.strings[1:] {
+		if len(s.str) != 0 {
+			string_list_append(result, s)
+		}
+	}
+	return 0
+}
+
+/*
+ * NAME
+ *      builtin_subst - substitute text
+ *
+ * SYNOPSIS
+ *      int builtin_subst_interpret(string_list_ty *result,
+ *              string_list_ty *args, expr_position_ty *pp,
+ *              opcode_context_ty *ocp);
+ *
+ * DESCRIPTION
+ *      The subst function is of the form
+ *              [subst from to words...]
+ *      Each occurrence of the text "from" in each of the words is
+ *      replaced by the text "to".
+ *
+ * RETURNS
+ *      int; 0 on success, -1 on error.
+ */
+
+func builtin_subst_interpret(result *string_list_ty, args *string_list_ty, pp *expr_position_ty, ocp *opcode_context_ty) int {
+	if len(args.strings) < 3 {
+		builtin_error(pp, args, i18n("$name: requires two or more arguments"))
+		return -1
+	}
+	from := args.strings[1].str
+	to := args.strings[2].str
+	for _, s := range args.strings[3:] {
+		if from == "" {
+			string_list_append(result, s)
+			continue
+		}
+		builtin_append_string(result, strings.ReplaceAll(s.str, from, to))
+	}
+	return 0
+}
+
+/*
+ * NAME
+ *      builtin_findstring - search for text
+ *
+ * SYNOPSIS
+ *      int builtin_findstring_interpret(string_list_ty *result,
+ *              string_list_ty *args, expr_position_ty *pp,
+ *              opcode_context_ty *ocp);
+ *
+ * DESCRIPTION
+ *      The findstring function is of the form
+ *              [findstring text words...]
+ *      The result is the text if it occurs in any of the words, and
+ *      empty otherwise.
+ *
+ * RETURNS
+ *      int; 0 on success, -1 on error.
+ */
+
+func builtin_findstring_interpret(result *string_list_ty, args *string_list_ty, pp *expr_position_ty, ocp *opcode_context_ty) int {
+	if len(args.strings) < 2 {
+		builtin_error(pp, args, i18n("$name: requires one or more arguments"))
+		return -1
+	}
+	for _, s := range args.strings[2:] {
+		if strings.Contains(s.str, args.strings[1].str) {
+			string_list_append(result, args.strings[1])
+			break
+		}
+	}
+	return 0
+}
+
+/*
+ * NAME
+ *      builtin_upcase - upper case
+ *
+ * SYNOPSIS
+ *      int builtin_upcase_interpret(string_list_ty *result,
+ *              string_list_ty *args, expr_position_ty *pp,
+ *              opcode_context_ty *ocp);
+ *
+ * DESCRIPTION
+ *      The upcase function is used to convert each of its arguments to
+ *      upper case.
+ *
+ * RETURNS
+ *      int; 0 on success, -1 on error.
+ */
+
+func builtin_upcase_interpret(result *string_list_ty, args *string_list_ty, pp *expr_position_ty, ocp *opcode_context_ty) int {
+	for _, s := range args.strings[1:] {
+		builtin_append_string(result, strings.ToUpper(s.str))
+	}
+	return 0
+}
+
+/*
+ * NAME
+ *      builtin_downcase - lower case
+ *
+ * SYNOPSIS
+ *      int builtin_downcase_interpret(string_list_ty *result,
+ *              string_list_ty *args, expr_position_ty *pp,
+ *              opcode_context_ty *ocp);
+ *
+ * DESCRIPTION
+ *      The downcase function is used to convert each of its arguments
+ *      to lower case.
+ *
+ * RETURNS
+ *      int; 0 on success, -1 on error.
+ */
+
+func builtin_downcase_interpret(result *string_list_ty, args *string_list_ty, pp *expr_position_ty, ocp *opcode_context_ty) int {
+	for _, s := range args.strings[1:] {
+		builtin_append_string(result, strings.ToLower(s.str))
+	}
+	return 0
+}
+
+/*
+ * NAME
+ *      builtin_quote - quote for the shell
+ *
+ * SYNOPSIS
+ *      int builtin_quote_interpret(string_list_ty *result,
+ *              string_list_ty *args, expr_position_ty *pp,
+ *              opcode_context_ty *ocp);
+ *
+ * DESCRIPTION
+ *      The quote function is used to quote each of its arguments, if
+ *      necessary, so that the shell will see each one as a single word
+ *      with no special characters.
+ *
+ * RETURNS
+ *      int; 0 on success, -1 on error.
+ */
+
+func builtin_quote_interpret(result *string_list_ty, args *string_list_ty, pp *expr_position_ty, ocp *opcode_context_ty) int {
+	for _, s := range args.strings[1:] {
+		builtin_append_string(result, builtin_quote_word(s.str))
+	}
+	return 0
+}
+
+/*
+ * NAME
+ *      builtin_quote_word
+ *
+ * SYNOPSIS
+ *      char *builtin_quote_word(char *);
+ *
+ * DESCRIPTION
+ *      The builtin_quote_word function is used to quote a single word
+ *      for the shell.  Words which need no quoting are returned
+ *      unchanged, others are enclosed in single quotes.
+ */
+
+func builtin_quote_word(s string) string {
+	if s != "" && !strings.ContainsAny(s, " \t\n\"'\\$`;&|<>()[]{}*?!~#") {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", "'\\''") + "'"
+}
@@ -0,0 +1,153 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import "fmt"
+
+/*
+ * NAME
+ *      cascade_new - create a cascade
+ *
+ * SYNOPSIS
+ *      cascade_ty *cascade_new(string_list_ty *target,
+ *              string_list_ty *ingredient, expr_position_ty *pp);
+ *
+ * DESCRIPTION
+ *      The cascade_new function is used to create a new cascade.  The
+ *      string lists are copied.
+ */
+
+func cascade_new(target, ingredient *string_list_ty, pp *expr_position_ty) *cascade_ty {
+	cp := &cascade_ty{}
+	string_list_copy_constructor(&cp.target, target)
+	string_list_copy_constructor(&cp.ingredient, ingredient)
+	expr_position_copy_constructor(&cp.pos, pp)
+	return cp
+}
+
+/*
+ * NAME
+ *      cascade_delete - release a cascade
+ *
+ * SYNOPSIS
+ *      void cascade_delete(cascade_ty *);
+ *
+ * DESCRIPTION
+ *      The cascade_delete function is used to release the resources
+ *      held by a cascade.
+ */
+
+func cascade_delete(cp *cascade_ty) {
+	string_list_destructor(&cp.target)
+	string_list_destructor(&cp.ingredient)
+	expr_position_destructor(&cp.pos)
+}
+
+/*
+ * NAME
+ *      cascade_list_append - remember a cascade
+ *
+ * SYNOPSIS
+ *      void cascade_list_append(cascade_list_ty *, cascade_ty *);
+ *
+ * DESCRIPTION
+ *      The cascade_list_append function is used to add a cascade to a
+ *      cascade list.  The list takes over the cascade.
+ */
+
+func cascade_list_append(clp *cascade_list_ty, cp *cascade_ty) {
+	clp.cascade = append(clp.cascade, cp)
+	if clp.index == nil {
+		clp.index = make(map[*string_ty][]*cascade_ty)
+	}
+	for _, s := range cp.target.strings {
+		clp.index[s] = append(clp.index[s], cp)
+	}
+}
+
+/*
+ * NAME
+ *      cascade_list_destructor - forget all cascades
+ *
+ * SYNOPSIS
+ *      void cascade_list_destructor(cascade_list_ty *);
+ *
+ * DESCRIPTION
+ *      The cascade_list_destructor function is used to release the
+ *      cascades held by a cascade list.
+ */
+
+func cascade_list_destructor(clp *cascade_list_ty) {
+	for _, cp := range clp.cascade {
+		cascade_delete(cp)
+	}
+	clp.cascade = nil
+	clp.index = nil
+}
+
+/*
+ * NAME
+ *      cascade_list_find - cascaded ingredients
+ *
+ * SYNOPSIS
+ *      void cascade_list_find(cascade_list_ty *, string_ty *name,
+ *              string_list_ty *result);
+ *
+ * DESCRIPTION
+ *      The cascade_list_find function is used to find the ingredients
+ *      cascaded from the given file.  They are appended to the result,
+ *      unless already present.
+ */
+
+func cascade_list_find(clp *cascade_list_ty, name *string_ty, result *string_list_ty) {
+	for _, cp := range clp.index[name] {
+		for _, s := range cp.ingredient.strings {
+			if !string_list_member(result, s) {
+				string_list_append(result, s)
+			}
+		}
+	}
+}
+
+/*
+ * NAME
+ *      cascade_disassemble - print a cascade
+ *
+ * SYNOPSIS
+ *      void cascade_disassemble(cascade_ty *);
+ *
+ * DESCRIPTION
+ *      The cascade_disassemble function is used to print a human
+ *      readable listing of a cascade.
+ */
+
+func cascade_disassemble(cp *cascade_ty) {
+	fmt.Printf("cascade")
+	if cp.pos.pos_name != nil {
+		fmt.Printf("  # %s: %d", cp.pos.pos_name.str, cp.pos.pos_line)
+	}
+	fmt.Printf("\n")
+	s := wl2str(&cp.target, 0, len(cp.target.strings)-1, "")
+	fmt.Printf("    target: %s\n", s.str)
+	str_free(s)
+	s = wl2str(&cp.ingredient, 0, len(cp.ingredient.strings)-1, "")
+	fmt.Printf("    ingredients: %s\n", s.str)
+	str_free(s)
+}
@@ -0,0 +1,42 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+/*
+ * A cascade, from a "cascade targets = ingredients;" statement.
+ * Whenever one of the targets is an ingredient of a recipe, the
+ * cascaded ingredients are implicitly ingredients too.
+ */
+
+type cascade_ty struct {
+	target     string_list_ty
+	ingredient string_list_ty
+	pos        expr_position_ty
+}
+
+/*
+ * The cascades are kept in definition order, and indexed by target.
+ * As the file names are interned, the string pointer is the key.
+ */
+
+type cascade_list_ty struct {
+	cascade []*cascade_ty
+	index   map[*string_ty][]*cascade_ty
+}
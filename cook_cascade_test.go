@@ -0,0 +1,104 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"testing"
+)
+
+func TestCascade(t *testing.T) {
+	cascades := "cascade a.c = a.h;\n" +
+		"cascade a.h = b.h c.h;\n" +
+		"cascade c.h = a.c d.h;\n"
+	tests := []struct {
+		name string
+		book string
+		want string /* ingredients, and their edge types */
+	}{
+		{
+			name: "cascade of cascade",
+			book: cascades + "a.o: a.c { cc a.c; }\n",
+			want: "a.c:0 a.h:0 b.h:0 c.h:0 d.h:0",
+		},
+		{
+			name: "edge type is inherited",
+			book: cascades + "a.o: a.c (weak) { cc a.c; }\n",
+			want: "a.c:2 a.h:2 b.h:2 c.h:2 d.h:2",
+		},
+		{
+			name: "no cascade",
+			book: cascades + "a.o: a.c set no-cascade { cc a.c; }\n",
+			want: "a.c:0",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cookbook_test_read(t, tt.book, "a.c", "a.h", "b.h", "c.h", "d.h")
+			gp := graph_new()
+			defer graph_delete(gp)
+			target := str_from_string("a.o")
+			if msg := capture_stderr(t, func() {
+				if got := graph_build(gp, target, nil, 0); got != graph_build_status_success {
+					t.Errorf("graph_build = %d, want %d", got, graph_build_status_success)
+				}
+			}); msg != "" {
+				t.Fatal(msg)
+			}
+			gfp := graph_file_find(gp, target)
+			if len(gfp.input.recipe) != 1 {
+				t.Fatalf("%d recipes, want 1", len(gfp.input.recipe))
+			}
+			got := ""
+			for _, item := range gfp.input.recipe[0].input.item {
+				if got != "" {
+					got += " "
+				}
+				got += fmt.Sprintf("%s:%d", item.file.filename.str, item.edge_type)
+			}
+			if got != tt.want {
+				t.Errorf("ingredients = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+/*
+ * A change to a file cascaded from a cascaded file makes the target
+ * out of date.
+ */
+
+func TestCascadeOutOfDate(t *testing.T) {
+	book := "cascade a.c = a.h;\n" +
+		"cascade a.h = b.h;\n" +
+		"a.o: a.c { touch [target] ran; }\n"
+	cookbook_test_read(t, book, "a.c", "a.h", "b.h", "a.o")
+	file_test_age(t, "a.c", 300)
+	file_test_age(t, "a.h", 300)
+	file_test_age(t, "a.o", 200)
+	file_test_age(t, "b.h", 100)
+	if status, msg := cook_test_run(t, "a.o"); status != 0 {
+		t.Fatalf("cook = %d\n%s", status, msg)
+	}
+	if _, err := os.Stat("ran"); err != nil {
+		t.Errorf("a.o was not cooked")
+	}
+}
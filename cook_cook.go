@@ -0,0 +1,294 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+/*
+ * The recipes read from the cookbook.  Explicit recipes name their
+ * targets literally, implicit recipes use patterns.
+ */
+var explicit recipe_list_ty
+var implicit recipe_list_ty
+
+/*
+ * The cascades read from the cookbook.
+ */
+var cascades cascade_list_ty
+
+/*
+ * The interior and leaf files of the most recently built dependency
+ * graph, for the [interior_files] and [leaf_files] functions.
+ */
+var cook_interior_files string_list_ty
+var cook_leaf_files string_list_ty
+
+/*
+ * NAME
+ *      cook_explicit_append - add an explicit recipe
+ *
+ * SYNOPSIS
+ *      void cook_explicit_append(recipe_ty *);
+ *
+ * DESCRIPTION
+ *      The cook_explicit_append function is used to remember an
+ *      explicit recipe.  The recipe is copied.
+ */
+
+func cook_explicit_append(rp *recipe_ty) {
+	recipe_list_append(&explicit, rp)
+}
+
+/*
+ * NAME
+ *      cook_implicit_append - add an implicit recipe
+ *
+ * SYNOPSIS
+ *      void cook_implicit_append(recipe_ty *);
+ *
+ * DESCRIPTION
+ *      The cook_implicit_append function is used to remember an
+ *      implicit recipe.  The recipe is copied.
+ */
+
+func cook_implicit_append(rp *recipe_ty) {
+	recipe_list_append(&implicit, rp)
+}
+
+/*
+ * NAME
+ *      cook_cascade_append - add a cascade
+ *
+ * SYNOPSIS
+ *      void cook_cascade_append(cascade_ty *);
+ *
+ * DESCRIPTION
+ *      The cook_cascade_append function is used to remember a cascade.
+ *      The cascade is taken over.
+ */
+
+func cook_cascade_append(cp *cascade_ty) {
+	cascade_list_append(&cascades, cp)
+}
+
+/*
+ * NAME
+ *      cook_cascade_find - cascaded ingredients
+ *
+ * SYNOPSIS
+ *      void cook_cascade_find(string_ty *name, string_list_ty *result);
+ *
+ * DESCRIPTION
+ *      The cook_cascade_find function is used to find the ingredients
+ *      cascaded from the given file, see cascade_list_find.
+ */
+
+func cook_cascade_find(name *string_ty, result *string_list_ty) {
+	cascade_list_find(&cascades, name, result)
+}
+
+/*
+ * NAME
+ *      cook_reset - forget all recipes
+ *
+ * SYNOPSIS
+ *      void cook_reset(void);
+ *
+ * DESCRIPTION
+ *      The cook_reset function is used to forget all of the recipes
+ *      and cascades, usually in preparation for re-reading the
+ *      cookbook.
+ */
+
+func cook_reset() {
+	recipe_list_destructor(&explicit)
+	recipe_list_destructor(&implicit)
+	cascade_list_destructor(&cascades)
+	string_list_destructor(&cook_interior_files)
+	string_list_destructor(&cook_leaf_files)
+}
+
+/*
+ * NAME
+ *      cook_disassemble - print all recipes
+ *
+ * SYNOPSIS
+ *      void cook_disassemble(void);
+ *
+ * DESCRIPTION
+ *      The cook_disassemble function is used to print a human readable
+ *      listing of all of the recipes, explicit recipes first, in the
+ *      order they were defined, followed by the cascades.
+ */
+
+func cook_disassemble() {
+	for _, rp := range explicit.recipe {
+		recipe_disassemble(rp)
+	}
+	for _, rp := range implicit.recipe {
+		recipe_disassemble(rp)
+	}
+	for _, cp := range cascades.cascade {
+		cascade_disassemble(cp)
+	}
+}
+
+/*
+ * NAME
+ *      cook_default_targets - the default targets
+ *
+ * SYNOPSIS
+ *      void cook_default_targets(string_list_ty *result);
+ *
+ * DESCRIPTION
+ *      The cook_default_targets function is used to find the targets
+ *      to cook when none are named on the command line.  They are the
+ *      targets of the first explicit recipe with the default flag set,
+ *      or, if there is none, the targets of the first explicit recipe.
+ */
+
+func cook_default_targets(result *string_list_ty) {
+	for _, rp := range explicit.recipe {
+		if flag_query(rp.flags, RF_DEFAULT) {
+			string_list_append_list(result, rp.target)
+			return
+		}
+	}
+	if len(explicit.recipe) > 0 {
+		string_list_append_list(result, explicit.recipe[0].target)
+	}
+}
+
+/*
+ * NAME
+ *      cook - construct files
+ *
+ * SYNOPSIS
+ *      int cook(string_list_ty *targets);
+ *
+ * DESCRIPTION
+ *      The cook function is used to build the dependency graph of the
+ *      given targets, and then walk it, running the recipes of any
+ *      targets which are out of date.  The interior and leaf files of
+ *      the graph are remembered for the [interior_files] and
+ *      [leaf_files] functions.
+ *
+ * RETURNS
+ *      int; 0 on success, 1 on error (already reported).
+ */
+
+func cook(targets *string_list_ty) int {
+	if len(targets.strings) == 0 {
+		error_intl(nil, i18n("no targets specified"))
+		return 1
+	}
+	gp := graph_new()
+	status := graph_build_list(gp, targets, nil)
+	if option.o_statistics {
+		graph_print_statistics(gp)
+	}
+	string_list_destructor(&cook_interior_files)
+	string_list_destructor(&cook_leaf_files)
+	graph_interior_and_leaf_files(gp, &cook_interior_files, &cook_leaf_files)
+	retval := 0
+	if status != graph_build_status_success {
+		retval = 1
+	} else {
+		switch graph_walk(gp) {
+		case graph_walk_status_error, graph_walk_status_interrupted:
+			retval = 1
+		}
+	}
+	graph_delete(gp)
+	fingerprint_write_all()
+	return retval
+}
+
+/*
+ * NAME
+ *      cook_auto - bring include-cooked files up to date
+ *
+ * SYNOPSIS
+ *      int cook_auto(string_list_ty *files, string_list_ty *nowarn);
+ *
+ * DESCRIPTION
+ *      The cook_auto function is used, after the cookbook has been
+ *      read, to cook the files named by #include-cooked directives,
+ *      which may be targets of the cookbook (dependency files made by
+ *      c_incl, for example).  If any of them changed, the cookbook must
+ *      be read again.  Unless the include-cooked-warning option has
+ *      been turned off, or they were all named by
+ *      #include-cooked-nowarn directives, a warning is printed.
+ *
+ * RETURNS
+ *      int; 1 if the cookbook must be read again, 0 if not, -1 on error
+ *      (already reported).
+ */
+
+func cook_auto(files, nowarn *string_list_ty) int {
+	if len(files.strings) == 0 || !option_test(OPTION_INCLUDE_COOKED) {
+		return 0
+	}
+	trace("cook_auto()\n{\n")
+	defer trace("}\n")
+
+	before := make([]long, len(files.strings))
+	for j, s := range files.strings {
+		before[j] = os_mtime_newest(s)
+		if before[j] < 0 {
+			return -1
+		}
+	}
+
+	gp := graph_new()
+	status := graph_build_list(gp, files, nil)
+	retval := 0
+	if status != graph_build_status_success {
+		retval = -1
+	} else {
+		switch graph_walk(gp) {
+		case graph_walk_status_error, graph_walk_status_interrupted:
+			retval = -1
+		}
+	}
+	graph_delete(gp)
+	fingerprint_write_all()
+	if retval < 0 {
+		return -1
+	}
+
+	warn := false
+	for j, s := range files.strings {
+		stat_cache_clear(s)
+		after := os_mtime_newest(s)
+		if after < 0 {
+			return -1
+		}
+		if after == before[j] {
+			continue
+		}
+		retval = 1
+		if !string_list_member(nowarn, s) {
+			warn = true
+		}
+	}
+	if warn && option_test(OPTION_INCLUDE_COOKED_WARNING) {
+		error_intl(nil, i18n("cookbook changed, rereading"))
+	}
+	return retval
+}
@@ -0,0 +1,74 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
+)
+
+/*
+ * The desist flag is set when the user interrupts cook.  It is tested
+ * by the interpreter between opcodes, so that cook stops at a safe
+ * place rather than in the middle of something.
+ */
+var desist int32
+
+/*
+ * NAME
+ *      desist_enable - catch interrupts
+ *
+ * SYNOPSIS
+ *      void desist_enable(void);
+ *
+ * DESCRIPTION
+ *      The desist_enable function is used to arrange for the interrupt,
+ *      hangup and terminate signals to set the desist flag, rather than
+ *      terminate cook immediately.  Child processes receive the signals
+ *      too, and are expected to die of them.
+ */
+
+func desist_enable() {
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, os.Interrupt, syscall.SIGHUP, syscall.SIGTERM)
+	go func() {
+		for range c {
+			atomic.StoreInt32(&desist, 1)
+		}
+	}()
+}
+
+/*
+ * NAME
+ *      desist_requested - test for interrupts
+ *
+ * SYNOPSIS
+ *      int desist_requested(void);
+ *
+ * DESCRIPTION
+ *      The desist_requested function is used to test whether the user
+ *      has interrupted cook.
+ */
+
+func desist_requested() bool {
+	return atomic.LoadInt32(&desist) != 0
+}
@@ -0,0 +1,266 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import "fmt"
+
+/*
+ * NAME
+ *      expr_instance_new - create an expression node
+ *
+ * SYNOPSIS
+ *      expr_ty *expr_instance_new(expr_method_ty *, void *this,
+ *              expr_position_ty *);
+ *
+ * DESCRIPTION
+ *      The expr_instance_new function is used to create a new
+ *      expression node of the class described by the method.  The
+ *      derived instance is remembered in the "this" field.
+ *
+ * RETURNS
+ *      expr_ty *; use expr_delete when finished with it.
+ */
+
+func expr_instance_new(mp *expr_method_ty, this interface{}, pp *expr_position_ty) *expr_ty {
+	trace(fmt.Sprintf("expr_instance_new(mp = %q)\n{\n", mp.name))
+	ep := &expr_ty{
+		method:          mp,
+		this:            this,
+		reference_count: 1,
+	}
+	if pp != nil {
+		ep.e_position = *pp
+		if pp.pos_name != nil {
+			ep.e_position.pos_name = str_copy(pp.pos_name)
+		}
+	}
+	trace(fmt.Sprintf("return %p;\n", ep))
+	trace("}\n")
+	return ep
+}
+
+/*
+ * NAME
+ *      expr_copy - copy an expression
+ *
+ * SYNOPSIS
+ *      expr_ty *expr_copy(expr_ty *);
+ *
+ * DESCRIPTION
+ *      The expr_copy function is used to make a copy of an expression
+ *      tree.  Expressions are never modified once built, so this is
+ *      simply a reference count increment.
+ */
+
+func expr_copy(ep *expr_ty) *expr_ty {
+	ep.reference_count++
+	return ep
+}
+
+/*
+ * NAME
+ *      expr_delete - release an expression
+ *
+ * SYNOPSIS
+ *      void expr_delete(expr_ty *);
+ *
+ * DESCRIPTION
+ *      The expr_delete function is used to release an expression tree
+ *      when it is finished with.
+ */
+
+func expr_delete(ep *expr_ty) {
+	assert(ep.reference_count > 0, "ep.reference_count > 0")
+	ep.reference_count--
+	if ep.reference_count > 0 {
+		return
+	}
+	if ep.method.destructor != nil {
+		ep.method.destructor(ep)
+	}
+	if ep.e_position.pos_name != nil {
+		str_free(ep.e_position.pos_name)
+	}
+	ep.method = nil /* paranoia */
+	ep.this = nil
+}
+
+/*
+ * NAME
+ *      expr_list_constructor - prepare an expression list
+ *
+ * SYNOPSIS
+ *      void expr_list_constructor(expr_list_ty *);
+ *
+ * DESCRIPTION
+ *      The expr_list_constructor function is used to prepare an
+ *      expression list for use.  It will be empty.
+ */
+
+func expr_list_constructor(elp *expr_list_ty) {
+	elp.el_expr = nil
+}
+
+/*
+ * NAME
+ *      expr_list_new - create an expression list
+ *
+ * SYNOPSIS
+ *      expr_list_ty *expr_list_new(void);
+ *
+ * DESCRIPTION
+ *      The expr_list_new function is used to create a new, empty,
+ *      expression list in dynamic memory.
+ */
+
+func expr_list_new() *expr_list_ty {
+	elp := &expr_list_ty{}
+	expr_list_constructor(elp)
+	return elp
+}
+
+/*
+ * NAME
+ *      expr_list_append - append to an expression list
+ *
+ * SYNOPSIS
+ *      void expr_list_append(expr_list_ty *, expr_ty *);
+ *
+ * DESCRIPTION
+ *      The expr_list_append function is used to append an expression
+ *      to an expression list.
+ *
+ * CAVEAT
+ *      The expression is copied.
+ */
+
+func expr_list_append(elp *expr_list_ty, ep *expr_ty) {
+	elp.el_expr = append(elp.el_expr, expr_copy(ep))
+}
+
+/*
+ * NAME
+ *      expr_list_destructor - release an expression list
+ *
+ * SYNOPSIS
+ *      void expr_list_destructor(expr_list_ty *);
+ *
+ * DESCRIPTION
+ *      The expr_list_destructor function is used to release the
+ *      expressions held by an expression list.
+ */
+
+func expr_list_destructor(elp *expr_list_ty) {
+	for _, ep := range elp.el_expr {
+		expr_delete(ep)
+	}
+	elp.el_expr = nil
+}
+
+/*
+ * NAME
+ *      expr_list_delete - release an expression list
+ *
+ * SYNOPSIS
+ *      void expr_list_delete(expr_list_ty *);
+ *
+ * DESCRIPTION
+ *      The expr_list_delete function is used to release an expression
+ *      list in dynamic memory.  It is safe to pass NULL.
+ */
+
+func expr_list_delete(elp *expr_list_ty) {
+	if elp == nil {
+		return
+	}
+	expr_list_destructor(elp)
+}
+
+/*
+ * NAME
+ *      expr_code - generate opcodes for an expression
+ *
+ * SYNOPSIS
+ *      void expr_code(expr_ty *, opcode_list_ty *);
+ *
+ * DESCRIPTION
+ *      The expr_code function is used to append to the given opcode
+ *      list the opcodes which evaluate the expression.  When executed,
+ *      the value of the expression is appended to the top-most string
+ *      list of the value stack.
+ */
+
+func expr_code(ep *expr_ty, olp *opcode_list_ty) {
+	trace(fmt.Sprintf("expr_code(ep = %p, olp = %p)\n{\n", ep, olp))
+	assert(ep.method.code != nil, "ep.method.code != nil")
+	ep.method.code(ep, olp)
+	trace("}\n")
+}
+
+/*
+ * NAME
+ *      expr_list_code - generate opcodes for an expression list
+ *
+ * SYNOPSIS
+ *      void expr_list_code(expr_list_ty *, opcode_list_ty *);
+ *
+ * DESCRIPTION
+ *      The expr_list_code function is used to append to the given
+ *      opcode list the opcodes which evaluate each of the expressions
+ *      in turn.  It is safe to pass NULL.
+ */
+
+func expr_list_code(elp *expr_list_ty, olp *opcode_list_ty) {
+	if elp == nil {
+		return
+	}
+	for _, ep := range elp.el_expr {
+		expr_code(ep, olp)
+	}
+}
+
+/*
+ * NAME
+ *      expr_list_compile - compile an expression list
+ *
+ * SYNOPSIS
+ *      opcode_list_ty *expr_list_compile(expr_list_ty *);
+ *
+ * DESCRIPTION
+ *      The expr_list_compile function is used to compile an expression
+ *      list into an opcode list of its own.  When executed, the opcode
+ *      list returns the value of the expressions.  It is safe to pass
+ *      NULL, in which case NULL is returned.
+ *
+ * RETURNS
+ *      opcode_list_ty *; use opcode_list_delete when you are done with
+ *      it.
+ */
+
+func expr_list_compile(elp *expr_list_ty) *opcode_list_ty {
+	if elp == nil {
+		return nil
+	}
+	olp := opcode_list_new()
+	opcode_list_append(olp, opcode_push_new())
+	expr_list_code(elp, olp)
+	opcode_list_append(olp, opcode_return_new())
+	return olp
+}
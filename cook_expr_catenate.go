@@ -0,0 +1,87 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+/*
+ * A catenate expression is formed from adjacent words and function
+ * calls with no white space between them, e.g. "[dirname x]/y.o".
+ */
+
+type expr_catenate_ty struct {
+	parts expr_list_ty
+}
+
+func expr_catenate_destructor(ep *expr_ty) {
+	this, ok := ep.this.(*expr_catenate_ty)
+	assert(ok, "ep.this.(*expr_catenate_ty)")
+	expr_list_destructor(&this.parts)
+}
+
+/*
+ * NAME
+ *      expr_catenate_code
+ *
+ * SYNOPSIS
+ *      void expr_catenate_code(expr_ty *, opcode_list_ty *);
+ *
+ * DESCRIPTION
+ *      The expr_catenate_code function is used to generate the opcodes
+ *      for a catenation: each part is evaluated into a string list of
+ *      its own, and then they are joined.
+ */
+
+func expr_catenate_code(ep *expr_ty, olp *opcode_list_ty) {
+	this, ok := ep.this.(*expr_catenate_ty)
+	assert(ok, "ep.this.(*expr_catenate_ty)")
+	for _, part := range this.parts.el_expr {
+		opcode_list_append(olp, opcode_push_new())
+		expr_code(part, olp)
+	}
+	opcode_list_append(olp, opcode_catenate_new(size_t(len(this.parts.el_expr))))
+}
+
+var expr_catenate_method = expr_method_ty{
+	name:       "catenate",
+	destructor: expr_catenate_destructor,
+	code:       expr_catenate_code,
+}
+
+/*
+ * NAME
+ *      expr_catenate_new - create a catenate expression
+ *
+ * SYNOPSIS
+ *      expr_ty *expr_catenate_new(expr_list_ty *parts,
+ *              expr_position_ty *pp);
+ *
+ * DESCRIPTION
+ *      The expr_catenate_new function is used to create a new instance
+ *      of a catenate expression node.  There must be at least two
+ *      parts.  The expressions are copied.
+ */
+
+func expr_catenate_new(parts *expr_list_ty, pp *expr_position_ty) *expr_ty {
+	assert(len(parts.el_expr) >= 2, "len(parts.el_expr) >= 2")
+	this := &expr_catenate_ty{}
+	for _, ep := range parts.el_expr {
+		expr_list_append(&this.parts, ep)
+	}
+	return expr_instance_new(&expr_catenate_method, this, pp)
+}
@@ -0,0 +1,73 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+type expr_constant_ty struct {
+	value *string_ty
+}
+
+func expr_constant_destructor(ep *expr_ty) {
+	this, ok := ep.this.(*expr_constant_ty)
+	assert(ok, "ep.this.(*expr_constant_ty)")
+	str_free(this.value)
+}
+
+/*
+ * NAME
+ *      expr_constant_code
+ *
+ * SYNOPSIS
+ *      void expr_constant_code(expr_ty *, opcode_list_ty *);
+ *
+ * DESCRIPTION
+ *      The expr_constant_code function is used to generate the opcodes
+ *      for a constant: the string is appended to the top-most string
+ *      list of the value stack.
+ */
+
+func expr_constant_code(ep *expr_ty, olp *opcode_list_ty) {
+	this, ok := ep.this.(*expr_constant_ty)
+	assert(ok, "ep.this.(*expr_constant_ty)")
+	opcode_list_append(olp, opcode_string_new(this.value))
+}
+
+var expr_constant_method = expr_method_ty{
+	name:       "constant",
+	destructor: expr_constant_destructor,
+	code:       expr_constant_code,
+}
+
+/*
+ * NAME
+ *      expr_constant_new - create a constant expression
+ *
+ * SYNOPSIS
+ *      expr_ty *expr_constant_new(string_ty *s, expr_position_ty *pp);
+ *
+ * DESCRIPTION
+ *      The expr_constant_new function is used to create a new instance
+ *      of a constant expression node, for a word in the cookbook.
+ *      The string is copied.
+ */
+
+func expr_constant_new(s *string_ty, pp *expr_position_ty) *expr_ty {
+	this := &expr_constant_ty{value: str_copy(s)}
+	return expr_instance_new(&expr_constant_method, this, pp)
+}
@@ -0,0 +1,86 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+/*
+ * An edge expression is an ingredient followed by an edge type, e.g.
+ * "config.h (exists)".  The edge type says how the ingredient's
+ * timestamp is used when deciding whether the targets are out of date.
+ */
+
+type expr_edge_ty struct {
+	ep        *expr_ty
+	edge_type edge_type_ty
+}
+
+func expr_edge_destructor(ep *expr_ty) {
+	this, ok := ep.this.(*expr_edge_ty)
+	assert(ok, "ep.this.(*expr_edge_ty)")
+	expr_delete(this.ep)
+}
+
+/*
+ * NAME
+ *      expr_edge_code
+ *
+ * SYNOPSIS
+ *      void expr_edge_code(expr_ty *, opcode_list_ty *);
+ *
+ * DESCRIPTION
+ *      The expr_edge_code function is used to generate the opcodes for
+ *      an edge expression: the ingredient is evaluated into a string
+ *      list of its own, and then the edge opcode notes the edge type of
+ *      each word.
+ */
+
+func expr_edge_code(ep *expr_ty, olp *opcode_list_ty) {
+	this, ok := ep.this.(*expr_edge_ty)
+	assert(ok, "ep.this.(*expr_edge_ty)")
+	opcode_list_append(olp, opcode_push_new())
+	expr_code(this.ep, olp)
+	opcode_list_append(olp, opcode_edge_new(this.edge_type))
+}
+
+var expr_edge_method = expr_method_ty{
+	name:       "edge",
+	destructor: expr_edge_destructor,
+	code:       expr_edge_code,
+}
+
+/*
+ * NAME
+ *      expr_edge_new - create an edge expression
+ *
+ * SYNOPSIS
+ *      expr_ty *expr_edge_new(expr_ty *ep, edge_type_ty et,
+ *              expr_position_ty *pp);
+ *
+ * DESCRIPTION
+ *      The expr_edge_new function is used to create a new instance of
+ *      an edge expression node.  The expression is copied.
+ */
+
+func expr_edge_new(ep *expr_ty, et edge_type_ty, pp *expr_position_ty) *expr_ty {
+	this := &expr_edge_ty{
+		ep:        expr_copy(ep),
+		edge_type: et,
+	}
+	return expr_instance_new(&expr_edge_method, this, pp)
+}
@@ -0,0 +1,85 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+/*
+ * A function expression is a bracketed list, "[name args...]".  The
+ * name is an expression like any other, it is evaluated at run time.
+ */
+
+type expr_function_ty struct {
+	args expr_list_ty
+}
+
+func expr_function_destructor(ep *expr_ty) {
+	this, ok := ep.this.(*expr_function_ty)
+	assert(ok, "ep.this.(*expr_function_ty)")
+	expr_list_destructor(&this.args)
+}
+
+/*
+ * NAME
+ *      expr_function_code
+ *
+ * SYNOPSIS
+ *      void expr_function_code(expr_ty *, opcode_list_ty *);
+ *
+ * DESCRIPTION
+ *      The expr_function_code function is used to generate the opcodes
+ *      for a function call: the name and arguments are gathered into a
+ *      new string list, which the function opcode replaces with the
+ *      results.
+ */
+
+func expr_function_code(ep *expr_ty, olp *opcode_list_ty) {
+	this, ok := ep.this.(*expr_function_ty)
+	assert(ok, "ep.this.(*expr_function_ty)")
+	opcode_list_append(olp, opcode_push_new())
+	expr_list_code(&this.args, olp)
+	opcode_list_append(olp, opcode_function_new(&ep.e_position))
+}
+
+var expr_function_method = expr_method_ty{
+	name:       "function",
+	destructor: expr_function_destructor,
+	code:       expr_function_code,
+}
+
+/*
+ * NAME
+ *      expr_function_new - create a function call expression
+ *
+ * SYNOPSIS
+ *      expr_ty *expr_function_new(expr_list_ty *args,
+ *              expr_position_ty *pp);
+ *
+ * DESCRIPTION
+ *      The expr_function_new function is used to create a new instance
+ *      of a function call expression node.  The first element of the
+ *      argument list names the function.  The expressions are copied.
+ */
+
+func expr_function_new(args *expr_list_ty, pp *expr_position_ty) *expr_ty {
+	this := &expr_function_ty{}
+	for _, ep := range args.el_expr {
+		expr_list_append(&this.args, ep)
+	}
+	return expr_instance_new(&expr_function_method, this, pp)
+}
@@ -0,0 +1,33 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+type expr_ty struct {
+	method          *expr_method_ty
+	this            interface{} /* the derived instance, see expr_instance_new */
+	reference_count long
+	e_position      expr_position_ty
+}
+
+type expr_list_ty struct {
+	// el_nexprs     size_t
+	// el_nexprs_max size_t
+	el_expr []*expr_ty
+}
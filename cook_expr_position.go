@@ -34,7 +34,7 @@ package main
  */
 
 func error_with_position(pp *expr_position_ty, scp *sub_context_ty, fmt string) {
-	need_to_delete := scp != nil
+	need_to_delete := scp == nil
 
 	if scp == nil {
 		scp = sub_context_new()
@@ -59,3 +59,46 @@ func error_with_position(pp *expr_position_ty, scp *sub_context_ty, fmt string)
 		sub_context_delete(scp)
 	}
 }
+
+/*
+ * NAME
+ *      expr_position_copy_constructor
+ *
+ * SYNOPSIS
+ *      void expr_position_copy_constructor(expr_position_ty *to,
+ *              expr_position_ty *from);
+ *
+ * DESCRIPTION
+ *      The expr_position_copy_constructor function is used to make a
+ *      copy of a position.  It is safe to pass a NULL from pointer.
+ */
+
+func expr_position_copy_constructor(to, from *expr_position_ty) {
+	*to = expr_position_ty{}
+	if from == nil {
+		return
+	}
+	*to = *from
+	if from.pos_name != nil {
+		to.pos_name = str_copy(from.pos_name)
+	}
+}
+
+/*
+ * NAME
+ *      expr_position_destructor
+ *
+ * SYNOPSIS
+ *      void expr_position_destructor(expr_position_ty *);
+ *
+ * DESCRIPTION
+ *      The expr_position_destructor function is used to release the
+ *      resources held by a position.
+ */
+
+func expr_position_destructor(pp *expr_position_ty) {
+	if pp.pos_name != nil {
+		str_free(pp.pos_name)
+		pp.pos_name = nil
+	}
+}
@@ -0,0 +1,380 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+/*
+ * The name of the fingerprint cache file in each directory.
+ */
+const fingerprint_cache_name = ".cook.fp"
+
+/*
+ * The fingerprint caches read so far, indexed by directory.
+ */
+var fingerprint_cache = make(map[string]*fingerprint_cache_ty)
+
+/*
+ * NAME
+ *      fingerprint_cache_find - find a directory's cache
+ *
+ * SYNOPSIS
+ *      fingerprint_cache_ty *fingerprint_cache_find(char *dir);
+ *
+ * DESCRIPTION
+ *      The fingerprint_cache_find function is used to obtain the
+ *      fingerprint cache of the given directory, reading the .cook.fp
+ *      file the first time.  A missing cache file is not an error, and
+ *      lines which can't be understood are ignored; it is only a cache.
+ */
+
+func fingerprint_cache_find(dir string) *fingerprint_cache_ty {
+	if cp, ok := fingerprint_cache[dir]; ok {
+		return cp
+	}
+	cp := &fingerprint_cache_ty{
+		path:  filepath.Join(dir, fingerprint_cache_name),
+		entry: make(map[string]*fingerprint_ty),
+	}
+	fingerprint_cache[dir] = cp
+	fp, err := os.Open(cp.path)
+	if err != nil {
+		return cp
+	}
+	defer fp.Close()
+	scanner := bufio.NewScanner(fp)
+	for scanner.Scan() {
+		var name string
+		v := &fingerprint_ty{}
+		n, err := fmt.Sscanf(scanner.Text(), "%q %d %d %d %s %s", &name, &v.oldest, &v.newest, &v.size, &v.hash, &v.ingredients)
+		if err != nil && n != 5 {
+			continue
+		}
+		if v.ingredients == "-" {
+			v.ingredients = ""
+		}
+		cp.entry[name] = v
+	}
+	return cp
+}
+
+/*
+ * NAME
+ *      fingerprint_cache_write - write a directory's cache
+ *
+ * SYNOPSIS
+ *      void fingerprint_cache_write(fingerprint_cache_ty *);
+ *
+ * DESCRIPTION
+ *      The fingerprint_cache_write function is used to write the
+ *      fingerprint cache back to its .cook.fp file, if it has changed.
+ *      The file is replaced atomically.  Failure is reported, but is
+ *      not fatal.
+ */
+
+func fingerprint_cache_write(cp *fingerprint_cache_ty) {
+	if !cp.dirty {
+		return
+	}
+	cp.dirty = false
+	var names []string
+	for name := range cp.entry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	tmp := cp.path + ".tmp"
+	fp, err := os.Create(tmp)
+	if err == nil {
+		w := bufio.NewWriter(fp)
+		for _, name := range names {
+			v := cp.entry[name]
+			ingredients := v.ingredients
+			if ingredients == "" {
+				ingredients = "-"
+			}
+			_, _ = fmt.Fprintf(w, "%q %d %d %d %s %s\n", name, v.oldest, v.newest, v.size, v.hash, ingredients)
+		}
+		err = w.Flush()
+		if err2 := fp.Close(); err == nil {
+			err = err2
+		}
+		if err == nil {
+			err = os.Rename(tmp, cp.path)
+		}
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+		scp := sub_context_new()
+		sub_var_set(scp, "Name", "%s", cp.path)
+		sub_var_set(scp, "ERRNO", "%s", err.Error())
+		error_intl(scp, i18n("warning: write $name: $errno"))
+		sub_context_delete(scp)
+	}
+}
+
+/*
+ * NAME
+ *      fingerprint_write_all - write the fingerprint caches
+ *
+ * SYNOPSIS
+ *      void fingerprint_write_all(void);
+ *
+ * DESCRIPTION
+ *      The fingerprint_write_all function is used to write all of the
+ *      fingerprint caches which have changed.  It is called once cook
+ *      has finished cooking.
+ */
+
+func fingerprint_write_all() {
+	for _, cp := range fingerprint_cache {
+		fingerprint_cache_write(cp)
+	}
+}
+
+/*
+ * NAME
+ *      fingerprint_hash - digest a file
+ *
+ * SYNOPSIS
+ *      string fingerprint_hash(char *path);
+ *
+ * DESCRIPTION
+ *      The fingerprint_hash function is used to calculate a digest of
+ *      the contents of a file.
+ */
+
+func fingerprint_hash(path string) (string, error) {
+	fp, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer fp.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, fp); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+/*
+ * NAME
+ *      fingerprint_mtime_oldest - when did a file's contents change
+ *
+ * SYNOPSIS
+ *      long fingerprint_mtime_oldest(string_ty *path);
+ *
+ * DESCRIPTION
+ *      The fingerprint_mtime_oldest function is used to obtain the time
+ *      the contents of the given file last changed.  If the file has
+ *      been written since it was last checked, its contents are
+ *      digested again; if they are unchanged, the previous time is
+ *      kept.  The cache is only marked for writing if the
+ *      fingerprint-write option is in force.
+ *
+ * RETURNS
+ *      long; in nanoseconds since the epoch, 0 if the file does not
+ *      exist, -1 on error (already reported).
+ */
+
+func fingerprint_mtime_oldest(path *string_ty) long {
+	scp := stat_cache_query(path)
+	if scp == nil {
+		return -1
+	}
+	if !scp.exists {
+		return 0
+	}
+	if !fs.FileMode(scp.mode).IsRegular() {
+		return scp.mtime
+	}
+	v := fingerprint_update(path.str, scp.mtime, scp.size)
+	if v == nil {
+		return -1
+	}
+	return v.oldest
+}
+
+/*
+ * NAME
+ *      fingerprint_update - refresh a file's fingerprint
+ *
+ * SYNOPSIS
+ *      fingerprint_ty *fingerprint_update(char *path, long mtime,
+ *              long size);
+ *
+ * DESCRIPTION
+ *      The fingerprint_update function is used to bring the cached
+ *      fingerprint of a regular file up to date, given its current
+ *      modification time and size.
+ *
+ * RETURNS
+ *      fingerprint_ty *; NULL on error (already reported).
+ */
+
+func fingerprint_update(path string, mtime, size long) *fingerprint_ty {
+	cp := fingerprint_cache_find(filepath.Dir(path))
+	name := filepath.Base(path)
+	v := cp.entry[name]
+	if v != nil && v.newest == mtime && v.size == size {
+		return v
+	}
+	hash, err := fingerprint_hash(path)
+	if err != nil {
+		scp := sub_context_new()
+		sub_var_set(scp, "Name", "%s", path)
+		sub_var_set(scp, "ERRNO", "%s", err.Error())
+		error_intl(scp, i18n("read $name: $errno"))
+		sub_context_delete(scp)
+		return nil
+	}
+	if v == nil {
+		v = &fingerprint_ty{}
+		cp.entry[name] = v
+	}
+	if v.hash != hash {
+		v.oldest = mtime
+		v.hash = hash
+	}
+	v.newest = mtime
+	v.size = size
+	if option_test(OPTION_FINGERPRINT_WRITE) {
+		cp.dirty = true
+	}
+	return v
+}
+
+/*
+ * NAME
+ *      fingerprint_update_tree - rescan a directory tree
+ *
+ * SYNOPSIS
+ *      int fingerprint_update_tree(char *dir);
+ *
+ * DESCRIPTION
+ *      The fingerprint_update_tree function is used to bring up to date
+ *      the fingerprints of every regular file in the given directory
+ *      tree, and write the caches.  Directories with names starting
+ *      with a dot are skipped.  This is the -Fingerprint_Update option.
+ *
+ * RETURNS
+ *      int; 0 on success, -1 on error (already reported).
+ */
+
+func fingerprint_update_tree(dir string) int {
+	status := 0
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != dir && d.Name()[0] == '.' {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() || d.Name() == fingerprint_cache_name {
+			return nil
+		}
+		st, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if fingerprint_update(path, st.ModTime().UnixNano(), st.Size()) == nil {
+			status = -1
+		}
+		return nil
+	})
+	if err != nil {
+		scp := sub_context_new()
+		sub_var_set(scp, "ERRNO", "%s", err.Error())
+		error_intl(scp, i18n("$errno"))
+		sub_context_delete(scp)
+		status = -1
+	}
+	for _, cp := range fingerprint_cache {
+		cp.dirty = true
+	}
+	fingerprint_write_all()
+	return status
+}
+
+/*
+ * NAME
+ *      fingerprint_ingredients_get - ingredients last used
+ *
+ * SYNOPSIS
+ *      string fingerprint_ingredients_get(string_ty *path);
+ *
+ * DESCRIPTION
+ *      The fingerprint_ingredients_get function is used to obtain the
+ *      digest of the ingredients list and recipe body last used to cook
+ *      the given file.
+ *
+ * RETURNS
+ *      string; "" if not known.
+ */
+
+func fingerprint_ingredients_get(path *string_ty) string {
+	cp := fingerprint_cache_find(filepath.Dir(path.str))
+	if v := cp.entry[filepath.Base(path.str)]; v != nil {
+		return v.ingredients
+	}
+	return ""
+}
+
+/*
+ * NAME
+ *      fingerprint_ingredients_set - remember ingredients used
+ *
+ * SYNOPSIS
+ *      void fingerprint_ingredients_set(string_ty *path, string digest);
+ *
+ * DESCRIPTION
+ *      The fingerprint_ingredients_set function is used to remember the
+ *      digest of the ingredients list and recipe body just used to cook
+ *      the given file.  Nothing is remembered if the file does not
+ *      exist, or if the fingerprint-write option is not in force.
+ */
+
+func fingerprint_ingredients_set(path *string_ty, digest string) {
+	if !option_test(OPTION_FINGERPRINT_WRITE) {
+		return
+	}
+	scp := stat_cache_query(path)
+	if scp == nil || !scp.exists || !fs.FileMode(scp.mode).IsRegular() {
+		return
+	}
+	v := fingerprint_update(path.str, scp.mtime, scp.size)
+	if v == nil || v.ingredients == digest {
+		return
+	}
+	v.ingredients = digest
+	fingerprint_cache_find(filepath.Dir(path.str)).dirty = true
+}
@@ -0,0 +1,50 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+/*
+ * The fingerprint of a file records when its contents last changed, as
+ * opposed to when it was last written.  A file which is rewritten with
+ * identical contents keeps its old fingerprint time, so that the files
+ * which depend on it need not be cooked again.
+ */
+type fingerprint_ty struct {
+	oldest long   /* when the contents last changed */
+	newest long   /* the modification time when last checked */
+	size   long   /* the size when last checked */
+	hash   string /* digest of the contents */
+
+	/*
+	 * The digest of the ingredients list and recipe body last used to
+	 * cook the file, or "" if not known.  See the
+	 * ingredients-fingerprint flag.
+	 */
+	ingredients string
+}
+
+/*
+ * The fingerprints are cached in a .cook.fp file in each directory,
+ * indexed by the files' base names.
+ */
+type fingerprint_cache_ty struct {
+	path  string
+	entry map[string]*fingerprint_ty
+	dirty bool
+}
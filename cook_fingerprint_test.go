@@ -0,0 +1,100 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFingerprintCacheRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	entry := map[string]*fingerprint_ty{
+		"plain.c":             {oldest: 100, newest: 200, size: 3, hash: "abc123", ingredients: "def456"},
+		"with space.o":        {oldest: 101, newest: 201, size: 4, hash: "0123", ingredients: "4567"},
+		"  leading and tail ": {oldest: 102, newest: 202, size: 5, hash: "89ab", ingredients: "cdef"},
+		"quote\"tab\tname":    {oldest: 103, newest: 203, size: 6, hash: "fedc"},
+		"no-ingredients":      {oldest: 104, newest: 204, size: 0, hash: "e3b0"},
+	}
+	cp := fingerprint_cache_find(dir)
+	for name, v := range entry {
+		dup := *v
+		cp.entry[name] = &dup
+	}
+	cp.dirty = true
+	fingerprint_cache_write(cp)
+	if cp.dirty {
+		t.Error("cache still dirty after writing")
+	}
+
+	/*
+	 * Forget the cached copy, so that the file is read again.
+	 */
+	delete(fingerprint_cache, dir)
+	cp = fingerprint_cache_find(dir)
+	defer delete(fingerprint_cache, dir)
+	if !reflect.DeepEqual(cp.entry, entry) {
+		for name, v := range cp.entry {
+			t.Logf("read %q = %+v", name, *v)
+		}
+		t.Fatalf("round trip lost entries, read %d, wrote %d", len(cp.entry), len(entry))
+	}
+	if _, err := ioutil.ReadFile(cp.path + ".tmp"); err == nil {
+		t.Error("temporary file left behind")
+	}
+}
+
+func TestFingerprintCacheRead(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want *fingerprint_ty /* nil if the line is ignored */
+	}{
+		{"six fields", `"a b" 1 2 3 h1 i1`, &fingerprint_ty{oldest: 1, newest: 2, size: 3, hash: "h1", ingredients: "i1"}},
+		{"no ingredients", `"a b" 1 2 3 h1 -`, &fingerprint_ty{oldest: 1, newest: 2, size: 3, hash: "h1"}},
+		{"five fields", `"a b" 1 2 3 h1`, &fingerprint_ty{oldest: 1, newest: 2, size: 3, hash: "h1"}},
+		{"unquoted name", `a 1 2 3 h1 i1`, nil},
+		{"bad number", `"a b" 1 x 3 h1 i1`, nil},
+		{"too few fields", `"a b" 1 2 3`, nil},
+		{"empty line", ``, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, fingerprint_cache_name)
+			if err := ioutil.WriteFile(path, []byte(tt.line+"\n"), 0644); err != nil {
+				t.Fatal(err)
+			}
+			cp := fingerprint_cache_find(dir)
+			defer delete(fingerprint_cache, dir)
+			got, ok := cp.entry["a b"]
+			switch {
+			case tt.want == nil && len(cp.entry) != 0:
+				t.Errorf("%q was not ignored", tt.line)
+			case tt.want != nil && !ok:
+				t.Errorf("%q was ignored", tt.line)
+			case tt.want != nil && !reflect.DeepEqual(got, tt.want):
+				t.Errorf("%q read as %+v, want %+v", tt.line, *got, *tt.want)
+			}
+		})
+	}
+}
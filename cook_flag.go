@@ -0,0 +1,297 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import "strings"
+
+/*
+ * The names of the recipe flags, as they appear in "set" statements
+ * and in the "set" clauses of recipes.  Some flags have more than one
+ * name.
+ */
+
+type flag_name_ty struct {
+	name  string
+	value flag_value_ty
+}
+
+var flag_name_table = []flag_name_ty{
+	{"cascade", RF_CASCADE},
+	{"no-cascade", RF_CASCADE_OFF},
+	{"clearstat", RF_CLEARSTAT},
+	{"clear-stat", RF_CLEARSTAT},
+	{"no-clearstat", RF_CLEARSTAT_OFF},
+	{"no-clear-stat", RF_CLEARSTAT_OFF},
+	{"ctime", RF_CTIME},
+	{"no-ctime", RF_CTIME_OFF},
+	{"deep", RF_DEEP},
+	{"no-deep", RF_DEEP_OFF},
+	{"default", RF_DEFAULT},
+	{"no-default", RF_DEFAULT_OFF},
+	{"errok", RF_ERROK},
+	{"no-errok", RF_ERROK_OFF},
+	{"file-size-statistics", RF_FILE_SIZE_STATS},
+	{"no-file-size-statistics", RF_FILE_SIZE_STATS_OFF},
+	{"fingerprint", RF_FINGERPRINT},
+	{"fingerprint-nowrite", RF_FINGERPRINT_NOWRITE},
+	{"no-fingerprint", RF_FINGERPRINT_OFF},
+	{"force", RF_FORCE},
+	{"no-force", RF_FORCE_OFF},
+	{"gate-first", RF_GATEFIRST},
+	{"no-gate-first", RF_GATEFIRST_OFF},
+	{"implicit-ingredients", RF_IMPLICIT_ALLOWED},
+	{"no-implicit-ingredients", RF_IMPLICIT_ALLOWED_OFF},
+	{"include-cooked-warning", RF_INCLUDE_COOKED_WARNING},
+	{"no-include-cooked-warning", RF_INCLUDE_COOKED_WARNING_OFF},
+	{"ingredients-fingerprint", RF_INGREDIENTS_FINGERPRINT},
+	{"no-ingredients-fingerprint", RF_INGREDIENTS_FINGERPRINT_OFF},
+	{"match-mode-cook", RF_MATCH_MODE_COOK},
+	{"match-mode-regex", RF_MATCH_MODE_REGEX},
+	{"meter", RF_METER},
+	{"no-meter", RF_METER_OFF},
+	{"mkdir", RF_MKDIR},
+	{"no-mkdir", RF_MKDIR_OFF},
+	{"precious", RF_PRECIOUS},
+	{"no-precious", RF_PRECIOUS_OFF},
+	{"recurse", RF_RECURSE},
+	{"no-recurse", RF_RECURSE_OFF},
+	{"shallow", RF_SHALLOW},
+	{"no-shallow", RF_SHALLOW_OFF},
+	{"silent", RF_SILENT},
+	{"no-silent", RF_SILENT_OFF},
+	{"star", RF_STAR},
+	{"no-star", RF_STAR_OFF},
+	{"stripdot", RF_STRIPDOT},
+	{"no-stripdot", RF_STRIPDOT_OFF},
+	{"symlink-ingredients", RF_SYMLINK_INGREDIENTS},
+	{"no-symlink-ingredients", RF_SYMLINK_INGREDIENTS_OFF},
+	{"tell-position", RF_TELL_POSITION},
+	{"no-tell-position", RF_TELL_POSITION_OFF},
+	{"unlink", RF_UNLINK},
+	{"no-unlink", RF_UNLINK_OFF},
+	{"update", RF_UPDATE},
+	{"time-adjust", RF_UPDATE},
+	{"update-max", RF_UPDATE_MAX},
+	{"time-adjust-back", RF_UPDATE_MAX},
+	{"no-update", RF_UPDATE_OFF},
+	{"no-time-adjust", RF_UPDATE_OFF},
+}
+
+/*
+ * The mapping from recipe flags to options.  Each row gives the option
+ * and the flags which turn it on and off.
+ */
+
+type flag_option_ty struct {
+	option option_number_ty
+	on     flag_value_ty
+	off    flag_value_ty
+}
+
+var flag_option_table = []flag_option_ty{
+	{OPTION_CASCADE, RF_CASCADE, RF_CASCADE_OFF},
+	{OPTION_INVALIDATE_STAT_CACHE, RF_CLEARSTAT, RF_CLEARSTAT_OFF},
+	{OPTION_CTIME, RF_CTIME, RF_CTIME_OFF},
+	{OPTION_DEEP, RF_DEEP, RF_DEEP_OFF},
+	{OPTION_ERROK, RF_ERROK, RF_ERROK_OFF},
+	{OPTION_FORCE, RF_FORCE, RF_FORCE_OFF},
+	{OPTION_GATEFIRST, RF_GATEFIRST, RF_GATEFIRST_OFF},
+	{OPTION_IMPLICIT_ALLOWED, RF_IMPLICIT_ALLOWED, RF_IMPLICIT_ALLOWED_OFF},
+	{OPTION_INCLUDE_COOKED_WARNING, RF_INCLUDE_COOKED_WARNING, RF_INCLUDE_COOKED_WARNING_OFF},
+	{OPTION_INGREDIENTS_FINGERPRINT, RF_INGREDIENTS_FINGERPRINT, RF_INGREDIENTS_FINGERPRINT_OFF},
+	{OPTION_MATCH_MODE_REGEX, RF_MATCH_MODE_REGEX, RF_MATCH_MODE_COOK},
+	{OPTION_METER, RF_METER, RF_METER_OFF},
+	{OPTION_MKDIR, RF_MKDIR, RF_MKDIR_OFF},
+	{OPTION_PRECIOUS, RF_PRECIOUS, RF_PRECIOUS_OFF},
+	{OPTION_RECURSE, RF_RECURSE, RF_RECURSE_OFF},
+	{OPTION_SHALLOW, RF_SHALLOW, RF_SHALLOW_OFF},
+	{OPTION_SILENT, RF_SILENT, RF_SILENT_OFF},
+	{OPTION_STAR, RF_STAR, RF_STAR_OFF},
+	{OPTION_STRIP_DOT, RF_STRIPDOT, RF_STRIPDOT_OFF},
+	{OPTION_SYMLINK_INGREDIENTS, RF_SYMLINK_INGREDIENTS, RF_SYMLINK_INGREDIENTS_OFF},
+	{OPTION_TELL_POSITION, RF_TELL_POSITION, RF_TELL_POSITION_OFF},
+	{OPTION_UNLINK, RF_UNLINK, RF_UNLINK_OFF},
+}
+
+/*
+ * NAME
+ *      flag_recast - convert a string list to recipe flags
+ *
+ * SYNOPSIS
+ *      flag_ty *flag_recast(string_list_ty *, expr_position_ty *);
+ *
+ * DESCRIPTION
+ *      The flag_recast function is used to convert a list of flag
+ *      names (the value of a "set" clause) into a flag set.
+ *
+ * RETURNS
+ *      flag_ty *; NULL on error (an unknown flag name, which has
+ *      already been reported).
+ */
+
+func flag_recast(slp *string_list_ty, pp *expr_position_ty) *flag_ty {
+	fp := &flag_ty{}
+	status := 0
+	for _, s := range slp.strings {
+		found := false
+		for _, tp := range flag_name_table {
+			if tp.name == s.str {
+				fp.flag[tp.value] = 1
+				found = true
+				break
+			}
+		}
+		if !found {
+			scp := sub_context_new()
+			sub_var_set_string(scp, "Name", s)
+			error_with_position(pp, scp, i18n("the name \"$name\" is not a valid flag"))
+			sub_context_delete(scp)
+			status = -1
+		}
+	}
+	if status < 0 {
+		return nil
+	}
+	return fp
+}
+
+/*
+ * NAME
+ *      flag_copy - copy a flag set
+ *
+ * SYNOPSIS
+ *      flag_ty *flag_copy(flag_ty *);
+ *
+ * DESCRIPTION
+ *      The flag_copy function is used to make a copy of a flag set.
+ *      It is safe to pass NULL.
+ */
+
+func flag_copy(fp *flag_ty) *flag_ty {
+	if fp == nil {
+		return nil
+	}
+	result := *fp
+	return &result
+}
+
+/*
+ * NAME
+ *      flag_query - test a recipe flag
+ *
+ * SYNOPSIS
+ *      int flag_query(flag_ty *, flag_value_ty);
+ *
+ * DESCRIPTION
+ *      The flag_query function is used to test whether the given flag
+ *      was named.  It is safe to pass NULL.
+ */
+
+func flag_query(fp *flag_ty, f flag_value_ty) bool {
+	return fp != nil && fp.flag[f] != 0
+}
+
+/*
+ * NAME
+ *      flag_set_options - set options from flags
+ *
+ * SYNOPSIS
+ *      void flag_set_options(flag_ty *, option_level_ty);
+ *
+ * DESCRIPTION
+ *      The flag_set_options function is used to set the options
+ *      corresponding to the given flags, at the given level.  Options
+ *      which were not mentioned are left alone.
+ */
+
+func flag_set_options(fp *flag_ty, level option_level_ty) {
+	if fp == nil {
+		return
+	}
+	for _, tp := range flag_option_table {
+		if fp.flag[tp.on] != 0 {
+			option_set(tp.option, level, true)
+		}
+		if fp.flag[tp.off] != 0 {
+			option_set(tp.option, level, false)
+		}
+	}
+
+	/*
+	 * The fingerprint and update flags each control two options.
+	 */
+	if fp.flag[RF_FINGERPRINT] != 0 {
+		option_set(OPTION_FINGERPRINT, level, true)
+		option_set(OPTION_FINGERPRINT_WRITE, level, true)
+	}
+	if fp.flag[RF_FINGERPRINT_NOWRITE] != 0 {
+		option_set(OPTION_FINGERPRINT, level, true)
+		option_set(OPTION_FINGERPRINT_WRITE, level, false)
+	}
+	if fp.flag[RF_FINGERPRINT_OFF] != 0 {
+		option_set(OPTION_FINGERPRINT, level, false)
+	}
+	if fp.flag[RF_UPDATE] != 0 {
+		option_set(OPTION_UPDATE, level, true)
+		option_set(OPTION_UPDATE_MAX, level, false)
+	}
+	if fp.flag[RF_UPDATE_MAX] != 0 {
+		option_set(OPTION_UPDATE, level, true)
+		option_set(OPTION_UPDATE_MAX, level, true)
+	}
+	if fp.flag[RF_UPDATE_OFF] != 0 {
+		option_set(OPTION_UPDATE, level, false)
+		option_set(OPTION_UPDATE_MAX, level, false)
+	}
+}
+
+/*
+ * NAME
+ *      flag_to_string - names of recipe flags
+ *
+ * SYNOPSIS
+ *      string_ty *flag_to_string(flag_ty *);
+ *
+ * DESCRIPTION
+ *      The flag_to_string function is used to obtain the names of the
+ *      flags set in the given flag set, separated by spaces, for use
+ *      in listings.  It is safe to pass NULL.
+ *
+ * RETURNS
+ *      string_ty *; use str_free when you are done with it.
+ */
+
+func flag_to_string(fp *flag_ty) *string_ty {
+	var names []string
+	if fp != nil {
+		for f := flag_value_ty(0); f < RF_max; f++ {
+			if fp.flag[f] == 0 {
+				continue
+			}
+			for _, tp := range flag_name_table {
+				if tp.value == f {
+					names = append(names, tp.name)
+					break
+				}
+			}
+		}
+	}
+	return str_from_string(strings.Join(names, " "))
+}
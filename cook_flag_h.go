@@ -64,6 +64,8 @@ const (
 	RF_CLEARSTAT_OFF
 	RF_CTIME
 	RF_CTIME_OFF
+	RF_DEEP
+	RF_DEEP_OFF
 	RF_DEFAULT
 	RF_DEFAULT_OFF
 	RF_ERROK
@@ -0,0 +1,208 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+)
+
+/*
+ * NAME
+ *      graph_new - create a dependency graph
+ *
+ * SYNOPSIS
+ *      graph_ty *graph_new(void);
+ *
+ * DESCRIPTION
+ *      The graph_new function is used to create a new, empty,
+ *      dependency graph.
+ *
+ * RETURNS
+ *      graph_ty *; use graph_delete when you are done with it.
+ */
+
+func graph_new() *graph_ty {
+	trace("graph_new()\n{\n")
+	gp := &graph_ty{
+		try_list:       &string_list_ty{},
+		already:        symtab_alloc(100),
+		already_recipe: graph_recipe_list_new(),
+	}
+	gp.already.reap = graph_reap_file
+	trace(fmt.Sprintf("return %p;\n", gp))
+	trace("}\n")
+	return gp
+}
+
+func graph_reap_file(p interface{}) {
+	gfp, ok := p.(*graph_file_ty)
+	assert(ok, "p.(*graph_file_ty)")
+	graph_file_delete(gfp)
+}
+
+/*
+ * NAME
+ *      graph_delete - release a dependency graph
+ *
+ * SYNOPSIS
+ *      void graph_delete(graph_ty *);
+ *
+ * DESCRIPTION
+ *      The graph_delete function is used to release a dependency graph,
+ *      and all of the file nodes and recipe instances in it.
+ */
+
+func graph_delete(gp *graph_ty) {
+	trace(fmt.Sprintf("graph_delete(gp = %p)\n{\n", gp))
+	string_list_delete(gp.try_list)
+	gp.try_list = nil
+	graph_recipe_list_delete(gp.already_recipe)
+	gp.already_recipe = nil
+	symtab_free(gp.already)
+	gp.already = nil
+	trace("}\n")
+}
+
+/*
+ * NAME
+ *      graph_file_list_sorted - all files in the graph
+ *
+ * SYNOPSIS
+ *      graph_file_ty **graph_file_list_sorted(graph_ty *);
+ *
+ * DESCRIPTION
+ *      The graph_file_list_sorted function is used to obtain all of the
+ *      file nodes of the graph, sorted by name, so that listings are
+ *      reproducible.
+ */
+
+func graph_file_list_sorted(gp *graph_ty) []*graph_file_ty {
+	var result []*graph_file_ty
+	for _, rows := range gp.already.hash_table {
+		for _, row := range rows {
+			if gfp, ok := row.data.(*graph_file_ty); ok {
+				result = append(result, gfp)
+			}
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].filename.str < result[j].filename.str
+	})
+	return result
+}
+
+/*
+ * NAME
+ *      graph_interior_and_leaf_files
+ *
+ * SYNOPSIS
+ *      void graph_interior_and_leaf_files(graph_ty *,
+ *              string_list_ty *interior, string_list_ty *leaf);
+ *
+ * DESCRIPTION
+ *      The graph_interior_and_leaf_files function is used to classify
+ *      the files of the graph which are used.  Interior files are made
+ *      by a recipe with a body, leaf files are not.  Files which were
+ *      considered and then backtracked are in neither list, nor are
+ *      phony files, such as "all", which are not files at all.
+ */
+
+func graph_interior_and_leaf_files(gp *graph_ty, interior, leaf *string_list_ty) {
+	for _, gfp := range graph_file_list_sorted(gp) {
+		if gfp.previous_backtrack != 0 || gfp.previous_error != 0 {
+			continue
+		}
+		if gfp.phony != 0 {
+			continue
+		}
+		if len(gfp.input.recipe) == 0 && len(gfp.output.recipe) == 0 && gfp.primary_target == 0 {
+			continue
+		}
+		made := false
+		for _, grp := range gfp.input.recipe {
+			if grp.rp.out_of_date != nil {
+				made = true
+				break
+			}
+		}
+		if made {
+			string_list_append(interior, gfp.filename)
+		} else {
+			string_list_append(leaf, gfp.filename)
+		}
+	}
+}
+
+/*
+ * NAME
+ *      graph_print_statistics - print graph statistics
+ *
+ * SYNOPSIS
+ *      void graph_print_statistics(graph_ty *);
+ *
+ * DESCRIPTION
+ *      The graph_print_statistics function is used to print the
+ *      statistics collected while building the graph, on the standard
+ *      error.  Counters which are zero are not printed.
+ */
+
+func graph_print_statistics(gp *graph_ty) {
+	sp := &gp.statistic
+	table := []struct {
+		name  string
+		value long
+	}{
+		{"backtrack bad path", sp.backtrack_bad_path},
+		{"backtrack by ingredient", sp.backtrack_by_ingredient},
+		{"backtrack cache", sp.backtrack_cache},
+		{"error by ingredient", sp.error_by_ingredient},
+		{"error cache", sp.error_cache},
+		{"error in expression", sp.error_in_expr},
+		{"explicit applicable", sp.explicit_applicable},
+		{"explicit ingredients applicable", sp.explicit_ingredients_applicable},
+		{"explicit ingredients not applicable", sp.explicit_ingredients_not_applicable},
+		{"explicit not applicable", sp.explicit_not_applicable},
+		{"implicit applicable", sp.implicit_applicable},
+		{"implicit ingredients applicable", sp.implicit_ingredients_applicable},
+		{"implicit ingredients not applicable", sp.implicit_ingredients_not_applicable},
+		{"implicit not applicable", sp.implicit_not_applicable},
+		{"infinite loop", sp.infinite_loop},
+		{"inhibit self recursion", sp.inhibit_self_recursion},
+		{"leaf backtrack", sp.leaf_backtrack},
+		{"leaf error", sp.leaf_error},
+		{"leaf exists", sp.leaf_exists},
+		{"pattern match query", sp.pattern_match_query},
+		{"phony", sp.phony},
+		{"precondition rejection", sp.precondition_rejection},
+		{"success", sp.success},
+		{"success reuse", sp.success_reuse},
+	}
+	fmt.Fprintf(os.Stderr, "%s: graph statistics:\n", progname_get())
+	for _, tp := range table {
+		if tp.value == 0 {
+			continue
+		}
+		fmt.Fprintf(os.Stderr, "    %-40s %8d\n", tp.name, tp.value)
+	}
+	fmt.Fprintf(os.Stderr, "    %-40s %8d\n", "files", len(graph_file_list_sorted(gp)))
+	fmt.Fprintf(os.Stderr, "    %-40s %8d\n", "recipe instances", len(gp.already_recipe.recipe))
+}
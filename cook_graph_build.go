@@ -0,0 +1,810 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import "fmt"
+
+/*
+ * NAME
+ *      graph_build_need - evaluate an ingredients list
+ *
+ * SYNOPSIS
+ *      string_list_ty *graph_build_need(graph_ty *, graph_recipe_ty *,
+ *              opcode_list_ty *need, edge_type_ty **etp);
+ *
+ * DESCRIPTION
+ *      The graph_build_need function is used to evaluate one of the
+ *      ingredients lists of a recipe instance (or another of its
+ *      clauses which yields file names).  The "target" and
+ *      "targets" variables are available, the recipe's flags are in
+ *      force, and the fields of an implicit recipe's match are used to
+ *      reconstruct the ingredient names.  If etp is not NULL, the edge
+ *      type of each word is appended to it.
+ *
+ * RETURNS
+ *      string_list_ty *; NULL on error (already reported).  Use
+ *      string_list_delete when you are done with it.
+ */
+
+func graph_build_need(gp *graph_ty, grp *graph_recipe_ty, need *opcode_list_ty, etp *[]edge_type_ty) *string_list_ty {
+	trace(fmt.Sprintf("graph_build_need(grp = %p)\n{\n", grp))
+	rp := grp.rp
+	stp := graph_recipe_target_symtab(grp)
+	flag_set_options(rp.flags, OPTION_LEVEL_RECIPE)
+	ocp := opcode_context_new(need, stp)
+	if grp.mp != nil {
+		opcode_context_match_push(ocp, grp.mp)
+	}
+	status := opcode_context_execute_nowait(ocp)
+	var result *string_list_ty
+	if status == opcode_status_success {
+		result = opcode_context_string_list_pop(ocp)
+		if etp != nil {
+			for _, s := range result.strings {
+				*etp = append(*etp, ocp.edge_type[s])
+			}
+		}
+	}
+	if grp.mp != nil {
+		opcode_context_match_pop(ocp)
+	}
+	opcode_context_delete(ocp)
+	option_undo_level(OPTION_LEVEL_RECIPE)
+	symtab_free(stp)
+
+	switch status {
+	case opcode_status_success:
+	case opcode_status_interrupted:
+		error_intl(nil, i18n("interrupted"))
+		fallthrough
+	default:
+		gp.statistic.error_in_expr++
+		trace("return NULL;\n")
+		trace("}\n")
+		return nil
+	}
+
+	/*
+	 * The ingredients of implicit recipes are patterns.
+	 */
+	if grp.mp != nil {
+		wl := &string_list_ty{}
+		for _, s := range result.strings {
+			s2 := match_reconstruct_rhs(grp.mp, s, &rp.pos)
+			if s2 == nil {
+				string_list_delete(wl)
+				string_list_delete(result)
+				gp.statistic.error_in_expr++
+				trace("return NULL;\n")
+				trace("}\n")
+				return nil
+			}
+			string_list_append(wl, s2)
+			str_free(s2)
+		}
+		string_list_delete(result)
+		result = wl
+	}
+	trace(fmt.Sprintf("return %p;\n", result))
+	trace("}\n")
+	return result
+}
+
+/*
+ * NAME
+ *      graph_build_ingredients - add a recipe's ingredients
+ *
+ * SYNOPSIS
+ *      graph_build_status_ty graph_build_ingredients(graph_ty *,
+ *              graph_recipe_ty *, opcode_list_ty *need, int backtrack,
+ *              string_ty **culprit);
+ *
+ * DESCRIPTION
+ *      The graph_build_ingredients function is used to evaluate an
+ *      ingredients list of a recipe instance, and add each ingredient
+ *      to the graph (recursively), as an input of the recipe instance
+ *      with the edge type given in the cookbook.
+ *
+ *      With the cascade flag (the default) the files cascaded from each
+ *      ingredient, by cascade statements, are added as well.
+ *
+ * RETURNS
+ *      graph_build_status_ty; and the ingredient which could not be
+ *      made, if any.
+ */
+
+func graph_build_ingredients(gp *graph_ty, grp *graph_recipe_ty, need *opcode_list_ty, backtrack int) (graph_build_status_ty, *string_ty) {
+	var edge_type []edge_type_ty
+	wlp := graph_build_need(gp, grp, need, &edge_type)
+	if wlp == nil {
+		return graph_build_status_error, nil
+	}
+	defer string_list_delete(wlp)
+
+	/*
+	 * Files cascaded from an ingredient are ingredients too, with
+	 * the same edge type.  Cascades of cascades are followed.
+	 */
+	flag_set_options(grp.rp.flags, OPTION_LEVEL_RECIPE)
+	cascade := option_test(OPTION_CASCADE)
+	option_undo_level(OPTION_LEVEL_RECIPE)
+	if cascade {
+		for j := 0; j < len(wlp.strings); j++ {
+			cook_cascade_find(wlp.strings[j], wlp)
+			for len(edge_type) < len(wlp.strings) {
+				edge_type = append(edge_type, edge_type[j])
+			}
+		}
+	}
+
+	for j, s := range wlp.strings {
+		duplicate := false
+		for _, item := range grp.input.item {
+			if str_equal(item.file.filename, s) {
+				duplicate = true
+				break
+			}
+		}
+		if duplicate {
+			continue
+		}
+		switch graph_build(gp, s, &grp.rp.pos, backtrack) {
+		case graph_build_status_error:
+			gp.statistic.error_by_ingredient++
+			return graph_build_status_error, s
+
+		case graph_build_status_backtrack:
+			gp.statistic.backtrack_by_ingredient++
+			return graph_build_status_backtrack, s
+		}
+		gfp := graph_file_find(gp, s)
+		graph_file_list_nrc_append(grp.input, gfp, edge_type[j])
+		graph_recipe_list_nrc_append(&gfp.output, grp)
+	}
+	return graph_build_status_success, nil
+}
+
+/*
+ * NAME
+ *      graph_build_precondition - evaluate a recipe's gate
+ *
+ * SYNOPSIS
+ *      graph_build_status_ty graph_build_precondition(graph_ty *,
+ *              graph_recipe_ty *);
+ *
+ * DESCRIPTION
+ *      The graph_build_precondition function is used to evaluate the
+ *      "if" clause of a recipe instance.  The precondition is false if
+ *      it evaluates to an empty list, or a list of empty strings, in
+ *      which case the recipe does not apply.
+ *
+ * RETURNS
+ *      graph_build_status_ty; success if the recipe applies, rejected
+ *      if it does not, error on error (already reported).
+ */
+
+func graph_build_precondition(gp *graph_ty, grp *graph_recipe_ty) graph_build_status_ty {
+	if grp.rp.precondition == nil {
+		return graph_build_status_success
+	}
+	wlp := graph_build_need(gp, grp, grp.rp.precondition, nil)
+	if wlp == nil {
+		return graph_build_status_error
+	}
+	applies := string_list_bool(wlp)
+	string_list_delete(wlp)
+	if !applies {
+		gp.statistic.precondition_rejection++
+		return graph_build_status_rejected
+	}
+	return graph_build_status_success
+}
+
+/*
+ * NAME
+ *      graph_build_recipe - instantiate a recipe
+ *
+ * SYNOPSIS
+ *      graph_build_status_ty graph_build_recipe(graph_ty *,
+ *              graph_file_ty *, recipe_ty *, match_ty *, int backtrack);
+ *
+ * DESCRIPTION
+ *      The graph_build_recipe function is used to add an instance of
+ *      the given recipe to the graph, to make the given file.  The
+ *      match (NULL for explicit recipes) is taken over.  The targets
+ *      of the instance are all of the recipe's targets, reconstructed
+ *      from the match for implicit recipes.  The ingredients are added
+ *      to the graph first; if any of them can't be made, or the
+ *      recipe's precondition is false, the instance is discarded.
+ *
+ * RETURNS
+ *      graph_build_status_ty; rejected if the precondition is false.
+ */
+
+func graph_build_recipe(gp *graph_ty, gfp *graph_file_ty, rp *recipe_ty, mp *match_ty, backtrack int) graph_build_status_ty {
+	trace(fmt.Sprintf("graph_build_recipe(gfp = %q, rp = %p)\n{\n", gfp.filename.str, rp))
+	grp := graph_recipe_new(rp, mp)
+	defer graph_recipe_delete(grp)
+
+	/*
+	 * Work out the targets.  The file being made is always first.
+	 * Remember which of the other targets are new to the graph, so
+	 * they can be forgotten if the recipe is rejected.
+	 */
+	var created []*string_ty
+	graph_file_list_nrc_append(grp.output, gfp, edge_type_default)
+	for _, s := range rp.target.strings {
+		name := s
+		if mp != nil {
+			name = match_reconstruct_lhs(mp, s, &rp.pos)
+			if name == nil {
+				gp.statistic.error_in_expr++
+				trace("return error;\n}\n")
+				return graph_build_status_error
+			}
+			defer str_free(name)
+		}
+		if str_equal(name, gfp.filename) {
+			continue
+		}
+		if symtab_query(gp.already, name) == nil {
+			created = append(created, name)
+		}
+		graph_file_list_nrc_append(grp.output, graph_file_find(gp, name), edge_type_default)
+	}
+
+	/*
+	 * Implicit recipe ingredients which can't be made cause the recipe
+	 * to be rejected, rather than an error.
+	 */
+	child_backtrack := backtrack
+	if mp != nil {
+		child_backtrack = 1
+	}
+
+	/*
+	 * The precondition is usually evaluated once the ingredients are
+	 * known; with the gate-first flag it is evaluated before, so that
+	 * a false precondition short-circuits the ingredients.
+	 */
+	flag_set_options(rp.flags, OPTION_LEVEL_RECIPE)
+	gate_first := option_test(OPTION_GATEFIRST)
+	option_undo_level(OPTION_LEVEL_RECIPE)
+	gp.stack = append(gp.stack, graph_build_frame_ty{gfp: gfp, rp: rp})
+	defer func() { gp.stack = gp.stack[:len(gp.stack)-1] }()
+	status := graph_build_status_success
+	var culprit *string_ty
+	if gate_first {
+		status = graph_build_precondition(gp, grp)
+	}
+	if status == graph_build_status_success {
+		status, culprit = graph_build_ingredients(gp, grp, rp.need1, child_backtrack)
+	}
+	if status == graph_build_status_success && rp.need2 != nil {
+		status, culprit = graph_build_ingredients(gp, grp, rp.need2, child_backtrack)
+	}
+	if status == graph_build_status_success && !gate_first {
+		status = graph_build_precondition(gp, grp)
+	}
+
+	/*
+	 * Remember why the recipe was not used, in case the file can't
+	 * be made at all.
+	 */
+	switch status {
+	case graph_build_status_backtrack:
+		gfp.rejected = append(gfp.rejected, graph_build_reject_ty{rp: rp, reason: graph_build_reject_ingredient, ingredient: str_copy(culprit)})
+
+	case graph_build_status_rejected:
+		gfp.rejected = append(gfp.rejected, graph_build_reject_ty{rp: rp, reason: graph_build_reject_precondition})
+	}
+
+	/*
+	 * The single thread clause names resources which no two recipe
+	 * instances may use at the same time.
+	 */
+	if status == graph_build_status_success && rp.single_thread != nil {
+		grp.single_thread = graph_build_need(gp, grp, rp.single_thread, nil)
+		if grp.single_thread == nil {
+			status = graph_build_status_error
+		}
+	}
+
+	/*
+	 * The host binding clause names the hosts the recipe body may be
+	 * run on.
+	 */
+	if status == graph_build_status_success && rp.host_binding != nil {
+		grp.host_binding = graph_build_need(gp, grp, rp.host_binding, nil)
+		if grp.host_binding == nil {
+			status = graph_build_status_error
+		}
+	}
+	if status != graph_build_status_success {
+		for _, item := range grp.input.item {
+			graph_recipe_list_nrc_remove(&item.file.output, grp)
+		}
+		for _, name := range created {
+			symtab_delete(gp.already, name)
+		}
+		trace(fmt.Sprintf("return %d;\n", status))
+		trace("}\n")
+		return status
+	}
+
+	/*
+	 * The recipe instance is good, add it to the graph.
+	 */
+	for _, item := range grp.output.item {
+		graph_recipe_list_nrc_append(&item.file.input, grp)
+	}
+	graph_recipe_list_append(gp.already_recipe, grp)
+	trace("return success;\n")
+	trace("}\n")
+	return graph_build_status_success
+}
+
+/*
+ * NAME
+ *      graph_build_explicit - apply explicit recipes
+ *
+ * SYNOPSIS
+ *      graph_build_status_ty graph_build_explicit(graph_ty *,
+ *              graph_file_ty *, int backtrack, int *have_body,
+ *              int *have_recipe);
+ *
+ * DESCRIPTION
+ *      The graph_build_explicit function is used to apply all of the
+ *      explicit recipes which name the file as a target.  Recipes
+ *      without a body only contribute ingredients.  Only the first
+ *      single-colon recipe with a body is used; all double-colon
+ *      (multiple) recipes with bodies are used.  Recipes whose
+ *      precondition is false are passed over.
+ */
+
+func graph_build_explicit(gp *graph_ty, gfp *graph_file_ty, backtrack int, have_body, have_recipe *bool) graph_build_status_ty {
+	single := false
+	for _, rp := range explicit.recipe {
+		if !string_list_member(rp.target, gfp.filename) {
+			gp.statistic.explicit_not_applicable++
+			continue
+		}
+		if rp.out_of_date != nil && rp.multiple == 0 && single {
+			continue
+		}
+		gp.statistic.explicit_applicable++
+		switch graph_build_recipe(gp, gfp, rp, nil, backtrack) {
+		case graph_build_status_error:
+			return graph_build_status_error
+
+		case graph_build_status_backtrack:
+			gp.statistic.explicit_ingredients_not_applicable++
+			gp.statistic.backtrack_bad_path++
+			return graph_build_status_backtrack
+
+		case graph_build_status_rejected:
+			continue
+		}
+		gp.statistic.explicit_ingredients_applicable++
+		*have_recipe = true
+		if rp.out_of_date != nil {
+			*have_body = true
+			if rp.multiple == 0 {
+				single = true
+			}
+		}
+	}
+	return graph_build_status_success
+}
+
+/*
+ * NAME
+ *      graph_build_implicit - apply implicit recipes
+ *
+ * SYNOPSIS
+ *      graph_build_status_ty graph_build_implicit(graph_ty *,
+ *              graph_file_ty *, int *have_body, int *have_recipe);
+ *
+ * DESCRIPTION
+ *      The graph_build_implicit function is used to search the implicit
+ *      recipes, in the order they were defined, for one with a target
+ *      pattern which matches the file, and with ingredients which can
+ *      be made, and a precondition which is true.  The search stops at
+ *      the first such recipe with a body.
+ *
+ *      While an implicit recipe is being applied, it is not applied
+ *      again to make its own ingredients; otherwise a recipe such as
+ *      "%: %.gz" would look for "x.gz.gz" and so on forever.
+ */
+
+func graph_build_implicit(gp *graph_ty, gfp *graph_file_ty, have_body, have_recipe *bool) graph_build_status_ty {
+	for _, rp := range implicit.recipe {
+		flag_set_options(rp.flags, OPTION_LEVEL_RECIPE)
+		mp := match_new()
+		option_undo_level(OPTION_LEVEL_RECIPE)
+		matched := 0
+		for _, pattern := range rp.target.strings {
+			gp.statistic.pattern_match_query++
+			matched = match_attempt(mp, pattern, gfp.filename, &rp.pos)
+			if matched != 0 {
+				break
+			}
+		}
+		if matched < 0 {
+			match_delete(mp)
+			return graph_build_status_error
+		}
+		if matched == 0 {
+			gp.statistic.implicit_not_applicable++
+			match_delete(mp)
+			continue
+		}
+		if rp.inhibit != 0 {
+			gp.statistic.inhibit_self_recursion++
+			gfp.rejected = append(gfp.rejected, graph_build_reject_ty{rp: rp, reason: graph_build_reject_inhibited})
+			match_delete(mp)
+			continue
+		}
+		gp.statistic.implicit_applicable++
+
+		rp.inhibit++
+		status := graph_build_recipe(gp, gfp, rp, mp, 1)
+		rp.inhibit--
+		switch status {
+		case graph_build_status_error:
+			return graph_build_status_error
+
+		case graph_build_status_backtrack:
+			gp.statistic.implicit_ingredients_not_applicable++
+			gp.statistic.backtrack_bad_path++
+			continue
+
+		case graph_build_status_rejected:
+			continue
+		}
+		gp.statistic.implicit_ingredients_applicable++
+		*have_recipe = true
+		if rp.out_of_date != nil {
+			*have_body = true
+			break
+		}
+	}
+	return graph_build_status_success
+}
+
+/*
+ * NAME
+ *      graph_build_file - add a file to the graph
+ *
+ * SYNOPSIS
+ *      graph_build_status_ty graph_build_file(graph_ty *,
+ *              graph_file_ty *, expr_position_ty *pp, int backtrack);
+ *
+ * DESCRIPTION
+ *      The graph_build_file function is used to find the recipes which
+ *      make the file, and add them (and their ingredients) to the
+ *      graph.  Explicit recipes are considered first, then implicit
+ *      recipes if no explicit recipe has a body.  A file with recipes
+ *      but no body which does not exist is phony.  A file with no
+ *      applicable recipe is a leaf, and must exist.
+ */
+
+func graph_build_file(gp *graph_ty, gfp *graph_file_ty, pp *expr_position_ty, backtrack int) graph_build_status_ty {
+	have_body := false
+	have_recipe := false
+	status := graph_build_explicit(gp, gfp, backtrack, &have_body, &have_recipe)
+	if status != graph_build_status_success {
+		return status
+	}
+	if !have_body {
+		status = graph_build_implicit(gp, gfp, &have_body, &have_recipe)
+		if status != graph_build_status_success {
+			return status
+		}
+	}
+	if have_recipe {
+		if !have_body {
+			/*
+			 * A target with recipes but no body and no file, such
+			 * as "all", is phony.  It is as old as its youngest
+			 * ingredient, see graph_file_mtime_oldest.
+			 */
+			switch search_list_exists(gfp.filename) {
+			case -1:
+				return graph_build_status_error
+
+			case 0:
+				gp.statistic.phony++
+				gfp.phony = 1
+			}
+		}
+		return graph_build_status_success
+	}
+
+	/*
+	 * No recipe applies, so this is a leaf.  It must exist.
+	 */
+	switch search_list_exists(gfp.filename) {
+	case -1:
+		return graph_build_status_error
+
+	case 1:
+		gp.statistic.leaf_exists++
+		return graph_build_status_success
+	}
+	if backtrack != 0 {
+		gp.statistic.leaf_backtrack++
+		string_list_append(gp.try_list, gfp.filename)
+		return graph_build_status_backtrack
+	}
+	gp.statistic.leaf_error++
+	graph_build_dont_know(gp, gfp, pp)
+	return graph_build_status_error
+}
+
+/*
+ * NAME
+ *      graph_build_dont_know
+ *
+ * SYNOPSIS
+ *      void graph_build_dont_know(graph_ty *, graph_file_ty *,
+ *              expr_position_ty *);
+ *
+ * DESCRIPTION
+ *      The graph_build_dont_know function is used to report that there
+ *      is no way to make the given file.  Each recipe which was
+ *      considered is listed at its position, with the reason it was
+ *      not used; an ingredient which could not be made is explained in
+ *      the same way.  Last, the files from the try list which were
+ *      looked for on the way, and do not exist, are listed.
+ */
+
+func graph_build_dont_know(gp *graph_ty, gfp *graph_file_ty, pp *expr_position_ty) {
+	scp := sub_context_new()
+	sub_var_set_string(scp, "Name", gfp.filename)
+	error_with_position(pp, scp, i18n("don't know how to cook \"$name\""))
+	sub_context_delete(scp)
+
+	seen := make(map[string]bool)
+	graph_build_dont_know_why(gp, gfp, seen)
+
+	quoted := &string_list_ty{}
+	for _, s := range gp.try_list.strings {
+		if !seen[s.str] {
+			continue
+		}
+		delete(seen, s.str)
+		q := str_format("\"%s\"", s.str)
+		string_list_append(quoted, q)
+		str_free(q)
+	}
+	if len(quoted.strings) > 0 {
+		list := wl2str(quoted, 0, len(quoted.strings)-1, ", ")
+		scp = sub_context_new()
+		sub_var_set_string(scp, "Name", gfp.filename)
+		sub_var_set_string(scp, "List", list)
+		error_with_position(pp, scp, i18n("tried $list for \"$name\", which do not exist and can't be cooked"))
+		sub_context_delete(scp)
+		str_free(list)
+	}
+	string_list_delete(quoted)
+}
+
+/*
+ * NAME
+ *      graph_build_dont_know_why - explain rejected recipes
+ *
+ * SYNOPSIS
+ *      void graph_build_dont_know_why(graph_ty *, graph_file_ty *,
+ *              map seen);
+ *
+ * DESCRIPTION
+ *      The graph_build_dont_know_why function is used to list the
+ *      recipes rejected for the given file, and then for each of the
+ *      ingredients responsible.  The names of the files explained are
+ *      added to the seen map, so that each is explained only once.
+ */
+
+func graph_build_dont_know_why(gp *graph_ty, gfp *graph_file_ty, seen map[string]bool) {
+	if seen[gfp.filename.str] {
+		return
+	}
+	seen[gfp.filename.str] = true
+	for _, r := range gfp.rejected {
+		scp := sub_context_new()
+		sub_var_set_string(scp, "Name", gfp.filename)
+		switch r.reason {
+		case graph_build_reject_ingredient:
+			sub_var_set_string(scp, "Ingredient", r.ingredient)
+			error_with_position(&r.rp.pos, scp, i18n("recipe for \"$name\" not used, can't cook \"$ingredient\""))
+
+		case graph_build_reject_precondition:
+			error_with_position(&r.rp.pos, scp, i18n("recipe for \"$name\" not used, precondition is false"))
+
+		case graph_build_reject_inhibited:
+			error_with_position(&r.rp.pos, scp, i18n("recipe for \"$name\" not used, it is already in use further up"))
+		}
+		sub_context_delete(scp)
+	}
+	for _, r := range gfp.rejected {
+		if r.reason != graph_build_reject_ingredient {
+			continue
+		}
+		if ifp, ok := symtab_query(gp.already, r.ingredient).(*graph_file_ty); ok {
+			graph_build_dont_know_why(gp, ifp, seen)
+		}
+	}
+}
+
+/*
+ * NAME
+ *      graph_build_loop - report an infinite loop
+ *
+ * SYNOPSIS
+ *      void graph_build_loop(graph_ty *, graph_file_ty *,
+ *              expr_position_ty *pp);
+ *
+ * DESCRIPTION
+ *      The graph_build_loop function is used to report that the given
+ *      file is needed to make itself.  Each step of the loop is listed,
+ *      with the position of the recipe responsible.
+ */
+
+func graph_build_loop(gp *graph_ty, gfp *graph_file_ty, pp *expr_position_ty) {
+	scp := sub_context_new()
+	sub_var_set_string(scp, "Name", gfp.filename)
+	error_with_position(pp, scp, i18n("infinite loop, \"$name\" depends on itself"))
+	sub_context_delete(scp)
+
+	start := len(gp.stack)
+	for j := len(gp.stack) - 1; j >= 0; j-- {
+		if gp.stack[j].gfp == gfp {
+			start = j
+			break
+		}
+	}
+	for j := start; j < len(gp.stack); j++ {
+		next := gfp
+		if j+1 < len(gp.stack) {
+			next = gp.stack[j+1].gfp
+		}
+		scp = sub_context_new()
+		sub_var_set_string(scp, "Name", gp.stack[j].gfp.filename)
+		sub_var_set_string(scp, "Ingredient", next.filename)
+		error_with_position(&gp.stack[j].rp.pos, scp, i18n("\"$name\" needs \"$ingredient\""))
+		sub_context_delete(scp)
+	}
+}
+
+/*
+ * NAME
+ *      graph_build - add a file to the graph
+ *
+ * SYNOPSIS
+ *      graph_build_status_ty graph_build(graph_ty *, string_ty *filename,
+ *              expr_position_ty *pp, int backtrack);
+ *
+ * DESCRIPTION
+ *      The graph_build function is used to add a file, and everything
+ *      needed to make it, to the dependency graph.  Files are only
+ *      considered once; the result is remembered for the next time
+ *      the file is asked for.
+ *
+ *      If backtrack is non-zero, a file which can't be made is not an
+ *      error, the caller will try something else.  This is the case for
+ *      the ingredients of implicit recipes.  The pp argument is the
+ *      position of the recipe asking, for error messages.
+ *
+ *      A file which is needed, directly or indirectly, to make itself
+ *      is an infinite loop.  It is reported (see graph_build_loop), or
+ *      if backtrack is non-zero, the caller tries something else.
+ *
+ * RETURNS
+ *      graph_build_status_ty;
+ *      graph_build_status_success if the file can be made (or exists),
+ *      graph_build_status_backtrack if not (and backtrack is non-zero),
+ *      graph_build_status_error on error (already reported).
+ */
+
+func graph_build(gp *graph_ty, filename *string_ty, pp *expr_position_ty, backtrack int) graph_build_status_ty {
+	trace(fmt.Sprintf("graph_build(filename = %q, backtrack = %d)\n{\n", filename.str, backtrack))
+	gfp, ok := symtab_query(gp.already, filename).(*graph_file_ty)
+	if ok {
+		status := graph_build_status_success
+		switch {
+		case gfp.building != 0:
+			gp.statistic.infinite_loop++
+			status = graph_build_status_backtrack
+			if backtrack == 0 {
+				graph_build_loop(gp, gfp, pp)
+				status = graph_build_status_error
+			}
+
+		case gfp.previous_error != 0:
+			gp.statistic.error_cache++
+			status = graph_build_status_error
+
+		case gfp.previous_backtrack != 0:
+			gp.statistic.backtrack_cache++
+			status = graph_build_status_backtrack
+			if backtrack == 0 {
+				graph_build_dont_know(gp, gfp, pp)
+				status = graph_build_status_error
+			}
+
+		default:
+			gp.statistic.success_reuse++
+		}
+		trace(fmt.Sprintf("return %d;\n", status))
+		trace("}\n")
+		return status
+	}
+
+	gfp = graph_file_find(gp, filename)
+	gfp.building = 1
+	status := graph_build_file(gp, gfp, pp, backtrack)
+	gfp.building = 0
+	switch status {
+	case graph_build_status_error:
+		gfp.previous_error = 1
+
+	case graph_build_status_backtrack:
+		gfp.previous_backtrack = 1
+
+	case graph_build_status_success:
+		gp.statistic.success++
+	}
+	trace(fmt.Sprintf("return %d;\n", status))
+	trace("}\n")
+	return status
+}
+
+/*
+ * NAME
+ *      graph_build_list - add files to the graph
+ *
+ * SYNOPSIS
+ *      graph_build_status_ty graph_build_list(graph_ty *,
+ *              string_list_ty *targets, expr_position_ty *pp);
+ *
+ * DESCRIPTION
+ *      The graph_build_list function is used to add each of the given
+ *      files to the graph, as primary targets.  All of the targets are
+ *      attempted, even if some of them fail, so that all errors are
+ *      reported.
+ *
+ * RETURNS
+ *      graph_build_status_ty; the worst of the individual results.
+ */
+
+func graph_build_list(gp *graph_ty, targets *string_list_ty, pp *expr_position_ty) graph_build_status_ty {
+	result := graph_build_status_success
+	for _, s := range targets.strings {
+		status := graph_build(gp, s, pp, 0)
+		graph_file_find(gp, s).primary_target = 1
+		if status < result {
+			result = status
+		}
+		if desist_requested() {
+			return graph_build_status_error
+		}
+	}
+	return result
+}
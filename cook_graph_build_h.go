@@ -0,0 +1,60 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+type graph_build_status_ty int
+
+// enum graph_build_status_ty
+const (
+	graph_build_status_error     graph_build_status_ty = iota
+	graph_build_status_backtrack                       /* no recipe applies, try something else */
+	graph_build_status_rejected                        /* the recipe's precondition is false */
+	graph_build_status_success
+)
+
+/*
+ * A recipe which was considered for making a file, but not used,
+ * remembered for the "don't know how" error message.
+ */
+
+type graph_build_reject_reason_ty int
+
+// enum graph_build_reject_reason_ty
+const (
+	graph_build_reject_ingredient   graph_build_reject_reason_ty = iota /* an ingredient can't be made */
+	graph_build_reject_precondition                                     /* the precondition is false */
+	graph_build_reject_inhibited                                        /* already in use further up */
+)
+
+type graph_build_reject_ty struct {
+	rp         *recipe_ty
+	reason     graph_build_reject_reason_ty
+	ingredient *string_ty /* can't be made; NULL unless the reason is ingredient */
+}
+
+/*
+ * A recipe instance whose ingredients are being added to the graph,
+ * kept on a stack so that a loop can be reported in full.
+ */
+
+type graph_build_frame_ty struct {
+	gfp *graph_file_ty /* the file being made */
+	rp  *recipe_ty     /* the recipe making it */
+}
@@ -0,0 +1,342 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestGraphBuild(t *testing.T) {
+	tests := []struct {
+		name      string
+		book      string
+		files     []string
+		target    string
+		backtrack int
+		want      graph_build_status_ty
+		line      long /* of the recipe which makes the target */
+		phony     int
+		loop      long /* statistic.infinite_loop */
+		inhibit   long /* statistic.inhibit_self_recursion */
+		bad_path  long /* statistic.backtrack_bad_path */
+		err       string
+	}{
+		{
+			name: "first implicit recipe",
+			book: "%.o: %.c { echo c; }\n" +
+				"%.o: %.s { echo s; }\n",
+			files:  []string{"x.c", "x.s"},
+			target: "x.o",
+			want:   graph_build_status_success,
+			line:   1,
+		},
+		{
+			name: "backtrack to second implicit recipe",
+			book: "%.o: %.c { echo c; }\n" +
+				"%.o: %.s { echo s; }\n",
+			files:    []string{"x.s"},
+			target:   "x.o",
+			want:     graph_build_status_success,
+			line:     2,
+			bad_path: 1,
+		},
+		{
+			name: "backtrack through an intermediate file",
+			book: "%.o: %.c { echo c; }\n" +
+				"%.c: %.y { echo y; }\n" +
+				"%.o: %.s { echo s; }\n",
+			files:    []string{"x.s"},
+			target:   "x.o",
+			want:     graph_build_status_success,
+			line:     3,
+			bad_path: 2,
+		},
+		{
+			name: "no implicit recipe applies",
+			book: "%.o: %.c { echo c; }\n" +
+				"%.o: %.s { echo s; }\n",
+			target:   "x.o",
+			want:     graph_build_status_error,
+			bad_path: 2,
+			err:      `don't know how to cook "x.o"`,
+		},
+		{
+			name: "no implicit recipe applies, backtracking",
+			book: "%.o: %.c { echo c; }\n" +
+				"%.o: %.s { echo s; }\n",
+			target:    "x.o",
+			backtrack: 1,
+			want:      graph_build_status_backtrack,
+			bad_path:  2,
+		},
+		{
+			name: "explicit loop",
+			book: "a: b { echo a; }\n" +
+				"b: c { echo b; }\n" +
+				"c: a { echo c; }\n",
+			target: "a",
+			want:   graph_build_status_error,
+			loop:   1,
+			err:    `infinite loop, "a" depends on itself`,
+		},
+		{
+			name:   "explicit self loop",
+			book:   "a: a { echo a; }\n",
+			target: "a",
+			want:   graph_build_status_error,
+			loop:   1,
+			err:    `Howto.cook: 1: "a" needs "a"`,
+		},
+		{
+			name: "implicit loop is backtracked",
+			book: "%.x: %.y { echo x; }\n" +
+				"%.y: %.x { echo y; }\n" +
+				"%.x: %.z { echo z; }\n",
+			files:    []string{"f.z"},
+			target:   "f.x",
+			want:     graph_build_status_success,
+			line:     3,
+			loop:     1,
+			bad_path: 2,
+		},
+		{
+			name:    "implicit self recursion is inhibited",
+			book:    "%: %.gz { echo gunzip; }\n",
+			files:   []string{"x.gz"},
+			target:  "x",
+			want:    graph_build_status_success,
+			line:    1,
+			inhibit: 1,
+		},
+		{
+			name: "phony target",
+			book: "all: x;\n" +
+				"x: { echo x; }\n",
+			target: "all",
+			want:   graph_build_status_success,
+			line:   1,
+			phony:  1,
+		},
+		{
+			name: "target without body which exists is not phony",
+			book: "all: x;\n" +
+				"x: { echo x; }\n",
+			files:  []string{"all"},
+			target: "all",
+			want:   graph_build_status_success,
+			line:   1,
+		},
+		{
+			name:   "leaf exists",
+			book:   "all: x;\n",
+			files:  []string{"x"},
+			target: "x",
+			want:   graph_build_status_success,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cookbook_test_read(t, tt.book, tt.files...)
+			gp := graph_new()
+			defer graph_delete(gp)
+			target := str_from_string(tt.target)
+			var got graph_build_status_ty
+			msg := capture_stderr(t, func() {
+				got = graph_build(gp, target, nil, tt.backtrack)
+			})
+			if got != tt.want {
+				t.Errorf("graph_build(%q) = %d, want %d\n%s", tt.target, got, tt.want, msg)
+			}
+			if tt.err == "" && msg != "" {
+				t.Errorf("graph_build(%q) reported %q", tt.target, msg)
+			}
+			if tt.err != "" && !strings.Contains(msg, tt.err) {
+				t.Errorf("graph_build(%q) reported %q, want %q", tt.target, msg, tt.err)
+			}
+			if got == graph_build_status_success {
+				gfp := graph_file_find(gp, target)
+				var line long
+				if len(gfp.input.recipe) > 0 {
+					line = gfp.input.recipe[0].rp.pos.pos_line
+				}
+				if line != tt.line {
+					t.Errorf("%q made by the recipe at line %d, want %d", tt.target, line, tt.line)
+				}
+				if gfp.phony != tt.phony {
+					t.Errorf("%q phony = %d, want %d", tt.target, gfp.phony, tt.phony)
+				}
+			}
+			if gp.statistic.infinite_loop != tt.loop {
+				t.Errorf("infinite_loop = %d, want %d", gp.statistic.infinite_loop, tt.loop)
+			}
+			if gp.statistic.inhibit_self_recursion != tt.inhibit {
+				t.Errorf("inhibit_self_recursion = %d, want %d", gp.statistic.inhibit_self_recursion, tt.inhibit)
+			}
+			if gp.statistic.backtrack_bad_path != tt.bad_path {
+				t.Errorf("backtrack_bad_path = %d, want %d", gp.statistic.backtrack_bad_path, tt.bad_path)
+			}
+		})
+	}
+}
+
+func TestGraphBuildDontKnow(t *testing.T) {
+	book := "%.o: %.c { echo c; }\n" +
+		"%.o: %.s if \"\" { echo s; }\n" +
+		"%: %.gz { echo gunzip; }\n"
+	cookbook_test_read(t, book, "x.s")
+	gp := graph_new()
+	defer graph_delete(gp)
+	var got graph_build_status_ty
+	msg := capture_stderr(t, func() {
+		got = graph_build(gp, str_from_string("x.o"), nil, 0)
+	})
+	if got != graph_build_status_error {
+		t.Fatalf("graph_build = %d, want %d\n%s", got, graph_build_status_error, msg)
+	}
+	want := []string{
+		`cook: don't know how to cook "x.o"`,
+		`cook: Howto.cook: 1: recipe for "x.o" not used, can't cook "x.c"`,
+		`cook: Howto.cook: 2: recipe for "x.o" not used, precondition is false`,
+		`cook: Howto.cook: 3: recipe for "x.o" not used, can't cook "x.o.gz"`,
+		`cook: Howto.cook: 3: recipe for "x.c" not used, can't cook "x.c.gz"`,
+		`cook: Howto.cook: 3: recipe for "x.c.gz" not used, it is already in use further up`,
+		`cook: Howto.cook: 3: recipe for "x.o.gz" not used, it is already in use further up`,
+		`cook: tried "x.c.gz", "x.c", "x.o.gz" for "x.o", which do not exist and can't be cooked`,
+	}
+	if lines := strings.Split(strings.TrimSpace(msg), "\n"); strings.Join(lines, "\n") != strings.Join(want, "\n") {
+		t.Errorf("graph_build reported\n%s\nwant\n%s", msg, strings.Join(want, "\n"))
+	}
+}
+
+func TestGraphInteriorAndLeafFiles(t *testing.T) {
+	book := "all: x;\n" +
+		"x: y { echo x; }\n"
+	cookbook_test_read(t, book, "y")
+	gp := graph_new()
+	defer graph_delete(gp)
+	targets := test_string_list("all")
+	defer string_list_delete(targets)
+	if got := graph_build_list(gp, targets, nil); got != graph_build_status_success {
+		t.Fatalf("graph_build = %d, want %d", got, graph_build_status_success)
+	}
+	interior := &string_list_ty{}
+	leaf := &string_list_ty{}
+	graph_interior_and_leaf_files(gp, interior, leaf)
+	if got := wl2str(interior, 0, len(interior.strings)-1, " ").str; got != "x" {
+		t.Errorf("interior = %q, want %q", got, "x")
+	}
+	if got := wl2str(leaf, 0, len(leaf.strings)-1, " ").str; got != "y" {
+		t.Errorf("leaf = %q, want %q", got, "y")
+	}
+}
+
+/*
+ * With gate-first, a false precondition short-circuits the
+ * ingredients; otherwise they are evaluated first.
+ */
+
+func TestGraphBuildGateFirst(t *testing.T) {
+	tests := []struct {
+		name      string
+		book      string
+		evaluated bool
+		want      graph_build_status_ty
+	}{
+		{
+			name:      "gate last",
+			book:      "x: [collect touch evaluated] if \"\" { echo x; }\n",
+			evaluated: true,
+			want:      graph_build_status_error,
+		},
+		{
+			name: "gate first",
+			book: "x: [collect touch evaluated] set gate-first if \"\" { echo x; }\n",
+			want: graph_build_status_error,
+		},
+		{
+			name:      "gate first, true",
+			book:      "x: [collect touch evaluated] set gate-first if yes { echo x; }\n",
+			evaluated: true,
+			want:      graph_build_status_success,
+		},
+		{
+			name: "global gate first",
+			book: "set gate-first;\nx: [collect touch evaluated] if \"\" { echo x; }\n",
+			want: graph_build_status_error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cookbook_test_read(t, tt.book)
+			gp := graph_new()
+			defer graph_delete(gp)
+			var got graph_build_status_ty
+			msg := capture_stderr(t, func() {
+				got = graph_build(gp, str_from_string("x"), nil, 0)
+			})
+			if got != tt.want {
+				t.Errorf("graph_build = %d, want %d\n%s", got, tt.want, msg)
+			}
+			_, err := os.Stat("evaluated")
+			if evaluated := err == nil; evaluated != tt.evaluated {
+				t.Errorf("ingredients evaluated = %v, want %v", evaluated, tt.evaluated)
+			}
+			var rejections long
+			if tt.want != graph_build_status_success {
+				rejections = 1
+			}
+			if gp.statistic.precondition_rejection != rejections {
+				t.Errorf("precondition_rejection = %d, want %d", gp.statistic.precondition_rejection, rejections)
+			}
+		})
+	}
+}
+
+/*
+ * The loop report names each step of the loop, at the position of the
+ * recipe responsible, and only the steps which are part of the loop.
+ */
+
+func TestGraphBuildLoopReport(t *testing.T) {
+	book := "all: a;\n" +
+		"a: b { echo a; }\n" +
+		"\n" +
+		"b: c { echo b; }\n" +
+		"c: a { echo c; }\n"
+	cookbook_test_read(t, book)
+	gp := graph_new()
+	defer graph_delete(gp)
+	var got graph_build_status_ty
+	msg := capture_stderr(t, func() {
+		got = graph_build(gp, str_from_string("all"), nil, 0)
+	})
+	if got != graph_build_status_error {
+		t.Errorf("graph_build = %d, want %d", got, graph_build_status_error)
+	}
+	want := "cook: Howto.cook: 5: infinite loop, \"a\" depends on itself\n" +
+		"cook: Howto.cook: 2: \"a\" needs \"b\"\n" +
+		"cook: Howto.cook: 4: \"b\" needs \"c\"\n" +
+		"cook: Howto.cook: 5: \"c\" needs \"a\"\n"
+	if msg != want {
+		t.Errorf("graph_build reported\n%s\nwant\n%s", msg, want)
+	}
+}
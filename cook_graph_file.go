@@ -0,0 +1,123 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import "fmt"
+
+/*
+ * NAME
+ *      graph_file_new - create a file node
+ *
+ * SYNOPSIS
+ *      graph_file_ty *graph_file_new(string_ty *filename);
+ *
+ * DESCRIPTION
+ *      The graph_file_new function is used to create a new file node of
+ *      the dependency graph.  The file name is copied.
+ *
+ * RETURNS
+ *      graph_file_ty *; use graph_file_delete when you are done with it.
+ */
+
+func graph_file_new(filename *string_ty) *graph_file_ty {
+	trace(fmt.Sprintf("graph_file_new(filename = %q)\n{\n", filename.str))
+	gfp := &graph_file_ty{
+		reference_count: 1,
+		filename:        str_copy(filename),
+	}
+	trace(fmt.Sprintf("return %p;\n", gfp))
+	trace("}\n")
+	return gfp
+}
+
+/*
+ * NAME
+ *      graph_file_copy - copy a file node
+ *
+ * SYNOPSIS
+ *      graph_file_ty *graph_file_copy(graph_file_ty *);
+ *
+ * DESCRIPTION
+ *      The graph_file_copy function is used to make a copy of a file
+ *      node.  This is simply a reference count increment.
+ */
+
+func graph_file_copy(gfp *graph_file_ty) *graph_file_ty {
+	assert(gfp.reference_count > 0, "gfp.reference_count > 0")
+	gfp.reference_count++
+	return gfp
+}
+
+/*
+ * NAME
+ *      graph_file_delete - release a file node
+ *
+ * SYNOPSIS
+ *      void graph_file_delete(graph_file_ty *);
+ *
+ * DESCRIPTION
+ *      The graph_file_delete function is used to release a file node
+ *      when it is finished with.  The recipe lists do not own the
+ *      recipe instances, the graph does.
+ */
+
+func graph_file_delete(gfp *graph_file_ty) {
+	assert(gfp.reference_count > 0, "gfp.reference_count > 0")
+	gfp.reference_count--
+	if gfp.reference_count > 0 {
+		return
+	}
+	str_free(gfp.filename)
+	gfp.filename = nil
+	for _, r := range gfp.rejected {
+		if r.ingredient != nil {
+			str_free(r.ingredient)
+		}
+	}
+	gfp.rejected = nil
+	gfp.input.recipe = nil
+	gfp.output.recipe = nil
+}
+
+/*
+ * NAME
+ *      graph_file_find - find a file node
+ *
+ * SYNOPSIS
+ *      graph_file_ty *graph_file_find(graph_ty *, string_ty *filename);
+ *
+ * DESCRIPTION
+ *      The graph_file_find function is used to find the node of the
+ *      given file in the graph's symbol table of files already
+ *      considered.  A new node is created if the file has not been seen
+ *      before.
+ *
+ * RETURNS
+ *      graph_file_ty *; the graph owns it, do not delete it.
+ */
+
+func graph_file_find(gp *graph_ty, filename *string_ty) *graph_file_ty {
+	if gfp, ok := symtab_query(gp.already, filename).(*graph_file_ty); ok {
+		return gfp
+	}
+	gfp := graph_file_new(filename)
+	symtab_assign(gp.already, filename, gfp)
+	return gfp
+}
@@ -21,9 +21,9 @@ package main
 
 type graph_file_ty struct {
 	reference_count    long
-	filename           string_ty
-	input              graph_recipe_list_nrc_ty
-	output             *graph_recipe_list_nrc_ty
+	filename           *string_ty
+	input              graph_recipe_list_nrc_ty /* recipes which make this file */
+	output             graph_recipe_list_nrc_ty /* recipes which use this file */
 	pending            long
 	previous_backtrack int
 	previous_error     int
@@ -32,4 +32,7 @@ type graph_file_ty struct {
 	done               long   /* used by graph_walk */
 	input_uptodate     size_t /* used by graph_walk */
 	primary_target     int
+	phony              int                     /* recipes, but no body and no file */
+	rejected           []graph_build_reject_ty /* used by graph_build */
+	building           int                     /* used by graph_build */
 }
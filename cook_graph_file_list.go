@@ -0,0 +1,127 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+/*
+ * NAME
+ *      graph_file_list_constructor
+ *
+ * SYNOPSIS
+ *      void graph_file_list_constructor(graph_file_list_ty *);
+ *
+ * DESCRIPTION
+ *      The graph_file_list_constructor function is used to prepare a
+ *      file list for use.  It will be empty.
+ */
+
+func graph_file_list_constructor(gflp *graph_file_list_ty) {
+	gflp.item = nil
+}
+
+/*
+ * NAME
+ *      graph_file_list_destructor
+ *
+ * SYNOPSIS
+ *      void graph_file_list_destructor(graph_file_list_ty *);
+ *
+ * DESCRIPTION
+ *      The graph_file_list_destructor function is used to release the
+ *      files of a list.  Each file's reference count is decremented.
+ */
+
+func graph_file_list_destructor(gflp *graph_file_list_ty) {
+	for _, item := range gflp.item {
+		graph_file_delete(item.file)
+	}
+	gflp.item = nil
+}
+
+/*
+ * NAME
+ *      graph_file_list_append
+ *
+ * SYNOPSIS
+ *      void graph_file_list_append(graph_file_list_ty *, graph_file_ty *,
+ *              edge_type_ty);
+ *
+ * DESCRIPTION
+ *      The graph_file_list_append function is used to append a file to
+ *      a list, together with the type of the edge.  The file is copied.
+ */
+
+func graph_file_list_append(gflp *graph_file_list_ty, gfp *graph_file_ty, et edge_type_ty) {
+	gflp.item = append(gflp.item, graph_file_and_type_ty{file: graph_file_copy(gfp), edge_type: et})
+}
+
+/*
+ * NAME
+ *      graph_file_list_nrc_new
+ *
+ * SYNOPSIS
+ *      graph_file_list_nrc_ty *graph_file_list_nrc_new(void);
+ *
+ * DESCRIPTION
+ *      The graph_file_list_nrc_new function is used to create a new,
+ *      empty, file list which does not touch the reference counts of
+ *      the files it holds.  The graph's symbol table of files owns
+ *      them.
+ */
+
+func graph_file_list_nrc_new() *graph_file_list_nrc_ty {
+	return &graph_file_list_nrc_ty{}
+}
+
+/*
+ * NAME
+ *      graph_file_list_nrc_delete
+ *
+ * SYNOPSIS
+ *      void graph_file_list_nrc_delete(graph_file_list_nrc_ty *);
+ *
+ * DESCRIPTION
+ *      The graph_file_list_nrc_delete function is used to release a
+ *      file list.  The files are not touched.
+ */
+
+func graph_file_list_nrc_delete(gflp *graph_file_list_nrc_ty) {
+	if gflp == nil {
+		return
+	}
+	gflp.item = nil
+}
+
+/*
+ * NAME
+ *      graph_file_list_nrc_append
+ *
+ * SYNOPSIS
+ *      void graph_file_list_nrc_append(graph_file_list_nrc_ty *,
+ *              graph_file_ty *, edge_type_ty);
+ *
+ * DESCRIPTION
+ *      The graph_file_list_nrc_append function is used to append a file
+ *      to a list, together with the type of the edge.  The file is not
+ *      copied.
+ */
+
+func graph_file_list_nrc_append(gflp *graph_file_list_nrc_ty, gfp *graph_file_ty, et edge_type_ty) {
+	gflp.item = append(gflp.item, graph_file_and_type_ty{file: gfp, edge_type: et})
+}
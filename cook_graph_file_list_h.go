@@ -25,16 +25,16 @@ type graph_file_and_type_ty struct {
 }
 
 type graph_file_list_ty struct {
-	nfiles     size_t
-	nfiles_max size_t
-	item       *graph_file_and_type_ty
+	// nfiles     size_t
+	// nfiles_max size_t
+	item []graph_file_and_type_ty
 }
 
 /*
  * again, this time without touching the reference counts...
  */
 type graph_file_list_nrc_ty struct {
-	nfiles     size_t
-	nfiles_max size_t
-	item       *graph_file_and_type_ty
+	// nfiles     size_t
+	// nfiles_max size_t
+	item []graph_file_and_type_ty
 }
@@ -74,4 +74,10 @@ type graph_ty struct {
 	 * information residing only in dependency files.
 	 */
 	file_pair *graph_file_pair_ty
+
+	/*
+	 * The recipe instances whose ingredients are being added to
+	 * the graph, innermost last.  Used to report infinite loops.
+	 */
+	stack []graph_build_frame_ty
 }
@@ -0,0 +1,151 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import "fmt"
+
+/*
+ * Each recipe instance is given a unique id, for tracing and for the
+ * walker's messages.
+ */
+var graph_recipe_id int
+
+/*
+ * NAME
+ *      graph_recipe_new - create a recipe instance
+ *
+ * SYNOPSIS
+ *      graph_recipe_ty *graph_recipe_new(recipe_ty *, match_ty *);
+ *
+ * DESCRIPTION
+ *      The graph_recipe_new function is used to create a new instance
+ *      of a recipe, for use in the dependency graph.  The recipe is
+ *      copied.  The match, which is NULL for explicit recipes, is taken
+ *      over; it supplies the fields when the recipe body is run.
+ *
+ * RETURNS
+ *      graph_recipe_ty *; use graph_recipe_delete when you are done
+ *      with it.
+ */
+
+func graph_recipe_new(rp *recipe_ty, mp *match_ty) *graph_recipe_ty {
+	trace(fmt.Sprintf("graph_recipe_new(rp = %p)\n{\n", rp))
+	graph_recipe_id++
+	grp := &graph_recipe_ty{
+		reference_count: 1,
+		id:              graph_recipe_id,
+		rp:              recipe_copy(rp),
+		mp:              mp,
+		input:           graph_file_list_nrc_new(),
+		output:          graph_file_list_nrc_new(),
+	}
+	trace(fmt.Sprintf("return %p;\n", grp))
+	trace("}\n")
+	return grp
+}
+
+/*
+ * NAME
+ *      graph_recipe_copy - copy a recipe instance
+ *
+ * SYNOPSIS
+ *      graph_recipe_ty *graph_recipe_copy(graph_recipe_ty *);
+ *
+ * DESCRIPTION
+ *      The graph_recipe_copy function is used to make a copy of a
+ *      recipe instance.  This is simply a reference count increment.
+ */
+
+func graph_recipe_copy(grp *graph_recipe_ty) *graph_recipe_ty {
+	assert(grp.reference_count > 0, "grp.reference_count > 0")
+	grp.reference_count++
+	return grp
+}
+
+/*
+ * NAME
+ *      graph_recipe_delete - release a recipe instance
+ *
+ * SYNOPSIS
+ *      void graph_recipe_delete(graph_recipe_ty *);
+ *
+ * DESCRIPTION
+ *      The graph_recipe_delete function is used to release a recipe
+ *      instance when it is finished with.
+ */
+
+func graph_recipe_delete(grp *graph_recipe_ty) {
+	assert(grp.reference_count > 0, "grp.reference_count > 0")
+	grp.reference_count--
+	if grp.reference_count > 0 {
+		return
+	}
+	recipe_delete(grp.rp)
+	grp.rp = nil
+	match_delete(grp.mp)
+	grp.mp = nil
+	graph_file_list_nrc_delete(grp.input)
+	grp.input = nil
+	graph_file_list_nrc_delete(grp.output)
+	grp.output = nil
+	if grp.single_thread != nil {
+		string_list_delete(grp.single_thread)
+		grp.single_thread = nil
+	}
+	if grp.host_binding != nil {
+		string_list_delete(grp.host_binding)
+		grp.host_binding = nil
+	}
+}
+
+/*
+ * NAME
+ *      graph_recipe_target_symtab
+ *
+ * SYNOPSIS
+ *      symtab_ty *graph_recipe_target_symtab(graph_recipe_ty *);
+ *
+ * DESCRIPTION
+ *      The graph_recipe_target_symtab function is used to create the
+ *      variables of a recipe instance's thread: "target" is the first
+ *      target, and "targets" is all of them.  The ingredients
+ *      variables are added by the walker.
+ *
+ * RETURNS
+ *      symtab_ty *; use symtab_free when you are done with it.
+ */
+
+func graph_recipe_target_symtab(grp *graph_recipe_ty) *symtab_ty {
+	stp := symtab_alloc(5)
+	stp.reap = id_global_reap
+	var wl string_list_ty
+	string_list_constructor(&wl)
+	if len(grp.output.item) > 0 {
+		string_list_append(&wl, grp.output.item[0].file.filename)
+	}
+	symtab_assign(stp, id_target, id_variable_new(&wl))
+	string_list_destructor(&wl)
+	for _, item := range grp.output.item {
+		string_list_append(&wl, item.file.filename)
+	}
+	symtab_assign(stp, id_targets, id_variable_new(&wl))
+	string_list_destructor(&wl)
+	return stp
+}
@@ -31,5 +31,6 @@ type graph_recipe_ty struct {
 	ocp             *opcode_context_ty /* used by graph_run */
 	single_thread   *string_list_ty
 	host_binding    *string_list_ty
-	multi_forced    int /* used by graph_walk */
+	multi_forced    int    /* used by graph_walk */
+	ingredients_fp  string /* used by graph_run */
 }
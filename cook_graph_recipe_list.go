@@ -0,0 +1,116 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+/*
+ * NAME
+ *      graph_recipe_list_new
+ *
+ * SYNOPSIS
+ *      graph_recipe_list_ty *graph_recipe_list_new(void);
+ *
+ * DESCRIPTION
+ *      The graph_recipe_list_new function is used to create a new,
+ *      empty, list of recipe instances.
+ */
+
+func graph_recipe_list_new() *graph_recipe_list_ty {
+	return &graph_recipe_list_ty{}
+}
+
+/*
+ * NAME
+ *      graph_recipe_list_delete
+ *
+ * SYNOPSIS
+ *      void graph_recipe_list_delete(graph_recipe_list_ty *);
+ *
+ * DESCRIPTION
+ *      The graph_recipe_list_delete function is used to release a list
+ *      of recipe instances.  Each recipe instance's reference count is
+ *      decremented.
+ */
+
+func graph_recipe_list_delete(grlp *graph_recipe_list_ty) {
+	if grlp == nil {
+		return
+	}
+	for _, grp := range grlp.recipe {
+		graph_recipe_delete(grp)
+	}
+	grlp.recipe = nil
+}
+
+/*
+ * NAME
+ *      graph_recipe_list_append
+ *
+ * SYNOPSIS
+ *      void graph_recipe_list_append(graph_recipe_list_ty *,
+ *              graph_recipe_ty *);
+ *
+ * DESCRIPTION
+ *      The graph_recipe_list_append function is used to append a recipe
+ *      instance to a list.  The recipe instance is copied.
+ */
+
+func graph_recipe_list_append(grlp *graph_recipe_list_ty, grp *graph_recipe_ty) {
+	grlp.recipe = append(grlp.recipe, graph_recipe_copy(grp))
+}
+
+/*
+ * NAME
+ *      graph_recipe_list_nrc_append
+ *
+ * SYNOPSIS
+ *      void graph_recipe_list_nrc_append(graph_recipe_list_nrc_ty *,
+ *              graph_recipe_ty *);
+ *
+ * DESCRIPTION
+ *      The graph_recipe_list_nrc_append function is used to append a
+ *      recipe instance to a list.  The recipe instance is not copied.
+ */
+
+func graph_recipe_list_nrc_append(grlp *graph_recipe_list_nrc_ty, grp *graph_recipe_ty) {
+	grlp.recipe = append(grlp.recipe, grp)
+}
+
+/*
+ * NAME
+ *      graph_recipe_list_nrc_remove
+ *
+ * SYNOPSIS
+ *      void graph_recipe_list_nrc_remove(graph_recipe_list_nrc_ty *,
+ *              graph_recipe_ty *);
+ *
+ * DESCRIPTION
+ *      The graph_recipe_list_nrc_remove function is used to remove a
+ *      recipe instance from a list, if it is present.  The order of the
+ *      remaining recipe instances is preserved.
+ */
+
+func graph_recipe_list_nrc_remove(grlp *graph_recipe_list_nrc_ty, grp *graph_recipe_ty) {
+	for j, p := range grlp.recipe {
+		if p == grp {
+			grlp.recipe = append(grlp.recipe[:j], grlp.recipe[j+1:]...)
+			return
+		}
+	}
+}
@@ -20,16 +20,16 @@
 package main
 
 type graph_recipe_list_ty struct {
-	nrecipes     size_t
-	nrecipes_max size_t
-	recipe       **graph_recipe_ty
+	// nrecipes     size_t
+	// nrecipes_max size_t
+	recipe []*graph_recipe_ty
 }
 
 /*
  * again, this time ignoring reference counts
  */
 type graph_recipe_list_nrc_ty struct {
-	nrecipes     size_t
-	nrecipes_max size_t
-	recipe       **graph_recipe_ty
+	// nrecipes     size_t
+	// nrecipes_max size_t
+	recipe []*graph_recipe_ty
 }
@@ -0,0 +1,378 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+/*
+ * NAME
+ *      graph_recipe_ingredients_fingerprint
+ *
+ * SYNOPSIS
+ *      string graph_recipe_ingredients_fingerprint(graph_recipe_ty *,
+ *              string_list_ty *need);
+ *
+ * DESCRIPTION
+ *      The graph_recipe_ingredients_fingerprint function is used to
+ *      calculate a digest of the resolved ingredients list of a recipe
+ *      instance and the text of its body (see opcode_list_text).  If
+ *      it differs from the digest remembered when the targets were
+ *      last cooked, the targets are out of date, even though no
+ *      timestamps have moved; for example, when a file has been
+ *      removed from a [glob] of ingredients.  The body is not run, so
+ *      asking whether the targets are out of date has no side effects.
+ *
+ * RETURNS
+ *      string; the digest.
+ */
+
+func graph_recipe_ingredients_fingerprint(grp *graph_recipe_ty, need *string_list_ty) string {
+	h := sha256.New()
+	for _, s := range need.strings {
+		_, _ = fmt.Fprintf(h, "%q\n", s.str)
+	}
+	if grp.rp.out_of_date != nil {
+		_, _ = fmt.Fprintf(h, "\n%s", opcode_list_text(grp.rp.out_of_date))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+/*
+ * NAME
+ *      graph_file_mtime_oldest - age of an ingredient
+ *
+ * SYNOPSIS
+ *      long graph_file_mtime_oldest(graph_file_ty *);
+ *
+ * DESCRIPTION
+ *      The graph_file_mtime_oldest function is used to obtain the age
+ *      of an ingredient (see search_list_mtime_oldest).  A phony file
+ *      has no age of its own, it is as old as the youngest of its
+ *      ingredients, so that recipes which use a phony target are only
+ *      out of date when something beneath it has changed.
+ *
+ * RETURNS
+ *      long; nanoseconds since the epoch, 0 if there is nothing, -1 on
+ *      error (already reported).
+ */
+
+func graph_file_mtime_oldest(gfp *graph_file_ty) long {
+	if gfp.phony == 0 {
+		return search_list_mtime_oldest(gfp.filename)
+	}
+	result := long(0)
+	for _, grp := range gfp.input.recipe {
+		for _, item := range grp.input.item {
+			mtime := graph_file_mtime_oldest(item.file)
+			if mtime < 0 {
+				return -1
+			}
+			if mtime > result {
+				result = mtime
+			}
+		}
+	}
+	return result
+}
+
+/*
+ * NAME
+ *      graph_recipe_out_of_date - are the targets out of date
+ *
+ * SYNOPSIS
+ *      int graph_recipe_out_of_date(graph_recipe_ty *,
+ *              string_list_ty *need, string_list_ty *younger);
+ *
+ * DESCRIPTION
+ *      The graph_recipe_out_of_date function is used to determine
+ *      whether the targets of a recipe instance are out of date.  They
+ *      are if any of them does not exist, or if any ingredient is
+ *      missing or younger than the oldest target.  Weak ingredients are
+ *      cooked first, but never make the targets out of date; for
+ *      exists ingredients, only their existence matters, not their age.
+ *
+ *      When the fingerprint option is in force, the age of an
+ *      ingredient is the time its contents last changed, rather than
+ *      the time it was last written (see stat_cache_mtime_oldest).
+ *      Copies of the files along the search list are considered too
+ *      (see search_list_mtime_newest and search_list_mtime_oldest).
+ *      Phony ingredients take the age of their own ingredients (see
+ *      graph_file_mtime_oldest).
+ *
+ *      With the force flag, the targets are always out of date.  When
+ *      the ingredients-fingerprint option is in force, the targets are
+ *      also out of date if the ingredients list or the recipe body
+ *      have changed since they were last cooked.
+ *
+ *      All of the ingredients are appended to need, and those which
+ *      make the targets out of date are appended to younger.  The
+ *      recipe's flags are expected to be in force.
+ *
+ * RETURNS
+ *      int; 1 if out of date, 0 if not, -1 on error (already reported).
+ */
+
+func graph_recipe_out_of_date(grp *graph_recipe_ty, need, younger *string_list_ty) int {
+	result := 0
+	oldest := long(-1)
+	for _, item := range grp.output.item {
+		mtime := search_list_mtime_newest(item.file.filename)
+		if mtime < 0 {
+			return -1
+		}
+		if mtime == 0 {
+			result = 1
+		}
+		if oldest < 0 || mtime < oldest {
+			oldest = mtime
+		}
+	}
+	for _, item := range grp.input.item {
+		name := item.file.filename
+		mtime := graph_file_mtime_oldest(item.file)
+		if mtime < 0 {
+			return -1
+		}
+		item.file.mtime_oldest = mtime
+		string_list_append(need, name)
+		if item.file.phony != 0 && mtime == 0 {
+			/* nothing beneath it */
+			continue
+		}
+		switch {
+		case item.edge_type&edge_type_weak != 0:
+			continue
+
+		case item.edge_type&edge_type_exists != 0:
+			if mtime != 0 {
+				continue
+			}
+		}
+		if mtime == 0 || mtime > oldest {
+			string_list_append(younger, name)
+			result = 1
+		}
+	}
+
+	if option_test(OPTION_FORCE) {
+		result = 1
+	}
+
+	grp.ingredients_fp = ""
+	if option_test(OPTION_INGREDIENTS_FINGERPRINT) {
+		grp.ingredients_fp = graph_recipe_ingredients_fingerprint(grp, need)
+		changed := false
+		for _, item := range grp.output.item {
+			old := fingerprint_ingredients_get(item.file.filename)
+			if old != "" && old != grp.ingredients_fp {
+				changed = true
+			}
+		}
+		if changed {
+			result = 1
+		}
+
+		/*
+		 * Targets with no digest yet (cooked before the option was
+		 * used, say) are not out of date because of it; the digest is
+		 * simply recorded.
+		 */
+		if result == 0 {
+			for _, item := range grp.output.item {
+				fingerprint_ingredients_set(item.file.filename, grp.ingredients_fp)
+			}
+		}
+	}
+	return result
+}
+
+/*
+ * NAME
+ *      graph_recipe_run - start a recipe instance
+ *
+ * SYNOPSIS
+ *      graph_walk_status_ty graph_recipe_run(graph_recipe_ty *,
+ *              graph_ty *, string_ty *host);
+ *
+ * DESCRIPTION
+ *      The graph_recipe_run function is used to start running a recipe
+ *      instance, once all of its ingredients have been cooked.  The
+ *      out_of_date body is run if the targets are out of date (see
+ *      graph_recipe_out_of_date), otherwise the up_to_date body, if
+ *      any.
+ *
+ *      The body runs in a thread of its own, with the "target",
+ *      "targets", "need" and "younger" variables defined.  Fields of an
+ *      implicit recipe's match are available to the body's commands.
+ *      If host is not NULL, the body's commands are run on that host.
+ *
+ *      Before an out_of_date body runs, symbolic links to deeper copies
+ *      of the targets are removed, and the mkdir and unlink flags are
+ *      acted upon (see graph_recipe_prepare_targets).  Before any body
+ *      runs, with the symlink-ingredients flag,
+ *      ingredients found deeper in the search list are linked into the
+ *      first directory (see graph_recipe_symlink_ingredients).
+ *
+ * RETURNS
+ *      graph_walk_status_ty; graph_walk_status_wait if the body is
+ *      waiting for a child process, see graph_recipe_run_resume.
+ */
+
+func graph_recipe_run(grp *graph_recipe_ty, gp *graph_ty, host *string_ty) graph_walk_status_ty {
+	trace(fmt.Sprintf("graph_recipe_run(grp = %d)\n{\n", grp.id))
+	rp := grp.rp
+	assert(grp.ocp == nil, "grp.ocp == nil")
+
+	var need, younger string_list_ty
+	defer string_list_destructor(&need)
+	defer string_list_destructor(&younger)
+	flag_set_options(rp.flags, OPTION_LEVEL_RECIPE)
+	out_of_date := graph_recipe_out_of_date(grp, &need, &younger)
+	option_undo_level(OPTION_LEVEL_RECIPE)
+	if out_of_date < 0 {
+		trace("return error;\n}\n")
+		return graph_walk_status_error
+	}
+
+	body := rp.up_to_date
+	if out_of_date > 0 {
+		body = rp.out_of_date
+	}
+	if body == nil {
+		trace("return uptodate;\n}\n")
+		return graph_walk_status_uptodate
+	}
+
+	/*
+	 * Targets linked to a deeper copy are about to be cooked here;
+	 * the body must not write through the link.
+	 */
+	flag_set_options(rp.flags, OPTION_LEVEL_RECIPE)
+	status := 0
+	if out_of_date > 0 {
+		status = graph_recipe_symlink_targets(grp)
+		if status == 0 {
+			status = graph_recipe_prepare_targets(grp)
+		}
+	}
+	if status == 0 && option_test(OPTION_SYMLINK_INGREDIENTS) {
+		status = graph_recipe_symlink_ingredients(grp)
+	}
+	option_undo_level(OPTION_LEVEL_RECIPE)
+	if status < 0 {
+		trace("return error;\n}\n")
+		return graph_walk_status_error
+	}
+
+	stp := graph_recipe_target_symtab(grp)
+	symtab_assign(stp, id_need, id_variable_new(&need))
+	symtab_assign(stp, id_younger, id_variable_new(&younger))
+	grp.ocp = opcode_context_new(body, stp)
+	grp.ocp.gp = gp
+	if host != nil {
+		grp.ocp.host_binding = str_copy(host)
+	}
+	if grp.mp != nil {
+		opcode_context_match_push(grp.ocp, grp.mp)
+	}
+	wstatus := graph_recipe_run_resume(grp, gp)
+	trace(fmt.Sprintf("return %d;\n", wstatus))
+	trace("}\n")
+	return wstatus
+}
+
+/*
+ * NAME
+ *      graph_recipe_run_resume - continue a recipe instance
+ *
+ * SYNOPSIS
+ *      graph_walk_status_ty graph_recipe_run_resume(graph_recipe_ty *,
+ *              graph_ty *);
+ *
+ * DESCRIPTION
+ *      The graph_recipe_run_resume function is used to continue running
+ *      the body of a recipe instance, after its child process has
+ *      exited (the exit status is placed in the context by the
+ *      caller).  The recipe's flags are in force while it runs.  Once
+ *      the body completes, the thread is released, the status of the
+ *      targets is forgotten by the stat cache, and if it succeeded the
+ *      ingredients fingerprint of the targets is remembered.  If it
+ *      failed, the targets are removed, unless they are precious.
+ *
+ * RETURNS
+ *      graph_walk_status_ty; graph_walk_status_wait if the body is
+ *      waiting for another child process.
+ */
+
+func graph_recipe_run_resume(grp *graph_recipe_ty, gp *graph_ty) graph_walk_status_ty {
+	ocp := grp.ocp
+	assert(ocp != nil, "grp.ocp != nil")
+	flag_set_options(grp.rp.flags, OPTION_LEVEL_RECIPE)
+	status := opcode_context_execute(ocp)
+	option_undo_level(OPTION_LEVEL_RECIPE)
+	if status == opcode_status_wait {
+		return graph_walk_status_wait
+	}
+
+	if status == opcode_status_success {
+		string_list_delete(opcode_context_string_list_pop(ocp))
+	}
+	if grp.mp != nil {
+		opcode_context_match_pop(ocp)
+	}
+	stp := ocp.thread_stp
+	opcode_context_delete(ocp)
+	symtab_free(stp)
+	grp.ocp = nil
+
+	/*
+	 * The body has (probably) changed the targets, and with the
+	 * clearstat flag, it may have changed anything.
+	 */
+	flag_set_options(grp.rp.flags, OPTION_LEVEL_RECIPE)
+	defer option_undo_level(OPTION_LEVEL_RECIPE)
+	if option_test(OPTION_INVALIDATE_STAT_CACHE) {
+		stat_cache_clear_all()
+	} else {
+		for _, item := range grp.output.item {
+			stat_cache_clear(item.file.filename)
+		}
+	}
+
+	switch status {
+	case opcode_status_success:
+		if grp.ingredients_fp != "" {
+			for _, item := range grp.output.item {
+				fingerprint_ingredients_set(item.file.filename, grp.ingredients_fp)
+			}
+		}
+		return graph_walk_status_done
+
+	case opcode_status_interrupted:
+		graph_recipe_failed_targets(grp)
+		return graph_walk_status_interrupted
+	}
+	graph_recipe_failed_targets(grp)
+	return graph_walk_status_error
+}
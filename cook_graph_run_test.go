@@ -0,0 +1,131 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+/*
+ * NAME
+ *      cook_test_run - cook, as a fresh process would
+ *
+ * DESCRIPTION
+ *      The cook_test_run function is used to cook the given targets of
+ *      the cookbook read by cookbook_test_read, forgetting the cached
+ *      file status and fingerprints first, the way a new cook process
+ *      would.  It returns the exit status and the error messages.
+ */
+
+func cook_test_run(t *testing.T, targets ...string) (int, string) {
+	t.Helper()
+	stat_cache_clear_all()
+	fingerprint_cache = make(map[string]*fingerprint_cache_ty)
+	var status int
+	msg := capture_stderr(t, func() {
+		status = cook(test_string_list(targets...))
+	})
+	return status, msg
+}
+
+/*
+ * NAME
+ *      file_test_age - set a file's modification time
+ *
+ * DESCRIPTION
+ *      The file_test_age function is used to make a file the given
+ *      number of seconds old, so that tests do not depend on the
+ *      resolution of the file system's timestamps.
+ */
+
+func file_test_age(t *testing.T, name string, seconds int) {
+	t.Helper()
+	when := time.Now().Add(-time.Duration(seconds) * time.Second)
+	if err := os.Chtimes(name, when, when); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestIngredientsFingerprint(t *testing.T) {
+	cookbook_test_read(t,
+		"set ingredients-fingerprint;\n"+
+			"x: [glob *.in] { cat [need] > [target]; }\n",
+		"a.in", "b.in")
+	file_test_age(t, "a.in", 100)
+	file_test_age(t, "b.in", 100)
+	if status, msg := cook_test_run(t, "x"); status != 0 {
+		t.Fatalf("first cook = %d\n%s", status, msg)
+	}
+	file_test_age(t, "x", 50)
+
+	/*
+	 * Nothing has changed, so nothing is cooked.
+	 */
+	if status, msg := cook_test_run(t, "x"); status != 0 {
+		t.Fatalf("second cook = %d\n%s", status, msg)
+	}
+	if fi, err := os.Stat("x"); err != nil || time.Since(fi.ModTime()) < 40*time.Second {
+		t.Fatalf("x was cooked again, although nothing changed")
+	}
+
+	/*
+	 * Removing an ingredient moves no timestamps, but the ingredients
+	 * list has changed.
+	 */
+	if err := os.Remove("b.in"); err != nil {
+		t.Fatal(err)
+	}
+	if status, msg := cook_test_run(t, "x"); status != 0 {
+		t.Fatalf("third cook = %d\n%s", status, msg)
+	}
+	if fi, err := os.Stat("x"); err != nil || time.Since(fi.ModTime()) > 40*time.Second {
+		t.Errorf("x was not cooked again when an ingredient was removed")
+	}
+}
+
+/*
+ * Asking whether an up to date target is out of date must not run
+ * any of its body.  A target with no digest recorded yet is not out
+ * of date because of it, the digest is recorded.
+ */
+
+func TestIngredientsFingerprintNoDigest(t *testing.T) {
+	cookbook_test_read(t,
+		"set ingredients-fingerprint;\n"+
+			"x: a.in { fail \"the body ran\"; }\n",
+		"a.in", "x")
+	file_test_age(t, "a.in", 100)
+	file_test_age(t, "x", 50)
+	status, msg := cook_test_run(t, "x")
+	if status != 0 || msg != "" {
+		t.Fatalf("cook = %d, want 0\n%s", status, msg)
+	}
+	data, err := ioutil.ReadFile(fingerprint_cache_name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(data), "\"x\" ") || strings.HasSuffix(strings.TrimSpace(string(data)), " -") {
+		t.Errorf("digest of x not recorded: %q", data)
+	}
+}
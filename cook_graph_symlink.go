@@ -0,0 +1,223 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+/*
+ * NAME
+ *      graph_symlink_copy - which copy a symbolic link refers to
+ *
+ * SYNOPSIS
+ *      int graph_symlink_copy(string_ty *link, string_list_ty *copies);
+ *
+ * DESCRIPTION
+ *      The graph_symlink_copy function is used to determine which of
+ *      the deeper copies of a file (see search_list_copies) the given
+ *      symbolic link contents refer to.  Only links made by
+ *      graph_recipe_symlink_ingredients will match, other symbolic
+ *      links belong to the user and are left alone.
+ *
+ * RETURNS
+ *      int; the index into copies, or -1 if the link is not ours.
+ */
+
+func graph_symlink_copy(link *string_ty, copies *string_list_ty) int {
+	for j := 1; j < len(copies.strings); j++ {
+		abs, err := filepath.Abs(copies.strings[j].str)
+		if err == nil && abs == link.str {
+			return j
+		}
+	}
+	return -1
+}
+
+/*
+ * NAME
+ *      graph_recipe_symlink_ingredients - materialise ingredients
+ *
+ * SYNOPSIS
+ *      int graph_recipe_symlink_ingredients(graph_recipe_ty *);
+ *
+ * DESCRIPTION
+ *      The graph_recipe_symlink_ingredients function is used, when the
+ *      symlink-ingredients flag is in force, to make each ingredient
+ *      of a recipe instance which is only found deeper in the search
+ *      list appear in the first directory, as a symbolic link to the
+ *      first copy which exists.  Some tools will not read files through
+ *      relative view paths.
+ *
+ *      Links made previously are checked: if the copy they refer to has
+ *      gone, or a nearer copy has since appeared, they are replaced.
+ *
+ * RETURNS
+ *      int; 0 on success, -1 on error (already reported).
+ */
+
+func graph_recipe_symlink_ingredients(grp *graph_recipe_ty) int {
+	trace(fmt.Sprintf("graph_recipe_symlink_ingredients(grp = %d)\n{\n", grp.id))
+	defer trace("}\n")
+	for _, item := range grp.input.item {
+		name := item.file.filename
+		var copies string_list_ty
+		search_list_copies(name, &copies)
+		status := graph_symlink_one(&copies)
+		string_list_destructor(&copies)
+		if status < 0 {
+			return -1
+		}
+	}
+	return 0
+}
+
+/*
+ * NAME
+ *      graph_symlink_one - materialise one ingredient
+ *
+ * SYNOPSIS
+ *      int graph_symlink_one(string_list_ty *copies);
+ *
+ * DESCRIPTION
+ *      The graph_symlink_one function is used to link, or re-link, the
+ *      first directory's copy of one ingredient, given all of its
+ *      copies along the search list.
+ *
+ * RETURNS
+ *      int; 0 on success, -1 on error (already reported).
+ */
+
+func graph_symlink_one(copies *string_list_ty) int {
+	if len(copies.strings) < 2 {
+		return 0
+	}
+	local := copies.strings[0]
+
+	/*
+	 * Find the first deeper copy which exists.
+	 */
+	want := -1
+	for j := 1; j < len(copies.strings); j++ {
+		exists := stat_cache_exists(copies.strings[j])
+		if exists < 0 {
+			return -1
+		}
+		if exists > 0 {
+			want = j
+			break
+		}
+	}
+
+	link, status := os_readlink(local)
+	if status < 0 {
+		return -1
+	}
+	if link != nil {
+		have := graph_symlink_copy(link, copies)
+		str_free(link)
+		if have < 0 {
+			return 0
+		}
+		if have == want {
+			return 0
+		}
+
+		/*
+		 * The link is stale: the copy it refers to is gone, or a
+		 * nearer copy has appeared.
+		 */
+		trace(fmt.Sprintf("remove stale %q\n", local.str))
+		stat_cache_clear(local)
+		if os_unlink(local) < 0 {
+			return -1
+		}
+	} else {
+		switch stat_cache_exists(local) {
+		case -1:
+			return -1
+
+		case 1:
+			/* a local copy */
+			return 0
+		}
+	}
+	if want < 0 {
+		return 0
+	}
+
+	abs, err := filepath.Abs(copies.strings[want].str)
+	if err != nil {
+		os_error(copies.strings[want], err, i18n("getcwd: $errno"))
+		return -1
+	}
+	target := str_from_string(abs)
+	defer str_free(target)
+	trace(fmt.Sprintf("symlink %q -> %q\n", local.str, target.str))
+	stat_cache_clear(local)
+	return os_symlink(target, local)
+}
+
+/*
+ * NAME
+ *      graph_recipe_symlink_targets - remove linked targets
+ *
+ * SYNOPSIS
+ *      int graph_recipe_symlink_targets(graph_recipe_ty *);
+ *
+ * DESCRIPTION
+ *      The graph_recipe_symlink_targets function is used, before the
+ *      body of a recipe instance runs, to remove any symbolic links to
+ *      deeper copies of its targets made by
+ *      graph_recipe_symlink_ingredients.  A local copy of the target is
+ *      about to appear, and the body must not write through the link
+ *      into the deeper directory.
+ *
+ * RETURNS
+ *      int; 0 on success, -1 on error (already reported).
+ */
+
+func graph_recipe_symlink_targets(grp *graph_recipe_ty) int {
+	for _, item := range grp.output.item {
+		name := item.file.filename
+		var copies string_list_ty
+		search_list_copies(name, &copies)
+		if len(copies.strings) < 2 {
+			string_list_destructor(&copies)
+			continue
+		}
+		local := copies.strings[0]
+		link, status := os_readlink(local)
+		if link != nil {
+			if graph_symlink_copy(link, &copies) >= 0 {
+				trace(fmt.Sprintf("remove linked target %q\n", local.str))
+				stat_cache_clear(local)
+				status = os_unlink(local)
+			}
+			str_free(link)
+		}
+		string_list_destructor(&copies)
+		if status < 0 {
+			return -1
+		}
+	}
+	return 0
+}
@@ -0,0 +1,130 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+/*
+ * NAME
+ *      symlink_test_one - materialise x
+ *
+ * DESCRIPTION
+ *      The symlink_test_one function is used to run graph_symlink_one
+ *      for the file "x", with fresh file status, and return where the
+ *      local "x" then points: "" if it is not a symbolic link, or
+ *      "missing" if there is nothing there.
+ */
+
+func symlink_test_one(t *testing.T) string {
+	t.Helper()
+	stat_cache_clear_all()
+	var copies string_list_ty
+	search_list_copies(str_from_string("x"), &copies)
+	defer string_list_destructor(&copies)
+	if msg := capture_stderr(t, func() {
+		if graph_symlink_one(&copies) != 0 {
+			t.Error("graph_symlink_one failed")
+		}
+	}); msg != "" {
+		t.Error(msg)
+	}
+	link, err := os.Readlink("x")
+	if err == nil {
+		return link
+	}
+	if _, err := os.Lstat("x"); os.IsNotExist(err) {
+		return "missing"
+	}
+	return ""
+}
+
+func TestGraphSymlinkOne(t *testing.T) {
+	cookbook_test_read(t, "search_list = . b1 b2;\n", "b2/x")
+	dir, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b1 := filepath.Join(dir, "b1", "x")
+	b2 := filepath.Join(dir, "b2", "x")
+	if err := os.Mkdir(filepath.Dir(b1), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := symlink_test_one(t); got != b2 {
+		t.Fatalf("link to %q, want %q", got, b2)
+	}
+
+	/*
+	 * A nearer copy appears: the link is replaced.
+	 */
+	if err := ioutil.WriteFile(b1, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := symlink_test_one(t); got != b1 {
+		t.Fatalf("nearer copy: link to %q, want %q", got, b1)
+	}
+
+	/*
+	 * The copy it refers to goes away: back to the deeper copy.
+	 */
+	if err := os.Remove(b1); err != nil {
+		t.Fatal(err)
+	}
+	if got := symlink_test_one(t); got != b2 {
+		t.Fatalf("copy gone: link to %q, want %q", got, b2)
+	}
+
+	/*
+	 * No copies are left: the stale link is removed.
+	 */
+	if err := os.Remove(b2); err != nil {
+		t.Fatal(err)
+	}
+	if got := symlink_test_one(t); got != "missing" {
+		t.Fatalf("no copies: link to %q, want it removed", got)
+	}
+
+	/*
+	 * Links made by the user, and local copies, are left alone.
+	 */
+	if err := ioutil.WriteFile(b2, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("elsewhere", "x"); err != nil {
+		t.Fatal(err)
+	}
+	if got := symlink_test_one(t); got != "elsewhere" {
+		t.Fatalf("user link: link to %q, want %q", got, "elsewhere")
+	}
+	if err := os.Remove("x"); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile("x", nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := symlink_test_one(t); got != "" {
+		t.Fatalf("local copy: link to %q, want a plain file", got)
+	}
+}
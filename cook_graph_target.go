@@ -0,0 +1,160 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+/*
+ * NAME
+ *      graph_recipe_echo - show what is being done
+ *
+ * SYNOPSIS
+ *      void graph_recipe_echo(char *text);
+ *
+ * DESCRIPTION
+ *      The graph_recipe_echo function is used to print the equivalent
+ *      command for something cook does to targets on its own behalf,
+ *      in the same way as the commands of recipe bodies are echoed,
+ *      unless the silent option is in force.
+ */
+
+func graph_recipe_echo(text string) {
+	if option_test(OPTION_SILENT) {
+		return
+	}
+	star_eoln()
+	fmt.Println(text)
+	_ = fflush_slowly(os.Stdout)
+}
+
+/*
+ * NAME
+ *      graph_recipe_prepare_targets - get ready to cook targets
+ *
+ * SYNOPSIS
+ *      int graph_recipe_prepare_targets(graph_recipe_ty *);
+ *
+ * DESCRIPTION
+ *      The graph_recipe_prepare_targets function is used, before the
+ *      out_of_date body of a recipe instance runs, to act on the
+ *      recipe's flags: with the mkdir flag, the directories of the
+ *      targets are created; with the unlink flag, the targets are
+ *      removed.  The recipe's flags are expected to be in force.
+ *
+ * RETURNS
+ *      int; 0 on success, -1 on error (already reported).
+ */
+
+func graph_recipe_prepare_targets(grp *graph_recipe_ty) int {
+	if option_test(OPTION_MKDIR) {
+		for _, item := range grp.output.item {
+			name := item.file.filename
+			dir := str_from_string(filepath.Dir(name.str))
+			exists := 1
+			if dir.str != "." {
+				exists = stat_cache_exists(dir)
+			}
+			str_free(dir)
+			if exists < 0 {
+				return -1
+			}
+			if exists > 0 {
+				continue
+			}
+			graph_recipe_echo(fmt.Sprintf("mkdir -p %s", filepath.Dir(name.str)))
+			if !option_test(OPTION_ACTION) {
+				continue
+			}
+			if os_mkdir_parent(name) < 0 {
+				return -1
+			}
+
+			/*
+			 * Forget the directories just created, and no more.
+			 */
+			for d := filepath.Dir(name.str); d != "." && d != "/"; d = filepath.Dir(d) {
+				ds := str_from_string(d)
+				stat_cache_clear(ds)
+				str_free(ds)
+			}
+		}
+	}
+	if option_test(OPTION_UNLINK) {
+		for _, item := range grp.output.item {
+			name := item.file.filename
+			exists := os_exists_symlink(name)
+			if exists < 0 {
+				return -1
+			}
+			if exists == 0 {
+				continue
+			}
+			graph_recipe_echo(fmt.Sprintf("rm %s", name.str))
+			if !option_test(OPTION_ACTION) {
+				continue
+			}
+			stat_cache_clear(name)
+			if os_unlink(name) < 0 {
+				return -1
+			}
+		}
+	}
+	return 0
+}
+
+/*
+ * NAME
+ *      graph_recipe_failed_targets - remove targets after failure
+ *
+ * SYNOPSIS
+ *      void graph_recipe_failed_targets(graph_recipe_ty *);
+ *
+ * DESCRIPTION
+ *      The graph_recipe_failed_targets function is used, after the
+ *      body of a recipe instance has failed or been interrupted, to
+ *      remove its targets, so that a half written target does not look
+ *      up to date next time.  Targets of recipes with the precious
+ *      flag are left alone.  The recipe's flags are expected to be in
+ *      force.
+ */
+
+func graph_recipe_failed_targets(grp *graph_recipe_ty) {
+	if option_test(OPTION_PRECIOUS) || !option_test(OPTION_ACTION) {
+		return
+	}
+	for _, item := range grp.output.item {
+		name := item.file.filename
+		if os_exists_symlink(name) <= 0 {
+			continue
+		}
+		stat_cache_clear(name)
+		if os_unlink(name) < 0 {
+			continue
+		}
+		scp := sub_context_new()
+		sub_var_set_string(scp, "File_Name", name)
+		error_intl(scp, i18n("$filename: deleted because of errors"))
+		sub_context_delete(scp)
+	}
+}
@@ -0,0 +1,168 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestGraphRecipeFlags(t *testing.T) {
+	tests := []struct {
+		name   string
+		book   string
+		target string   /* default "x" */
+		files  []string /* made older than the ingredient "in" */
+		status int
+		exists string /* files which must exist afterwards */
+		absent string /* files which must not */
+		echo   string /* must be echoed; "-" for nothing at all */
+	}{
+		{
+			name:   "failed target is deleted",
+			book:   "x: in { touch [target]; false; }\n",
+			status: 1,
+			absent: "x",
+		},
+		{
+			name:   "precious target is kept",
+			book:   "x: in set precious { touch [target]; false; }\n",
+			status: 1,
+			exists: "x",
+		},
+		{
+			name:   "global precious",
+			book:   "set precious;\nx: in { touch [target]; false; }\n",
+			status: 1,
+			exists: "x",
+		},
+		{
+			name:   "recipe overrides global",
+			book:   "set precious;\nx: in set no-precious { touch [target]; false; }\n",
+			status: 1,
+			absent: "x",
+		},
+		{
+			name:   "unlink",
+			book:   "x: in set unlink { test ! -e [target]; touch [target]; }\n",
+			files:  []string{"x"},
+			exists: "x",
+			echo:   "rm x\n",
+		},
+		{
+			name:   "without unlink",
+			book:   "x: in { test ! -e [target]; touch [target]; }\n",
+			files:  []string{"x"},
+			status: 1,
+		},
+		{
+			name:   "mkdir",
+			book:   "d/e/x: in set mkdir { touch [target]; }\n",
+			target: "d/e/x",
+			exists: "d/e/x",
+			echo:   "mkdir -p d/e\n",
+		},
+		{
+			name:   "without mkdir",
+			book:   "d/e/x: in { touch [target]; }\n",
+			target: "d/e/x",
+			status: 1,
+			absent: "d",
+		},
+		{
+			name:   "errok",
+			book:   "x: in set errok { false; touch [target]; }\n",
+			exists: "x",
+		},
+		{
+			name:   "without errok",
+			book:   "x: in { false; touch [target]; }\n",
+			status: 1,
+			absent: "x",
+		},
+		{
+			name:   "not silent",
+			book:   "x: in { touch [target]; }\n",
+			exists: "x",
+			echo:   "touch x\n",
+		},
+		{
+			name:   "silent",
+			book:   "x: in set silent { touch [target]; }\n",
+			exists: "x",
+			echo:   "-",
+		},
+		{
+			name:   "up to date",
+			book:   "x: { touch ran; }\n",
+			files:  []string{"x"},
+			absent: "ran",
+		},
+		{
+			name:   "force",
+			book:   "x: set force { touch ran; }\n",
+			files:  []string{"x"},
+			exists: "ran",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cookbook_test_read(t, tt.book, append([]string{"in"}, tt.files...)...)
+			file_test_age(t, "in", 100)
+			for _, name := range tt.files {
+				file_test_age(t, name, 200)
+			}
+			target := tt.target
+			if target == "" {
+				target = "x"
+			}
+			var status int
+			var msg string
+			echo := capture_stdout(t, func() {
+				status, msg = cook_test_run(t, target)
+			})
+			if status != tt.status {
+				t.Errorf("cook = %d, want %d\n%s", status, tt.status, msg)
+			}
+			for _, name := range strings.Fields(tt.exists) {
+				if _, err := os.Lstat(name); err != nil {
+					t.Errorf("%s does not exist", name)
+				}
+			}
+			for _, name := range strings.Fields(tt.absent) {
+				if _, err := os.Lstat(name); err == nil {
+					t.Errorf("%s exists", name)
+				}
+			}
+			switch tt.echo {
+			case "":
+			case "-":
+				if echo != "" {
+					t.Errorf("echoed %q, want nothing", echo)
+				}
+			default:
+				if !strings.Contains(echo, tt.echo) {
+					t.Errorf("echoed %q, want %q", echo, tt.echo)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,351 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import "fmt"
+
+/*
+ * NAME
+ *      graph_walk_recipe_ready - ingredients resolved
+ *
+ * SYNOPSIS
+ *      void graph_walk_recipe_ready(graph_walk_ty *, graph_recipe_ty *);
+ *
+ * DESCRIPTION
+ *      The graph_walk_recipe_ready function is called once all of the
+ *      ingredients of a recipe instance have been resolved.  If any of
+ *      them could not be cooked, the recipe instance fails without
+ *      being run; otherwise it is queued to be run.
+ */
+
+func graph_walk_recipe_ready(wp *graph_walk_ty, grp *graph_recipe_ty) {
+	for _, item := range grp.input.item {
+		if item.file.done >= 0 {
+			continue
+		}
+		if len(grp.output.item) > 0 && wp.status != graph_walk_status_interrupted {
+			scp := sub_context_new()
+			sub_var_set_string(scp, "Name", grp.output.item[0].file.filename)
+			sub_var_set_string(scp, "Ingredient", item.file.filename)
+			error_with_position(&grp.rp.pos, scp, i18n("\"$name\" not derived due to errors cooking \"$ingredient\""))
+			sub_context_delete(scp)
+		}
+		graph_walk_recipe_finish(wp, grp, graph_walk_status_error)
+		return
+	}
+	wp.ready = append(wp.ready, grp)
+}
+
+/*
+ * NAME
+ *      graph_walk_file_done - file resolved
+ *
+ * SYNOPSIS
+ *      void graph_walk_file_done(graph_walk_ty *, graph_file_ty *);
+ *
+ * DESCRIPTION
+ *      The graph_walk_file_done function is called once all of the
+ *      recipe instances which make a file have finished (immediately,
+ *      for leaf files).  The recipe instances which use the file are
+ *      told, and those with all of their ingredients resolved become
+ *      ready.
+ */
+
+func graph_walk_file_done(wp *graph_walk_ty, gfp *graph_file_ty) {
+	if gfp.done == 0 {
+		gfp.done = 1
+	}
+	for _, grp := range gfp.output.recipe {
+		grp.input_satisfied++
+		if grp.input_satisfied == size_t(len(grp.input.item)) {
+			graph_walk_recipe_ready(wp, grp)
+		}
+	}
+}
+
+/*
+ * NAME
+ *      graph_walk_recipe_finish - recipe instance finished
+ *
+ * SYNOPSIS
+ *      void graph_walk_recipe_finish(graph_walk_ty *, graph_recipe_ty *,
+ *              graph_walk_status_ty);
+ *
+ * DESCRIPTION
+ *      The graph_walk_recipe_finish function is called when a recipe
+ *      instance has finished, successfully or not.  Its targets are
+ *      updated, and may in turn be resolved.  After a failure no more
+ *      recipes are started, unless the -Continue option is in force,
+ *      in which case only the recipes which depend on the failed
+ *      targets are abandoned.
+ */
+
+func graph_walk_recipe_finish(wp *graph_walk_ty, grp *graph_recipe_ty, status graph_walk_status_ty) {
+	trace(fmt.Sprintf("graph_walk_recipe_finish(grp = %d, status = %d)\n{\n", grp.id, status))
+	failed := false
+	switch status {
+	case graph_walk_status_error, graph_walk_status_interrupted:
+		failed = true
+		if wp.status < status {
+			wp.status = status
+		}
+		if status == graph_walk_status_interrupted || !option_test(OPTION_PERSEVERE) {
+			wp.stop = true
+		}
+
+	case graph_walk_status_done:
+		if wp.status < status {
+			wp.status = status
+		}
+	}
+	for _, item := range grp.output.item {
+		gfp := item.file
+		if failed {
+			gfp.done = -1
+		} else if status == graph_walk_status_uptodate {
+			gfp.input_uptodate++
+		}
+		gfp.input_satisfied++
+		gfp.pending--
+		if gfp.pending == 0 {
+			graph_walk_file_done(wp, gfp)
+		}
+	}
+	trace("}\n")
+}
+
+/*
+ * NAME
+ *      graph_walk_conflict - may a recipe instance start
+ *
+ * SYNOPSIS
+ *      int graph_walk_conflict(graph_walk_ty *, graph_recipe_ty *);
+ *
+ * DESCRIPTION
+ *      The graph_walk_conflict function is used to determine whether a
+ *      ready recipe instance must wait for a running one.  This is the
+ *      case when they have a target in common, as double colon recipes
+ *      do; they are run one at a time, in the order they were defined.
+ *      It is also the case when their single thread clauses name a
+ *      common resource.
+ */
+
+func graph_walk_conflict(wp *graph_walk_ty, grp *graph_recipe_ty) bool {
+	for _, other := range wp.running {
+		if grp.single_thread != nil && other.single_thread != nil {
+			for _, s := range grp.single_thread.strings {
+				if string_list_member(other.single_thread, s) {
+					return true
+				}
+			}
+		}
+		for _, item := range grp.output.item {
+			for _, item2 := range other.output.item {
+				if item.file == item2.file {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+/*
+ * NAME
+ *      graph_walk_next - choose a recipe instance
+ *
+ * SYNOPSIS
+ *      graph_recipe_ty *graph_walk_next(graph_walk_ty *);
+ *
+ * DESCRIPTION
+ *      The graph_walk_next function is used to take the first ready
+ *      recipe instance which may be started now from the ready queue.
+ *
+ * RETURNS
+ *      graph_recipe_ty *; NULL if there are none.
+ */
+
+func graph_walk_next(wp *graph_walk_ty) *graph_recipe_ty {
+	for j, grp := range wp.ready {
+		if graph_walk_conflict(wp, grp) {
+			continue
+		}
+		wp.ready = append(wp.ready[:j], wp.ready[j+1:]...)
+		return grp
+	}
+	return nil
+}
+
+/*
+ * NAME
+ *      graph_walk_host - choose a host
+ *
+ * SYNOPSIS
+ *      string_ty *graph_walk_host(graph_walk_ty *, graph_recipe_ty *);
+ *
+ * DESCRIPTION
+ *      The graph_walk_host function is used to choose the host a recipe
+ *      instance is to run on.  The candidates are the hosts named by
+ *      its host binding clause, if it has one, or else the hosts named
+ *      by the parallel_hosts variable.  They are taken in turn, round
+ *      robin, skipping those already busy running another recipe if
+ *      possible.
+ *
+ * RETURNS
+ *      string_ty *; NULL to run locally.
+ */
+
+func graph_walk_host(wp *graph_walk_ty, grp *graph_recipe_ty) *string_ty {
+	candidates := wp.hosts
+	if grp.host_binding != nil {
+		candidates = grp.host_binding
+	}
+	if candidates == nil || len(candidates.strings) == 0 {
+		return nil
+	}
+	n := len(candidates.strings)
+	for j := 0; j < n; j++ {
+		host := candidates.strings[(wp.host_rr+j)%n]
+		busy := false
+		for _, other := range wp.running {
+			if other.ocp.host_binding != nil && str_equal(other.ocp.host_binding, host) {
+				busy = true
+				break
+			}
+		}
+		if !busy {
+			wp.host_rr += j + 1
+			return host
+		}
+	}
+	host := candidates.strings[wp.host_rr%n]
+	wp.host_rr++
+	return host
+}
+
+/*
+ * NAME
+ *      graph_walk - cook the graph
+ *
+ * SYNOPSIS
+ *      graph_walk_status_ty graph_walk(graph_ty *);
+ *
+ * DESCRIPTION
+ *      The graph_walk function is used to run the recipe instances of
+ *      the dependency graph, each once all of its ingredients have been
+ *      cooked.  Up to -Parallel recipe bodies run at once (by default,
+ *      one per host named by the parallel_hosts variable, if set);
+ *      while their commands execute, the walker waits for any child
+ *      process to exit and resumes the recipe instance it belongs to.
+ *
+ * RETURNS
+ *      graph_walk_status_ty; graph_walk_status_uptodate if there was
+ *      nothing to do, graph_walk_status_done if all went well, or the
+ *      worst failure.
+ */
+
+func graph_walk(gp *graph_ty) graph_walk_status_ty {
+	trace(fmt.Sprintf("graph_walk(gp = %p)\n{\n", gp))
+	wp := &graph_walk_ty{
+		gp:    gp,
+		jobs:  option.o_parallel,
+		hosts: id_variable_query("parallel_hosts"),
+	}
+	if wp.jobs < 1 && wp.hosts != nil {
+		wp.jobs = long(len(wp.hosts.strings))
+	}
+	if wp.jobs < 1 {
+		wp.jobs = 1
+	}
+
+	/*
+	 * Reset the bookkeeping, and release everything which depends
+	 * only on leaf files.
+	 */
+	files := graph_file_list_sorted(gp)
+	for _, gfp := range files {
+		gfp.pending = long(len(gfp.input.recipe))
+		gfp.done = 0
+		gfp.input_satisfied = 0
+		gfp.input_uptodate = 0
+	}
+	for _, grp := range gp.already_recipe.recipe {
+		grp.input_satisfied = 0
+		if len(grp.input.item) == 0 {
+			graph_walk_recipe_ready(wp, grp)
+		}
+	}
+	for _, gfp := range files {
+		if gfp.pending == 0 {
+			graph_walk_file_done(wp, gfp)
+		}
+	}
+
+	for {
+		if !wp.stop && desist_requested() {
+			wp.stop = true
+			wp.status = graph_walk_status_interrupted
+		}
+
+		/*
+		 * Start as many recipe instances as we are allowed.
+		 */
+		for !wp.stop && long(len(wp.running)) < wp.jobs {
+			grp := graph_walk_next(wp)
+			if grp == nil {
+				break
+			}
+			status := graph_recipe_run(grp, gp, graph_walk_host(wp, grp))
+			if status == graph_walk_status_wait {
+				wp.running = append(wp.running, grp)
+				continue
+			}
+			graph_walk_recipe_finish(wp, grp, status)
+		}
+		if len(wp.running) == 0 {
+			break
+		}
+
+		/*
+		 * Wait for a child process to exit, and resume the recipe
+		 * instance it belongs to.
+		 */
+		pid, exit_status := os_wait()
+		j := 0
+		for j < len(wp.running) && wp.running[j].ocp.pid != pid {
+			j++
+		}
+		assert(j < len(wp.running), "j < len(wp.running)")
+		grp := wp.running[j]
+		grp.ocp.exit_status = exit_status
+		status := graph_recipe_run_resume(grp, gp)
+		if status == graph_walk_status_wait {
+			continue
+		}
+		wp.running = append(wp.running[:j], wp.running[j+1:]...)
+		graph_walk_recipe_finish(wp, grp, status)
+	}
+	if wp.status == graph_walk_status_interrupted {
+		error_intl(nil, i18n("interrupted"))
+	}
+	trace(fmt.Sprintf("return %d;\n", wp.status))
+	trace("}\n")
+	return wp.status
+}
@@ -0,0 +1,47 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+type graph_walk_status_ty int
+
+// enum graph_walk_status_ty
+const (
+	graph_walk_status_uptodate graph_walk_status_ty = iota /* nothing needed doing */
+	graph_walk_status_done                                 /* the body was run successfully */
+	graph_walk_status_wait                                 /* waiting for a child process */
+	graph_walk_status_error
+	graph_walk_status_interrupted
+)
+
+/*
+ * The state of a walk of the dependency graph.  Recipe instances are
+ * ready once all of their ingredients have been resolved; up to jobs
+ * of them run at once.
+ */
+type graph_walk_ty struct {
+	gp      *graph_ty
+	jobs    long
+	ready   []*graph_recipe_ty
+	running []*graph_recipe_ty
+	status  graph_walk_status_ty
+	stop    bool            /* start no more recipes */
+	hosts   *string_list_ty /* the parallel_hosts variable */
+	host_rr int             /* next host, round robin */
+}
@@ -0,0 +1,269 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func test_string_list(words ...string) *string_list_ty {
+	if len(words) == 0 {
+		return nil
+	}
+	wlp := &string_list_ty{}
+	for _, w := range words {
+		string_list_append(wlp, str_from_string(w))
+	}
+	return wlp
+}
+
+func TestGraphWalkHost(t *testing.T) {
+	tests := []struct {
+		name    string
+		hosts   []string /* the parallel_hosts variable */
+		binding []string /* the host-binding clause */
+		busy    []string /* hosts running another recipe */
+		rr      int
+		want    string
+		want_rr int
+	}{
+		{"no hosts runs locally", nil, nil, nil, 0, "", 0},
+		{"first host", []string{"h1", "h2"}, nil, nil, 0, "h1", 1},
+		{"second host", []string{"h1", "h2"}, nil, nil, 1, "h2", 2},
+		{"round robin wraps", []string{"h1", "h2"}, nil, nil, 2, "h1", 3},
+		{"skip busy host", []string{"h1", "h2"}, nil, []string{"h1"}, 0, "h2", 2},
+		{"skip busy hosts and wrap", []string{"h1", "h2", "h3"}, nil, []string{"h2", "h3"}, 1, "h1", 4},
+		{"all busy shares", []string{"h1", "h2"}, nil, []string{"h1", "h2"}, 1, "h2", 2},
+		{"binding without hosts", nil, []string{"b1"}, nil, 0, "b1", 1},
+		{"binding overrides hosts", []string{"h1", "h2"}, []string{"b1"}, nil, 1, "b1", 2},
+		{"binding round robin", []string{"h1"}, []string{"b1", "b2"}, nil, 3, "b2", 4},
+		{"binding skips busy host", nil, []string{"b1", "b2"}, []string{"b1"}, 0, "b2", 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wp := &graph_walk_ty{hosts: test_string_list(tt.hosts...), host_rr: tt.rr}
+			for _, host := range tt.busy {
+				ocp := &opcode_context_ty{host_binding: str_from_string(host)}
+				wp.running = append(wp.running, &graph_recipe_ty{ocp: ocp})
+			}
+			grp := &graph_recipe_ty{host_binding: test_string_list(tt.binding...)}
+			got := graph_walk_host(wp, grp)
+			switch {
+			case tt.want == "" && got != nil:
+				t.Errorf("graph_walk_host = %q, want local", got.str)
+			case tt.want != "" && (got == nil || got.str != tt.want):
+				t.Errorf("graph_walk_host = %v, want %q", got, tt.want)
+			}
+			if wp.host_rr != tt.want_rr {
+				t.Errorf("host_rr = %d, want %d", wp.host_rr, tt.want_rr)
+			}
+		})
+	}
+}
+
+/*
+ * Run the recipes with the local runner, one at a time, and check
+ * that each ran in the directory of the host it was given.
+ */
+
+func TestGraphWalkHostLocalRunner(t *testing.T) {
+	cookbook_test_read(t,
+		"parallel_hosts = h1 h2;\n"+
+			"parallel_runner = local;\n"+
+			"all: t1 t2 t3 b1 b2 t4;\n"+
+			"t1: { pwd > ../../[target]; }\n"+
+			"t2: { pwd > ../../[target]; }\n"+
+			"t3: { pwd > ../../[target]; }\n"+
+			"b1: host-binding h3 { pwd > ../../[target]; }\n"+
+			"b2: host-binding h4 h5 { pwd > ../../[target]; }\n"+
+			"t4: { pwd > ../../[target]; }\n")
+	save := option.o_parallel
+	option.o_parallel = 1
+	defer func() { option.o_parallel = save }()
+
+	var status int
+	capture_stderr(t, func() {
+		status = cook(test_string_list("all"))
+	})
+	if status != 0 {
+		t.Fatalf("cook = %d, want 0", status)
+	}
+	for _, tt := range []struct {
+		target string
+		host   string
+	}{
+		{"t1", "h1"},
+		{"t2", "h2"},
+		{"t3", "h1"},
+		{"b1", "h3"},
+		{"b2", "h4"},
+		{"t4", "h2"},
+	} {
+		data, err := ioutil.ReadFile(tt.target)
+		if err != nil {
+			t.Errorf("%s: %v", tt.target, err)
+			continue
+		}
+		got := strings.TrimSpace(string(data))
+		want := filepath.Join(".cook.hosts", tt.host)
+		if !strings.HasSuffix(got, string(filepath.Separator)+want) {
+			t.Errorf("%s ran in %q, want %q", tt.target, got, want)
+		}
+	}
+}
+
+/*
+ * NAME
+ *      parallel_test_set - set the -Parallel option for a test
+ */
+
+func parallel_test_set(t *testing.T, n long) {
+	save := option.o_parallel
+	option.o_parallel = n
+	t.Cleanup(func() { option.o_parallel = save })
+}
+
+/*
+ * Recipes run at the same time finish in whatever order their
+ * commands do, and each is resumed when its own child exits.  A recipe
+ * which needs them waits for all of them.
+ */
+
+func TestGraphWalkParallel(t *testing.T) {
+	tests := []struct {
+		jobs long
+		want string
+	}{
+		{1, "a1 a2 b c"},
+		{2, "b a1 a2 c"},
+		{3, "b a1 a2 c"},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("parallel=%d", tt.jobs), func(t *testing.T) {
+			cookbook_test_read(t,
+				"c: a b { echo c >> log; touch c; }\n"+
+					"a: { sleep 0.2; echo a1 >> log; sleep 0.1; echo a2 >> log; touch a; }\n"+
+					"b: { sleep 0.1; echo b >> log; touch b; }\n")
+			parallel_test_set(t, tt.jobs)
+			if status, msg := cook_test_run(t, "c"); status != 0 {
+				t.Fatalf("cook = %d\n%s", status, msg)
+			}
+			data, err := ioutil.ReadFile("log")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := strings.Join(strings.Fields(string(data)), " "); got != tt.want {
+				t.Errorf("order %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGraphWalkContinue(t *testing.T) {
+	tests := []struct {
+		persevere bool
+		jobs      long
+		want      []string /* the files which exist afterwards */
+	}{
+		{false, 1, nil},
+		{true, 1, []string{"good", "good2"}},
+		{true, 2, []string{"good", "good2"}},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("continue=%v,parallel=%d", tt.persevere, tt.jobs), func(t *testing.T) {
+			cookbook_test_read(t,
+				"all: bad good good2;\n"+
+					"bad: { false; }\n"+
+					"good: { sleep 0.1; touch good; }\n"+
+					"good2: good { touch good2; }\n"+
+					"after: bad { touch after; }\n"+
+					"all: after;\n")
+			parallel_test_set(t, tt.jobs)
+			if tt.persevere {
+				option_set(OPTION_PERSEVERE, OPTION_LEVEL_COMMAND_LINE, true)
+				defer option_undo(OPTION_PERSEVERE, OPTION_LEVEL_COMMAND_LINE)
+			}
+			status, msg := cook_test_run(t, "all")
+			if status == 0 {
+				t.Errorf("cook = 0, want failure")
+			}
+			if !strings.Contains(msg, "bad") {
+				t.Errorf("failure of bad not reported: %q", msg)
+			}
+			for _, name := range []string{"good", "good2", "after"} {
+				_, err := os.Stat(name)
+				want := false
+				for _, w := range tt.want {
+					if w == name {
+						want = true
+					}
+				}
+				if (err == nil) != want {
+					t.Errorf("%s exists = %v, want %v", name, err == nil, want)
+				}
+			}
+		})
+	}
+}
+
+/*
+ * Recipes whose single-thread clauses name a common word must not run
+ * at the same time, even when -Parallel would allow it.
+ */
+
+func TestGraphWalkSingleThread(t *testing.T) {
+	tests := []struct {
+		name    string
+		a, b    string /* the single-thread words */
+		overlap bool
+	}{
+		{"shared word", "db", "db", false},
+		{"one of several", "db x", "y db", false},
+		{"implicit recipe field", "%.lock", "%.lock", false},
+		{"different words", "db1", "db2", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := "{ echo start [target] >> log; sleep 0.2; echo end [target] >> log; touch [target]; }\n"
+			cookbook_test_read(t,
+				"all: x.a x.b;\n"+
+					"%.a: single-thread "+tt.a+" "+body+
+					"%.b: single-thread "+tt.b+" "+body)
+			parallel_test_set(t, 2)
+			if status, msg := cook_test_run(t, "all"); status != 0 {
+				t.Fatalf("cook = %d\n%s", status, msg)
+			}
+			data, err := ioutil.ReadFile("log")
+			if err != nil {
+				t.Fatal(err)
+			}
+			lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+			overlap := len(lines) == 4 && strings.HasPrefix(lines[1], "start")
+			if overlap != tt.overlap {
+				t.Errorf("overlap = %v, want %v\n%s", overlap, tt.overlap, data)
+			}
+		})
+	}
+}
@@ -45,11 +45,11 @@ var id_search_list *string_ty
 func id_initialize() {
 	trace("init\n")
 
-	id_need = str_from_c("need")
-	id_younger = str_from_c("younger")
-	id_target = str_from_c("target")
-	id_targets = str_from_c("targets")
-	id_search_list = str_from_c("search_list")
+	id_need = str_from_string("need")
+	id_younger = str_from_string("younger")
+	id_target = str_from_string("target")
+	id_targets = str_from_string("targets")
+	id_search_list = str_from_string("search_list")
 
 	id_reset()
 }
@@ -62,13 +62,16 @@ func id_reset() {
 	 */
 	var wl string_list_ty
 	string_list_constructor(&wl)
-	s := str_from_c(version_stamp())
+	s := str_from_string(version_stamp())
 	string_list_append(&wl, s)
 	s = str_free(s)
-	s = str_from_c("version")
+	s = str_from_string("version")
 	symtab_assign(id_global_stp(), s, id_variable_new(&wl))
 	s = str_free(s)
 	string_list_destructor(&wl)
 
-	panic("!")
+	/*
+	 * set the builtin functions
+	 */
+	builtin_initialize()
 }
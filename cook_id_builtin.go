@@ -0,0 +1,143 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import "fmt"
+
+/*
+ * A builtin function id.  The builtin table is static, so there is
+ * nothing to release.
+ */
+
+type id_builtin_ty struct {
+	bp *builtin_ty
+}
+
+func id_builtin_destructor(idp *id_ty) {
+	_, ok := idp.this.(*id_builtin_ty)
+	assert(ok, "idp.this.(*id_builtin_ty)")
+}
+
+/*
+ * NAME
+ *      id_builtin_interpret
+ *
+ * SYNOPSIS
+ *      int id_builtin_interpret(id_ty *, opcode_context_ty *,
+ *              expr_position_ty *);
+ *
+ * DESCRIPTION
+ *      The id_builtin_interpret function is used to call a builtin
+ *      function.  The arguments are popped from the value stack, and
+ *      the results appended to the (new) top-most string list.
+ *
+ * RETURNS
+ *      int; 0 on success, -1 on error.
+ */
+
+func id_builtin_interpret(idp *id_ty, ocp *opcode_context_ty, pp *expr_position_ty) int {
+	this, ok := idp.this.(*id_builtin_ty)
+	assert(ok, "idp.this.(*id_builtin_ty)")
+	return id_builtin_call(this.bp.interpret, ocp, pp)
+}
+
+/*
+ * NAME
+ *      id_builtin_script
+ *
+ * SYNOPSIS
+ *      int id_builtin_script(id_ty *, opcode_context_ty *,
+ *              expr_position_ty *);
+ *
+ * DESCRIPTION
+ *      The id_builtin_script function is used to call a builtin
+ *      function, for the purposes of writing a shell script.  Builtins
+ *      without a script function are interpreted normally.
+ *
+ * RETURNS
+ *      int; 0 on success, -1 on error.
+ */
+
+func id_builtin_script(idp *id_ty, ocp *opcode_context_ty, pp *expr_position_ty) int {
+	this, ok := idp.this.(*id_builtin_ty)
+	assert(ok, "idp.this.(*id_builtin_ty)")
+	fn := this.bp.script
+	if fn == nil {
+		fn = this.bp.interpret
+	}
+	return id_builtin_call(fn, ocp, pp)
+}
+
+func id_builtin_call(
+	fn func(*string_list_ty, *string_list_ty, *expr_position_ty, *opcode_context_ty) int,
+	ocp *opcode_context_ty,
+	pp *expr_position_ty,
+) int {
+	trace(fmt.Sprintf("id_builtin_call(ocp = %p)\n{\n", ocp))
+	args := opcode_context_string_list_pop(ocp)
+	var result string_list_ty
+	string_list_constructor(&result)
+	status := fn(&result, args, pp, ocp)
+	if status >= 0 {
+		opcode_context_string_push_list(ocp, &result)
+	}
+	string_list_destructor(&result)
+	string_list_delete(args)
+	trace(fmt.Sprintf("return %d;\n", status))
+	trace("}\n")
+	return status
+}
+
+/*
+ * NAME
+ *      id_builtin_method
+ *
+ * DESCRIPTION
+ *      The id_builtin_method variable describes this ID class.
+ */
+
+var id_builtin_method = id_method_ty{
+	name:       "builtin",
+	destructor: id_builtin_destructor,
+	interprets: id_builtin_interpret,
+	script:     id_builtin_script,
+}
+
+/*
+ * NAME
+ *      id_builtin_new
+ *
+ * SYNOPSIS
+ *      id_ty *id_builtin_new(builtin_ty *);
+ *
+ * DESCRIPTION
+ *      The id_builtin_new function is used to create a new instance of
+ *      a builtin function ID.
+ *
+ * RETURNS
+ *      id_ty *; a pointer to a ID instance is dynamic memory.
+ *
+ * CAVEAT
+ *      Use id_instance_delete when you are done with it.
+ */
+
+func id_builtin_new(bp *builtin_ty) *id_ty {
+	return id_instance_new(&id_builtin_method, &id_builtin_ty{bp: bp})
+}
@@ -0,0 +1,129 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import "fmt"
+
+/*
+ * A user defined function id.  The body is the compiled function
+ * definition; the arguments are passed as local variables.
+ */
+
+type id_function_ty struct {
+	body *opcode_list_ty
+}
+
+func id_function_destructor(idp *id_ty) {
+	this, ok := idp.this.(*id_function_ty)
+	assert(ok, "idp.this.(*id_function_ty)")
+	opcode_list_delete(this.body)
+}
+
+/*
+ * NAME
+ *      id_function_interpret
+ *
+ * SYNOPSIS
+ *      int id_function_interpret(id_ty *, opcode_context_ty *,
+ *              expr_position_ty *);
+ *
+ * DESCRIPTION
+ *      The id_function_interpret function is used to call a user
+ *      defined function.  The arguments are popped from the value
+ *      stack, and a new frame is pushed on the call stack to execute
+ *      the function body.  The frame's local variables are the
+ *      arguments: "@1" through "@n" are the individual words, and "arg"
+ *      is all of them.  When the body returns, its value is appended to
+ *      the (new) top-most string list.
+ *
+ * RETURNS
+ *      int; 0 on success, -1 on error.
+ */
+
+func id_function_interpret(idp *id_ty, ocp *opcode_context_ty, pp *expr_position_ty) int {
+	trace(fmt.Sprintf("id_function::interpret(idp = %p)\n{\n", idp))
+	this, ok := idp.this.(*id_function_ty)
+	assert(ok, "idp.this.(*id_function_ty)")
+	args := opcode_context_string_list_pop(ocp)
+	assert(len(args.strings) >= 1, "len(args.strings) >= 1")
+
+	stp := symtab_alloc(len(args.strings) + 1)
+	stp.reap = id_global_reap
+	for j := 1; j < len(args.strings); j++ {
+		name := str_format("@%d", j)
+		wl := string_list_ty{}
+		string_list_append(&wl, args.strings[j])
+		symtab_assign(stp, name, id_variable_new(&wl))
+		string_list_destructor(&wl)
+		str_free(name)
+	}
+	string_list_remove_nth(args, 0)
+	name := str_from_string("arg")
+	symtab_assign(stp, name, id_variable_new(args))
+	str_free(name)
+	string_list_delete(args)
+
+	opcode_context_call(ocp, this.body, stp)
+	trace("}\n")
+	return 0
+}
+
+/*
+ * NAME
+ *      id_function_method
+ *
+ * DESCRIPTION
+ *      The id_function_method variable describes this ID class.
+ */
+
+var id_function_method = id_method_ty{
+	name:       "function",
+	size:       0, //todo: sizeof(id_function_ty),
+	destructor: id_function_destructor,
+	interprets: id_function_interpret,
+	script:     id_function_interpret, /* script */
+}
+
+/*
+ * NAME
+ *      id_function_new
+ *
+ * SYNOPSIS
+ *      id_ty *id_function_new(opcode_list_ty *body);
+ *
+ * DESCRIPTION
+ *      The id_function_new function is used to create a new instance of
+ *      a user defined function ID.  The body is copied.
+ *
+ * RETURNS
+ *      id_ty *; a pointer to a ID instance is dynamic memory.
+ *
+ * CAVEAT
+ *      Use id_instance_delete when you are done with it.
+ */
+
+func id_function_new(body *opcode_list_ty) *id_ty {
+	trace("id_function::new()\n{\n")
+	this := &id_function_ty{body: opcode_list_copy(body)}
+	idp := id_instance_new(&id_function_method, this)
+	trace(fmt.Sprintf("return %p;\n", idp))
+	trace("}\n")
+	return idp
+}
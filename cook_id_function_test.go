@@ -0,0 +1,91 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import "testing"
+
+func TestIdFunction(t *testing.T) {
+	tests := []struct {
+		name string
+		book string
+		want string
+	}{
+		{
+			name: "arguments",
+			book: "function f = { return [@2] [@1] / [arg]; }\n" +
+				"r = [f a b c];\n",
+			want: "b a / a b c",
+		},
+		{
+			name: "no arguments",
+			book: "function f = { return [count [arg]]; }\n" +
+				"r = [f];\n",
+			want: "0",
+		},
+		{
+			name: "nested calls have their own arguments",
+			book: "function g = { return [@1][@1]; }\n" +
+				"function f = { return [g x] [@1]; }\n" +
+				"r = [f a];\n",
+			want: "xx a",
+		},
+		{
+			name: "recursion",
+			book: "function rev = {\n" +
+				"    if [not [arg]] then return;\n" +
+				"    return [rev [tail [arg]]] [head [arg]];\n" +
+				"}\n" +
+				"r = [rev a b c d];\n",
+			want: "d c b a",
+		},
+		{
+			name: "arguments are local",
+			book: "function f = { arg = changed; @1 = changed; return [arg] [@1]; }\n" +
+				"arg = global;\n" +
+				"r = [f a] [arg] [if [defined @1] then leaked else local];\n",
+			want: "changed changed global local",
+		},
+		{
+			name: "other assignments are global",
+			book: "function f = { v = [@1]; return; }\n" +
+				"v = old;\n" +
+				"r = [f new] [v];\n",
+			want: "new",
+		},
+		{
+			name: "function without return",
+			book: "function f = { v = x; }\n" +
+				"r = a [f] b;\n",
+			want: "a b",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cookbook_test_read(t, tt.book)
+			got := id_variable_query("r")
+			if got == nil {
+				t.Fatalf("r is not set")
+			}
+			if s := wl2str(got, 0, len(got.strings)-1, " ").str; s != tt.want {
+				t.Errorf("r = %q, want %q", s, tt.want)
+			}
+		})
+	}
+}
@@ -21,4 +21,5 @@ package main
 
 type id_ty struct {
 	method *id_method_ty
+	this   interface{} /* the derived instance, see id_instance_new */
 }
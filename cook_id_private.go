@@ -27,15 +27,33 @@ func id_instance_delete(idp *id_ty) {
 	assert(idp.method.destructor != nil, "idp.method.destructor")
 	idp.method.destructor(idp)
 	idp.method = nil /* paranoia */
-	idp = nil        // mem_free(idp)
+	idp.this = nil
+	idp = nil // mem_free(idp)
 }
 
-func id_instance_new(mp *id_method_ty) *id_ty {
+/*
+ * NAME
+ *      id_instance_new
+ *
+ * SYNOPSIS
+ *      id_ty *id_instance_new(id_method_ty *, void *this);
+ *
+ * DESCRIPTION
+ *      The id_instance_new function is used to create a new ID
+ *      instance.  The C version allocated mp->size bytes and relied on
+ *      the derived structure starting with the base; here the derived
+ *      instance is remembered in the "this" field instead, and the
+ *      methods recover it with a type assertion.
+ */
+
+func id_instance_new(mp *id_method_ty, this interface{}) *id_ty {
 	trace("id_new()\n{\n")
 	assert(mp != nil, "mp != nil")
+	assert(this != nil, "this != nil")
 	trace(fmt.Sprintf("is a %q\n", mp.name))
 	idp := &id_ty{} // mem_alloc(mp.size);
 	idp.method = mp
+	idp.this = this
 	trace(fmt.Sprintf("return %p;\n", idp))
 	trace("}\n")
 	return idp
@@ -22,13 +22,12 @@ package main
 import "fmt"
 
 type id_variable_ty struct {
-	inherited id_ty
-	value     string_list_ty
+	value string_list_ty
 }
 
 /*
  * NAME
- *      destructor
+ *      id_variable_destructor
  *
  * SYNOPSIS
  *      void destructor(id_ty *);
@@ -38,17 +37,17 @@ type id_variable_ty struct {
  *      an ID instance.
  */
 
-func destructor(idp *id_ty) {
+func id_variable_destructor(idp *id_ty) {
 	trace(fmt.Sprintf("id_variable::destructor(idp = %p)\n{\n", idp))
-	this, ok := idp.(*id_variable_ty)
-	assert(ok, "idp.(*id_variable_ty)")
+	this, ok := idp.this.(*id_variable_ty)
+	assert(ok, "idp.this.(*id_variable_ty)")
 	string_list_destructor(&this.value)
 	trace("}\n")
 }
 
 /*
  * NAME
- *      evaluate
+ *      id_variable_interpret
  *
  * SYNOPSIS
  *      int evaluate(id_ty *, const string_list_ty *, string_list_ty *);
@@ -63,10 +62,10 @@ func destructor(idp *id_ty) {
  *      int; 0 on success, -1 on error.
  */
 
-func interpret(idp *id_ty, ocp *opcode_context_ty, pp *expr_position_ty) int {
+func id_variable_interpret(idp *id_ty, ocp *opcode_context_ty, pp *expr_position_ty) int {
 	trace(fmt.Sprintf("id_variable::interpret(idp = %p)\n{\n", idp))
-	this, ok := idp.(*id_variable_ty)
-	assert(ok, "idp.(*id_variable_ty)")
+	this, ok := idp.this.(*id_variable_ty)
+	assert(ok, "idp.this.(*id_variable_ty)")
 	status := 0
 	arg := opcode_context_string_list_pop(ocp)
 	assert(len(arg.strings) >= 1, "len(arg.strings) >= 1")
@@ -85,22 +84,18 @@ func interpret(idp *id_ty, ocp *opcode_context_ty, pp *expr_position_ty) int {
 
 /*
  * NAME
- *      method
+ *      id_variable_method
  *
  * DESCRIPTION
- *      The method variable describes this ID class.
- *
- * CAVEAT
- *      This symbol is not to be exported from this file (its name is
- *      not unique).
+ *      The id_variable_method variable describes this ID class.
  */
 
-var method = id_method_ty{
+var id_variable_method = id_method_ty{
 	name:       "variable",
 	size:       0, //todo: sizeof(id_variable_ty),
-	destructor: destructor,
-	interprets: interpret,
-	script:     interpret, /* script */
+	destructor: id_variable_destructor,
+	interprets: id_variable_interpret,
+	script:     id_variable_interpret, /* script */
 }
 
 /*
@@ -123,11 +118,41 @@ var method = id_method_ty{
 
 func id_variable_new(slp *string_list_ty) *id_ty {
 	trace("id_variable::new()\n{\n")
-	idp := id_instance_new(&method)
-	this, ok := idp.(*id_variable_ty)
-	assert(ok, "idp.(*id_variable_ty)")
+	this := &id_variable_ty{}
+	idp := id_instance_new(&id_variable_method, this)
 	string_list_copy_constructor(&this.value, slp)
 	trace(fmt.Sprintf("return %p;\n", idp))
 	trace("}\n")
 	return idp
 }
+
+/*
+ * NAME
+ *      id_variable_query - value of a global variable
+ *
+ * SYNOPSIS
+ *      string_list_ty *id_variable_query(char *name);
+ *
+ * DESCRIPTION
+ *      The id_variable_query function is used to obtain the value of
+ *      the named global variable, such as those the cookbook sets to
+ *      control cook's behaviour.
+ *
+ * RETURNS
+ *      string_list_ty *; NULL if there is no such variable (or the
+ *      name is not a variable).  Do not change or free the result.
+ */
+
+func id_variable_query(name string) *string_list_ty {
+	s := str_from_string(name)
+	idp, _ := symtab_query(id_global_stp(), s).(*id_ty)
+	str_free(s)
+	if idp == nil {
+		return nil
+	}
+	this, ok := idp.this.(*id_variable_ty)
+	if !ok {
+		return nil
+	}
+	return &this.value
+}
@@ -0,0 +1,676 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+/*
+ * The maximum number of errors before the lexer gives up.
+ */
+const MAX_ERRORS = 20
+
+/*
+ * The maximum nesting of #include files.
+ */
+const MAX_INCLUDE_DEPTH = 50
+
+type lex_file_ty struct {
+	name  *string_ty
+	text  []byte
+	pos   int
+	line  long
+	depth int
+	prev  *lex_file_ty
+}
+
+var lex_fp *lex_file_ty
+var lex_error_count int
+
+/*
+ * The #include-cooked files named by the cookbook, whether or not
+ * they exist yet.
+ */
+var lex_include_cooked string_list_ty
+
+/*
+ * Those of the #include-cooked files named by #include-cooked-nowarn
+ * directives, which are rebuilt without comment.
+ */
+var lex_include_cooked_nowarn string_list_ty
+
+var lex_keyword = map[string]lex_token_ty{
+	"cascade":       token_cascade,
+	"data":          token_data,
+	"else":          token_else,
+	"fail":          token_fail,
+	"function":      token_function,
+	"host-binding":  token_host_binding,
+	"if":            token_if,
+	"loop":          token_loop,
+	"loopstop":      token_loopstop,
+	"return":        token_return,
+	"set":           token_set,
+	"single-thread": token_single_thread,
+	"then":          token_then,
+}
+
+/*
+ * NAME
+ *      lex_open - open a file for lexical analysis
+ *
+ * SYNOPSIS
+ *      void lex_open(string_ty *filename);
+ *
+ * DESCRIPTION
+ *      The lex_open function is used to open the cookbook for lexical
+ *      analysis.  It resets the error count and the lists of
+ *      include-cooked files.
+ */
+
+func lex_open(filename *string_ty) {
+	trace(fmt.Sprintf("lex_open(filename = %q)\n{\n", filename.str))
+	lex_error_count = 0
+	string_list_destructor(&lex_include_cooked)
+	string_list_destructor(&lex_include_cooked_nowarn)
+	lex_push(filename, nil)
+	trace("}\n")
+}
+
+/*
+ * NAME
+ *      lex_close - finish lexical analysis
+ *
+ * SYNOPSIS
+ *      void lex_close(void);
+ *
+ * DESCRIPTION
+ *      The lex_close function is used to release the resources held by
+ *      the lexical analyser.  If there were any errors, cook exits.
+ */
+
+func lex_close() {
+	trace("lex_close()\n{\n")
+	for lex_fp != nil {
+		lex_pop()
+	}
+	if lex_error_count > 0 {
+		scp := sub_context_new()
+		sub_var_set_long(scp, "Number", long(lex_error_count))
+		error_intl(scp, i18n("found $number fatal errors"))
+		sub_context_delete(scp)
+		quit(1)
+	}
+	trace("}\n")
+}
+
+func lex_push(filename *string_ty, pp *expr_position_ty) {
+	depth := 0
+	if lex_fp != nil {
+		depth = lex_fp.depth + 1
+	}
+	if depth >= MAX_INCLUDE_DEPTH {
+		scp := sub_context_new()
+		sub_var_set_string(scp, "Name", filename)
+		lex_error(pp, scp, i18n("$name: include files nested too deeply"))
+		sub_context_delete(scp)
+		return
+	}
+
+	text, err := os.ReadFile(filename.str)
+	if err != nil {
+		if pp == nil {
+			nfatal_raw(err, "%s", filename.str)
+		}
+		scp := sub_context_new()
+		sub_var_set_string(scp, "Name", filename)
+		sub_var_set_string(scp, "ERRNO", str_from_string(err.Error()))
+		lex_error(pp, scp, i18n("open $name: $errno"))
+		sub_context_delete(scp)
+		return
+	}
+
+	lex_fp = &lex_file_ty{
+		name:  str_copy(filename),
+		text:  text,
+		line:  1,
+		depth: depth,
+		prev:  lex_fp,
+	}
+}
+
+func lex_pop() {
+	fp := lex_fp
+	lex_fp = fp.prev
+	str_free(fp.name)
+}
+
+/*
+ * NAME
+ *      lex_error - report a lexical or syntax error
+ *
+ * SYNOPSIS
+ *      void lex_error(expr_position_ty *pp, sub_context_ty *scp,
+ *              char *fmt);
+ *
+ * DESCRIPTION
+ *      The lex_error function is used to report an error at the given
+ *      position, or at the current input position if pp is NULL.  The
+ *      error is counted, and cook gives up once there are too many.
+ */
+
+func lex_error(pp *expr_position_ty, scp *sub_context_ty, msg string) {
+	if pp == nil {
+		pos := lex_position()
+		pp = &pos
+	}
+	error_with_position(pp, scp, msg)
+	lex_error_count++
+	if lex_error_count >= MAX_ERRORS {
+		scp = sub_context_new()
+		sub_var_set_string(scp, "File_Name", pp.pos_name)
+		error_intl(scp, i18n("$filename: too many errors"))
+		sub_context_delete(scp)
+		quit(1)
+	}
+}
+
+/*
+ * NAME
+ *      lex_position - current input position
+ *
+ * SYNOPSIS
+ *      expr_position_ty lex_position(void);
+ *
+ * DESCRIPTION
+ *      The lex_position function is used to obtain the current file
+ *      name and line number.
+ */
+
+func lex_position() expr_position_ty {
+	if lex_fp == nil {
+		return expr_position_ty{}
+	}
+	return expr_position_ty{pos_name: lex_fp.name, pos_line: lex_fp.line}
+}
+
+/*
+ * The lex_getc function returns the next character of the current
+ * file, or -1 at the end of it.  Included files are popped by the
+ * caller, so that no token ever spans two files.
+ */
+
+func lex_getc() int {
+	if lex_fp == nil || lex_fp.pos >= len(lex_fp.text) {
+		return -1
+	}
+	c := lex_fp.text[lex_fp.pos]
+	lex_fp.pos++
+	if c == '\n' {
+		lex_fp.line++
+	}
+	return int(c)
+}
+
+func lex_ungetc(c int) {
+	if c < 0 {
+		return
+	}
+	lex_fp.pos--
+	if c == '\n' {
+		lex_fp.line--
+	}
+}
+
+/*
+ * The lex_at_bol function is used to determine if the character just
+ * read was the first non-blank character on its line.
+ */
+
+func lex_at_bol() bool {
+	for n := lex_fp.pos - 2; n >= 0; n-- {
+		switch lex_fp.text[n] {
+		case '\n':
+			return true
+		case ' ', '\t', '\f', '\r', '\v':
+			continue
+		}
+		return false
+	}
+	return true
+}
+
+/*
+ * NAME
+ *      lex_directive - process a preprocessor directive
+ *
+ * DESCRIPTION
+ *      The lex_directive function is called when a '#' is seen at the
+ *      start of a line.  The rest of the line is the directive.
+ */
+
+func lex_directive() {
+	pos := lex_position()
+	var sb strings.Builder
+	for {
+		c := lex_getc()
+		if c < 0 || c == '\n' {
+			break
+		}
+		sb.WriteByte(byte(c))
+	}
+	line := strings.TrimSpace(sb.String())
+	name := line
+	if n := strings.IndexAny(line, " \t\"<"); n >= 0 {
+		name, line = line[:n], line[n:]
+	} else {
+		line = ""
+	}
+	args := lex_directive_args(line)
+
+	switch name {
+	case "include":
+		if len(args) != 1 {
+			lex_error(&pos, nil, i18n("#include requires exactly one file name"))
+			return
+		}
+		path := lex_include_find(args[0])
+		if path == "" {
+			scp := sub_context_new()
+			sub_var_set(scp, "Name", "%s", args[0])
+			lex_error(&pos, scp, i18n("include file $name not found"))
+			sub_context_delete(scp)
+			return
+		}
+		s := str_from_string(path)
+		lex_push(s, &pos)
+		str_free(s)
+
+	case "include-cooked", "include-cooked-nowarn":
+		/*
+		 * The include-cooked files need not exist yet, they
+		 * may be targets of this very cookbook.
+		 */
+		if len(args) == 0 {
+			lex_error(&pos, nil, i18n("#include-cooked requires file names"))
+			return
+		}
+		for n := len(args) - 1; n >= 0; n-- {
+			s := str_from_string(args[n])
+			string_list_append(&lex_include_cooked, s)
+			if name == "include-cooked-nowarn" {
+				string_list_append(&lex_include_cooked_nowarn, s)
+			}
+			if _, err := os.Stat(args[n]); err == nil {
+				lex_push(s, &pos)
+			}
+			str_free(s)
+		}
+
+	case "pragma":
+		/* ignored */
+
+	default:
+		scp := sub_context_new()
+		sub_var_set(scp, "Name", "%s", name)
+		lex_error(&pos, scp, i18n("unknown \"#$name\" directive"))
+		sub_context_delete(scp)
+	}
+}
+
+/*
+ * The lex_directive_args function is used to split the arguments of a
+ * directive into file names.  Names may be quoted with "double
+ * quotes" or <angle brackets>.
+ */
+
+func lex_directive_args(s string) []string {
+	var args []string
+	for {
+		s = strings.TrimLeft(s, " \t\f\r\v")
+		if s == "" {
+			return args
+		}
+		var end byte
+		switch s[0] {
+		case '"':
+			end = '"'
+		case '<':
+			end = '>'
+		}
+		if end != 0 {
+			n := strings.IndexByte(s[1:], end)
+			if n < 0 {
+				return append(args, s[1:])
+			}
+			args = append(args, s[1:n+1])
+			s = s[n+2:]
+			continue
+		}
+		n := strings.IndexAny(s, " \t\f\r\v")
+		if n < 0 {
+			return append(args, s)
+		}
+		args = append(args, s[:n])
+		s = s[n:]
+	}
+}
+
+/*
+ * The lex_include_find function is used to locate an include file.
+ * Absolute names are used as is, relative names are looked for in the
+ * current directory and then in each of the -Include directories.
+ */
+
+func lex_include_find(name string) string {
+	if filepath.IsAbs(name) {
+		if _, err := os.Stat(name); err == nil {
+			return name
+		}
+		return ""
+	}
+	if _, err := os.Stat(name); err == nil {
+		return name
+	}
+	for _, dir := range option.o_include.strings {
+		path := filepath.Join(dir.str, name)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+/*
+ * The lex_escape function is used to read the character following a
+ * backslash inside a quoted string.  The usual C escapes are
+ * understood, except that there are no octal escapes: a backslash
+ * followed by a digit is kept as it is, so that "\1" may be used in the
+ * replacement of a regular expression.  Any other character stands for
+ * itself.
+ */
+
+func lex_escape(sb *strings.Builder) {
+	c := lex_getc()
+	switch c {
+	case -1:
+		lex_error(nil, nil, i18n("end of file after backslash"))
+	case '\n':
+		/* line continuation */
+	case 'a':
+		sb.WriteByte('\a')
+	case 'b':
+		sb.WriteByte('\b')
+	case 'f':
+		sb.WriteByte('\f')
+	case 'n':
+		sb.WriteByte('\n')
+	case 'r':
+		sb.WriteByte('\r')
+	case 't':
+		sb.WriteByte('\t')
+	case 'v':
+		sb.WriteByte('\v')
+	case '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+		/* kept, so regular expression back references can be written */
+		sb.WriteByte('\\')
+		sb.WriteByte(byte(c))
+	default:
+		sb.WriteByte(byte(c))
+	}
+}
+
+/*
+ * The lex_is_special function is used to determine if a character
+ * terminates an unquoted word.
+ */
+
+func lex_is_special(c int) bool {
+	switch c {
+	case -1, ' ', '\t', '\n', '\f', '\r', '\v', '[', ']', '{', '}', ';', ':', '"', '\'':
+		return true
+	}
+	return false
+}
+
+/*
+ * NAME
+ *      lex - lexical analyser
+ *
+ * SYNOPSIS
+ *      token_ty lex(void);
+ *
+ * DESCRIPTION
+ *      The lex function is used to read the next token from the
+ *      cookbook.  Comments and white space are skipped, and
+ *      preprocessor directives are acted upon.
+ *
+ *      A word is a run of unquoted characters and quoted strings, with
+ *      no white space between them.  Unquoted words which match a
+ *      keyword are returned as the keyword.  An equals sign is only
+ *      special at the start of a token, so that command lines like
+ *      "cc -DFOO=1" need no quoting.
+ */
+
+func lex() token_ty {
+	var tok token_ty
+	for {
+		if lex_fp == nil {
+			tok.kind = token_eof
+			return tok
+		}
+		c := lex_getc()
+		switch c {
+		case -1:
+			if lex_fp.prev == nil {
+				tok.kind = token_eof
+				tok.pos = lex_position()
+				return tok
+			}
+			lex_pop()
+			tok.space_before = true
+			continue
+
+		case ' ', '\t', '\n', '\f', '\r', '\v':
+			tok.space_before = true
+			continue
+
+		case '/':
+			c2 := lex_getc()
+			if c2 != '*' {
+				lex_ungetc(c2)
+				break
+			}
+			pos := lex_position()
+			for prev := 0; ; {
+				c2 = lex_getc()
+				if c2 < 0 {
+					lex_error(&pos, nil, i18n("unterminated comment"))
+					break
+				}
+				if prev == '*' && c2 == '/' {
+					break
+				}
+				prev = c2
+			}
+			tok.space_before = true
+			continue
+
+		case '#':
+			if !lex_at_bol() {
+				break
+			}
+			lex_directive()
+			tok.space_before = true
+			continue
+
+		case '\\':
+			c2 := lex_getc()
+			if c2 == '\n' {
+				tok.space_before = true
+				continue
+			}
+			lex_ungetc(c2)
+		}
+
+		tok.pos = lex_position()
+		switch c {
+		case ':':
+			c2 := lex_getc()
+			if c2 == ':' {
+				tok.kind = token_colon2
+				tok.pos.multi = 2
+			} else {
+				lex_ungetc(c2)
+				tok.kind = token_colon
+				tok.pos.multi = 1
+			}
+			return tok
+		case ';':
+			tok.kind = token_semicolon
+			return tok
+		case '=':
+			tok.kind = token_equals
+			return tok
+		case '{':
+			tok.kind = token_lbrace
+			return tok
+		case '}':
+			tok.kind = token_rbrace
+			return tok
+		case '[':
+			tok.kind = token_lbracket
+			return tok
+		case ']':
+			tok.kind = token_rbracket
+			return tok
+		case '+':
+			c2 := lex_getc()
+			if c2 == '=' {
+				tok.kind = token_plus_equals
+				return tok
+			}
+			lex_ungetc(c2)
+		}
+
+		/*
+		 * Everything else is a word.
+		 */
+		var sb strings.Builder
+		quoted := false
+		for ; ; c = lex_getc() {
+			if c == '"' || c == '\'' {
+				quoted = true
+				pos := lex_position()
+				for q := c; ; {
+					c = lex_getc()
+					if c < 0 || c == '\n' {
+						lex_error(&pos, nil, i18n("unterminated string"))
+						lex_ungetc(c)
+						break
+					}
+					if c == q {
+						break
+					}
+					if c == '\\' {
+						lex_escape(&sb)
+						continue
+					}
+					sb.WriteByte(byte(c))
+				}
+				continue
+			}
+			if c == '\\' {
+				c = lex_getc()
+				if c < 0 {
+					lex_error(nil, nil, i18n("end of file after backslash"))
+					break
+				}
+				if c == '\n' {
+					/* continuation ends the word */
+					break
+				}
+				quoted = true
+				sb.WriteByte(byte(c))
+				continue
+			}
+			if lex_is_special(c) {
+				lex_ungetc(c)
+				break
+			}
+			sb.WriteByte(byte(c))
+		}
+
+		tok.kind = token_word
+		if kind, ok := lex_keyword[sb.String()]; ok && !quoted {
+			tok.kind = kind
+		}
+		tok.value = str_from_string(sb.String())
+		return tok
+	}
+}
+
+/*
+ * NAME
+ *      lex_data - read here document
+ *
+ * SYNOPSIS
+ *      string_ty *lex_data(void);
+ *
+ * DESCRIPTION
+ *      The lex_data function is used to read the text following a
+ *      "data" keyword.  The rest of the line containing the keyword is
+ *      ignored; the text is all of the following lines, up to but not
+ *      including a line containing only the word "dataend".
+ */
+
+func lex_data() *string_ty {
+	pos := lex_position()
+	for {
+		c := lex_getc()
+		if c < 0 || c == '\n' {
+			break
+		}
+	}
+
+	var sb strings.Builder
+	for {
+		var line strings.Builder
+		c := lex_getc()
+		for c >= 0 && c != '\n' {
+			line.WriteByte(byte(c))
+			c = lex_getc()
+		}
+		if strings.TrimSpace(line.String()) == "dataend" {
+			break
+		}
+		if c < 0 {
+			lex_error(&pos, nil, i18n("end of file looking for \"dataend\""))
+			break
+		}
+		sb.WriteString(line.String())
+		sb.WriteByte('\n')
+	}
+	return str_from_string(sb.String())
+}
@@ -0,0 +1,62 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+type lex_token_ty int
+
+// enum lex_token_ty
+const (
+	token_eof lex_token_ty = iota
+	token_cascade
+	token_colon
+	token_colon2
+	token_data
+	token_else
+	token_equals
+	token_fail
+	token_function
+	token_host_binding
+	token_if
+	token_lbrace
+	token_lbracket
+	token_loop
+	token_loopstop
+	token_plus_equals
+	token_rbrace
+	token_rbracket
+	token_return
+	token_semicolon
+	token_set
+	token_single_thread
+	token_then
+	token_word
+)
+
+type token_ty struct {
+	kind  lex_token_ty
+	value *string_ty       /* the text of the token */
+	pos   expr_position_ty /* where the token started */
+
+	/*
+	 * White space matters in cookbooks: adjacent words and function
+	 * calls are catenated, separated ones are not.
+	 */
+	space_before bool
+}
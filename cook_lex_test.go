@@ -0,0 +1,123 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+/*
+ * NAME
+ *      lex_test_words - the words of a text
+ *
+ * DESCRIPTION
+ *      The lex_test_words function is used to write the given text to a
+ *      file, and return the values of the word tokens read from it.
+ */
+
+func lex_test_words(t *testing.T, text string) []string {
+	t.Helper()
+	name := filepath.Join(t.TempDir(), "Howto.cook")
+	if err := ioutil.WriteFile(name, []byte(text), 0644); err != nil {
+		t.Fatal(err)
+	}
+	var result []string
+	lex_open(str_from_string(name))
+	for {
+		tok := lex()
+		if tok.kind == token_eof {
+			break
+		}
+		if tok.kind == token_word {
+			result = append(result, tok.value.str)
+			str_free(tok.value)
+		}
+	}
+	lex_close()
+	return result
+}
+
+func TestLexEscape(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{`"a\tb"`, "a\tb"},
+		{`"a\nb"`, "a\nb"},
+		{`"a\"b"`, `a"b`},
+		{`"a\\b"`, `a\b`},
+		{`"a\qb"`, "aqb"},
+		{`"\1"`, `\1`},
+		{`"\0\9"`, `\0\9`},
+		{`'s/\(.*\)\.c/\1.o/'`, `s/(.*).c/\1.o/`},
+		{`a\;b`, "a;b"},
+	}
+	for _, tt := range tests {
+		got := lex_test_words(t, tt.text+"\n")
+		if len(got) != 1 || got[0] != tt.want {
+			t.Errorf("lex(%s) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	cookbook_test_read(t, "")
+	files := map[string]string{
+		"Howto.cook": "a: b { echo a; }\n" +
+			"x = ;\n" +
+			"}\n" +
+			"#include \"inc.cook\"\n" +
+			"c: d { echo [foo; }\n" +
+			"e: f { echo \"e; }\n",
+		"inc.cook": "\n" +
+			"if then;\n" +
+			"g: h;\n",
+	}
+	for name, text := range files {
+		if err := ioutil.WriteFile(name, []byte(text), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var quitted bool
+	msg := capture_stderr(t, func() {
+		quitted = quit_test_catch(t, func() {
+			stmt_delete(parse(str_from_string("Howto.cook")))
+		})
+	})
+	if !quitted {
+		t.Errorf("parse did not quit")
+	}
+
+	/*
+	 * Each error is reported at its own position, in the included
+	 * file where appropriate, and parsing carries on after it.
+	 */
+	want := "cook: Howto.cook: 3: syntax error, unexpected \"}\"\n" +
+		"cook: inc.cook: 2: if requires a value\n" +
+		"cook: Howto.cook: 5: syntax error, unexpected \";\"\n" +
+		"cook: Howto.cook: 6: unterminated string\n" +
+		"cook: Howto.cook: 7: syntax error, unexpected end of file\n" +
+		"cook: found 5 fatal errors\n"
+	if msg != want {
+		t.Errorf("parse reported\n%s\nwant\n%s", msg, want)
+	}
+}
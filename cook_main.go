@@ -23,8 +23,312 @@ import (
 	"fmt"
 	"github.com/mdhender/gcook/internal/signals"
 	"os"
+	"strings"
 )
 
+// enum arglex_token_ty (continued)
+const (
+	arglex_token_book arglex_token_ty = ARGLEX_MAX + iota
+	arglex_token_include
+	arglex_token_disassemble
+	arglex_token_statistics
+	arglex_token_parallel
+	arglex_token_continue
+	arglex_token_fingerprint_update
+)
+
+var argtab = []arglex_table_ty{
+	{"-Book", arglex_token_book},
+	{"-Include", arglex_token_include},
+	{"-Disassemble", arglex_token_disassemble},
+	{"-STatistics", arglex_token_statistics},
+	{"-Parallel", arglex_token_parallel},
+	{"-Continue", arglex_token_continue},
+	{"-Fingerprint_Update", arglex_token_fingerprint_update},
+}
+
+/*
+ * The names cook looks for when no -Book option is given.
+ */
+var default_cookbook = []string{
+	"Howto.cook",
+	"howto.cook",
+	".howto.cook",
+	".how.to.cook",
+}
+
+func usage() {
+	progname := progname_get()
+	_, _ = fmt.Fprintf(os.Stderr, "usage: %s [ <option>... ][ <filename>... ]\n", progname)
+	_, _ = fmt.Fprintf(os.Stderr, "       %s -Help\n", progname)
+	_, _ = fmt.Fprintf(os.Stderr, "       %s -VERSion\n", progname)
+	quit(1)
+}
+
+func help() {
+	progname := progname_get()
+	fmt.Printf("NAME\n\t%s - a file construction tool\n\n", progname)
+	fmt.Printf("SYNOPSIS\n\t%s [ <option>... ][ <filename>... ][ <name>=<value>... ]\n\n", progname)
+	fmt.Printf("OPTIONS\n")
+	fmt.Printf("\t-Book <filename>\tthe cookbook to read (default %s)\n", default_cookbook[0])
+	fmt.Printf("\t-Include <directory>\tsearch this directory for #include files\n")
+	fmt.Printf("\t-Disassemble\t\tlist the compiled cookbook and recipes, don't cook\n")
+	fmt.Printf("\t-STatistics\t\tprint dependency graph statistics\n")
+	fmt.Printf("\t-Parallel <number>\trun up to this many recipes at once\n")
+	fmt.Printf("\t-Continue\t\tkeep cooking unrelated targets after an error\n")
+	fmt.Printf("\t-Fingerprint_Update\tupdate the fingerprints of all files, don't cook\n")
+	fmt.Printf("\t-Help\t\t\tthis message\n")
+	fmt.Printf("\t-VERSion\t\tthe version of %s\n", progname)
+}
+
+func version() {
+	fmt.Printf("%s version %s\n", progname_get(), version_stamp())
+	fmt.Printf("Copyright (C) %s Peter Miller\n", copyright_years())
+}
+
+/*
+ * NAME
+ *      argparse - parse command line
+ *
+ * SYNOPSIS
+ *      void argparse(void);
+ *
+ * DESCRIPTION
+ *      The argparse function is used to parse the command line, and set
+ *      the fields of the option structure accordingly.  Arguments of
+ *      the form name=value are variable assignments, all other
+ *      arguments are targets.
+ */
+
+func argparse() {
+	for arglex() != arglex_token_eoln {
+		switch arglex_token {
+		default:
+			error_raw("misplaced \"%s\" command line argument", arglex_value.alv_string)
+			usage()
+
+		case arglex_token_help:
+			help()
+			quit(0)
+
+		case arglex_token_version:
+			version()
+			quit(0)
+
+		case arglex_token_book:
+			if option.o_book != nil {
+				fatal_raw("duplicate %s option", arglex_token_name(arglex_token_book))
+			}
+			if arglex() != arglex_token_string {
+				fatal_raw("the %s option requires a file name", arglex_token_name(arglex_token_book))
+			}
+			option.o_book = str_from_string(arglex_value.alv_string)
+
+		case arglex_token_include:
+			if arglex() != arglex_token_string {
+				fatal_raw("the %s option requires a directory name", arglex_token_name(arglex_token_include))
+			}
+			s := str_from_string(arglex_value.alv_string)
+			string_list_append(&option.o_include, s)
+			str_free(s)
+
+		case arglex_token_disassemble:
+			if option.o_disassemble {
+				fatal_raw("duplicate %s option", arglex_token_name(arglex_token_disassemble))
+			}
+			option.o_disassemble = true
+
+		case arglex_token_statistics:
+			if option.o_statistics {
+				fatal_raw("duplicate %s option", arglex_token_name(arglex_token_statistics))
+			}
+			option.o_statistics = true
+
+		case arglex_token_parallel:
+			if option.o_parallel != 0 {
+				fatal_raw("duplicate %s option", arglex_token_name(arglex_token_parallel))
+			}
+			if arglex() != arglex_token_number || arglex_value.alv_number < 1 {
+				fatal_raw("the %s option requires a positive number", arglex_token_name(arglex_token_parallel))
+			}
+			option.o_parallel = arglex_value.alv_number
+
+		case arglex_token_continue:
+			if option_already(OPTION_PERSEVERE, OPTION_LEVEL_COMMAND_LINE) {
+				fatal_raw("duplicate %s option", arglex_token_name(arglex_token_continue))
+			}
+			option_set(OPTION_PERSEVERE, OPTION_LEVEL_COMMAND_LINE, true)
+
+		case arglex_token_fingerprint_update:
+			if option.o_fingerprint_update {
+				fatal_raw("duplicate %s option", arglex_token_name(arglex_token_fingerprint_update))
+			}
+			option.o_fingerprint_update = true
+
+		case arglex_token_string, arglex_token_number:
+			s := str_from_string(arglex_value.alv_string)
+			if strings.IndexByte(s.str, '=') > 0 {
+				string_list_append(&option.o_vardef, s)
+			} else {
+				string_list_append(&option.o_target, s)
+			}
+			str_free(s)
+		}
+	}
+}
+
+/*
+ * NAME
+ *      cookbook_find - locate the cookbook
+ *
+ * SYNOPSIS
+ *      void cookbook_find(void);
+ *
+ * DESCRIPTION
+ *      The cookbook_find function is used to set option.o_book to the
+ *      default cookbook, if no -Book option was given.
+ */
+
+func cookbook_find() {
+	if option.o_book != nil {
+		return
+	}
+	for _, name := range default_cookbook {
+		if _, err := os.Stat(name); err == nil {
+			option.o_book = str_from_string(name)
+			return
+		}
+	}
+	fatal_raw("no book found (tried %s)", strings.Join(default_cookbook, ", "))
+}
+
+/*
+ * NAME
+ *      vardef_assign - command line variable assignments
+ *
+ * SYNOPSIS
+ *      void vardef_assign(void);
+ *
+ * DESCRIPTION
+ *      The vardef_assign function is used to set the variables given on
+ *      the command line.  The value is split into words on white space.
+ */
+
+func vardef_assign() {
+	for _, s := range option.o_vardef.strings {
+		n := strings.IndexByte(s.str, '=')
+		name := str_from_string(s.str[:n])
+		var wl string_list_ty
+		string_list_constructor(&wl)
+		for _, word := range strings.Fields(s.str[n+1:]) {
+			w := str_from_string(word)
+			string_list_append(&wl, w)
+			str_free(w)
+		}
+		symtab_assign(id_global_stp(), name, id_variable_new(&wl))
+		string_list_destructor(&wl)
+		str_free(name)
+	}
+}
+
+/*
+ * The number of times the cookbook may be read again because its
+ * #include-cooked files changed, before cook concludes they are
+ * rebuilt every time and gives up.
+ */
+const cookbook_reread_max = 10
+
+/*
+ * NAME
+ *      cookbook_read - read the cookbook
+ *
+ * SYNOPSIS
+ *      int cookbook_read(void);
+ *
+ * DESCRIPTION
+ *      The cookbook_read function is used to parse the cookbook,
+ *      compile it, and execute it to define the recipes.  The compiled
+ *      cookbook is listed if the -Disassemble option was given.
+ *
+ * RETURNS
+ *      int; 0 on success, 1 on error (already reported).
+ */
+
+func cookbook_read() int {
+	cookbook := parse(option.o_book)
+	olp := stmt_compile(cookbook)
+	stmt_delete(cookbook)
+	if olp == nil {
+		return 1
+	}
+	if option.o_disassemble {
+		fmt.Printf("cookbook  # %s\n", option.o_book.str)
+		opcode_list_disassemble(olp)
+		fmt.Printf("\n")
+	}
+	retval := 0
+	ocp := opcode_context_new(olp, nil)
+	opcode_list_delete(olp)
+	switch opcode_context_execute_nowait(ocp) {
+	case opcode_status_success:
+		string_list_delete(opcode_context_string_list_pop(ocp))
+
+	case opcode_status_interrupted:
+		error_intl(nil, i18n("interrupted"))
+		retval = 1
+
+	default:
+		retval = 1
+	}
+	opcode_context_delete(ocp)
+	return retval
+}
+
+/*
+ * NAME
+ *      cookbook_read_cooked - read the cookbook until it settles
+ *
+ * SYNOPSIS
+ *      int cookbook_read_cooked(void);
+ *
+ * DESCRIPTION
+ *      The cookbook_read_cooked function is used to read the cookbook,
+ *      and read it again for as long as cooking the #include-cooked
+ *      files changes them, at most cookbook_reread_max times.  When
+ *      the cookbook is only to be disassembled, it is read once.
+ *
+ * RETURNS
+ *      int; 0 on success, 1 on error (already reported).
+ */
+
+func cookbook_read_cooked() int {
+	for rereads := 0; ; rereads++ {
+		if rereads > 0 {
+			cook_reset()
+			id_reset()
+			vardef_assign()
+			option_undo_level(OPTION_LEVEL_COOKBOOK)
+		}
+		if cookbook_read() != 0 {
+			return 1
+		}
+		if option.o_disassemble {
+			return 0
+		}
+		if rereads >= cookbook_reread_max {
+			error_intl(nil, i18n("the #include-cooked files keep changing"))
+			return 1
+		}
+		switch cook_auto(&lex_include_cooked, &lex_include_cooked_nowarn) {
+		case -1:
+			return 1
+
+		case 0:
+			return 0
+		}
+	}
+}
+
 /*
  * NAME
  *      main - initial entry point for cook
@@ -52,15 +356,50 @@ func main() {
 	 * we expect no matter how we are invoked.
 	 */
 	signals.Signal("SIGCHLD", "SIG_DFL")
+	desist_enable()
 
 	/*
 	 * initialize things
 	 * (order is critical here)
 	 */
 	progname_set(progname_fetch())
-	fmt.Println(progname_get())
+	language_init()
 	str_initialize()
 	id_initialize()
+	option_tidy_up()
+
+	/*
+	 * parse the command line
+	 */
+	arglex_init(os.Args[1:], argtab)
+	argparse()
+	if option.o_fingerprint_update {
+		if fingerprint_update_tree(".") < 0 {
+			quit(1)
+		}
+		quit(0)
+	}
+	cookbook_find()
+	vardef_assign()
 
-	os.Exit(retval)
+	/*
+	 * read the cookbook
+	 */
+	retval = cookbook_read_cooked()
+	if retval != 0 {
+		quit(retval)
+	}
+	if option.o_disassemble {
+		cook_disassemble()
+		quit(0)
+	}
+
+	/*
+	 * cook the targets
+	 */
+	if len(option.o_target.strings) == 0 {
+		cook_default_targets(&option.o_target)
+	}
+	retval = cook(&option.o_target)
+	quit(retval)
 }
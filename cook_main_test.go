@@ -0,0 +1,265 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+/*
+ * The tests share the one process, so initialize things the same way
+ * main does (order is critical here), then run them.
+ */
+
+func TestMain(m *testing.M) {
+	progname_set("cook")
+	language_init()
+	str_initialize()
+	id_initialize()
+	option_tidy_up()
+	os.Exit(m.Run())
+}
+
+/*
+ * NAME
+ *      capture_stderr - collect error messages
+ *
+ * DESCRIPTION
+ *      The capture_stderr function is used to run a function and
+ *      return whatever it wrote to the standard error stream, so that
+ *      tests can check the error messages.
+ */
+
+func capture_stderr(t *testing.T, f func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	save := os.Stderr
+	os.Stderr = w
+	done := make(chan []byte)
+	go func() {
+		data, _ := ioutil.ReadAll(r)
+		done <- data
+	}()
+	f()
+	os.Stderr = save
+	w.Close()
+	data := <-done
+	r.Close()
+	return string(data)
+}
+
+/*
+ * NAME
+ *      capture_stdout - collect printed output
+ *
+ * DESCRIPTION
+ *      The capture_stdout function is used to run a function and
+ *      return whatever it wrote to the standard output stream.
+ */
+
+func capture_stdout(t *testing.T, f func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	save := os.Stdout
+	os.Stdout = w
+	done := make(chan []byte)
+	go func() {
+		data, _ := ioutil.ReadAll(r)
+		done <- data
+	}()
+	f()
+	os.Stdout = save
+	w.Close()
+	data := <-done
+	r.Close()
+	return string(data)
+}
+
+/*
+ * NAME
+ *      quit_test_catch - run something which may quit
+ *
+ * DESCRIPTION
+ *      The quit_test_catch function is used to run a function which
+ *      may call quit, as cook does after fatal errors, without ending
+ *      the test process.  It reports whether quit was called.
+ */
+
+type quit_test_ty struct{}
+
+func quit_test_catch(t *testing.T, f func()) (quitted bool) {
+	t.Helper()
+	save := quit_list_prio
+	quit_list_prio = append(quit_list_prio[:len(quit_list_prio):len(quit_list_prio)], func() { panic(quit_test_ty{}) })
+	defer func() {
+		quit_list_prio = save
+		if r := recover(); r != nil {
+			if _, ok := r.(quit_test_ty); !ok {
+				panic(r)
+			}
+			quitted = true
+		}
+	}()
+	f()
+	return false
+}
+
+/*
+ * NAME
+ *      cookbook_test_read - read a cookbook for a test
+ *
+ * DESCRIPTION
+ *      The cookbook_test_read function is used to create the given
+ *      files (an empty text creates an empty file) in a temporary
+ *      directory, change into it, and read the Howto.cook from there.
+ *      Recipes, variables, cached file status and fingerprints from
+ *      previous tests are forgotten first.  The working directory is
+ *      restored when the test finishes.
+ */
+
+func cookbook_test_read(t *testing.T, book string, files ...string) {
+	t.Helper()
+	dir := t.TempDir()
+	if err := ioutil.WriteFile(filepath.Join(dir, "Howto.cook"), []byte(book), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = os.Chdir(cwd)
+		stat_cache_clear_all()
+	})
+
+	cook_reset()
+	id_reset()
+	option_undo_level(OPTION_LEVEL_COOKBOOK)
+	stat_cache_clear_all()
+	fingerprint_cache = make(map[string]*fingerprint_cache_ty)
+	cookbook := parse(str_from_string("Howto.cook"))
+	olp := stmt_compile(cookbook)
+	stmt_delete(cookbook)
+	if olp == nil {
+		t.Fatalf("can't compile cookbook:\n%s", book)
+	}
+	ocp := opcode_context_new(olp, nil)
+	opcode_list_delete(olp)
+	if opcode_context_execute_nowait(ocp) != opcode_status_success {
+		t.Fatalf("can't read cookbook:\n%s", book)
+	}
+	string_list_delete(opcode_context_string_list_pop(ocp))
+	opcode_context_delete(ocp)
+}
+
+/*
+ * NAME
+ *      cookbook_test_read_cooked - read a cookbook until it settles
+ *
+ * DESCRIPTION
+ *      The cookbook_test_read_cooked function is used to read the
+ *      cookbook written by cookbook_test_read again, the way cook does,
+ *      cooking its #include-cooked files.  It returns the result and
+ *      the error messages.
+ */
+
+func cookbook_test_read_cooked(t *testing.T) (int, string) {
+	t.Helper()
+	save := option.o_book
+	option.o_book = str_from_string("Howto.cook")
+	defer func() { option.o_book = save }()
+	stat_cache_clear_all()
+	var status int
+	msg := capture_stderr(t, func() {
+		status = cookbook_read_cooked()
+	})
+	return status, msg
+}
+
+func TestCookbookReadCooked(t *testing.T) {
+	book := "#include-cooked deps\n" +
+		"deps: src { echo 'v = [collect cat src];' > [target]; }\n"
+	cookbook_test_read(t, book, "src")
+	if err := ioutil.WriteFile("src", []byte("1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	file_test_age(t, "src", 100)
+	status, msg := cookbook_test_read_cooked(t)
+	if status != 0 {
+		t.Fatalf("cookbook_read_cooked = %d\n%s", status, msg)
+	}
+	if want := "cook: cookbook changed, rereading\n"; msg != want {
+		t.Errorf("reported %q, want %q", msg, want)
+	}
+	got := id_variable_query("v")
+	if got == nil || wl2str(got, 0, len(got.strings)-1, " ").str != "1" {
+		t.Errorf("v = %v, want 1", got)
+	}
+
+	/*
+	 * Nothing has changed, so the cookbook is read once.
+	 */
+	status, msg = cookbook_test_read_cooked(t)
+	if status != 0 || msg != "" {
+		t.Errorf("second read = %d, reported %q", status, msg)
+	}
+}
+
+func TestCookbookReadCookedLimit(t *testing.T) {
+	book := "#include-cooked-nowarn deps\n" +
+		"deps: set force { echo pass >> log; echo 'x = 1;' > [target]; }\n"
+	cookbook_test_read(t, book)
+	status, msg := cookbook_test_read_cooked(t)
+	if status != 1 {
+		t.Errorf("cookbook_read_cooked = %d, want 1", status)
+	}
+	if want := "cook: the #include-cooked files keep changing\n"; msg != want {
+		t.Errorf("reported %q, want %q", msg, want)
+	}
+	data, err := ioutil.ReadFile("log")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(string(data), "pass\n"); n != cookbook_reread_max {
+		t.Errorf("deps cooked %d times, want %d", n, cookbook_reread_max)
+	}
+}
@@ -0,0 +1,192 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import "fmt"
+
+/*
+ * NAME
+ *      match_instance_new - create a matcher
+ *
+ * SYNOPSIS
+ *      match_ty *match_instance_new(match_method_ty *, void *this);
+ *
+ * DESCRIPTION
+ *      The match_instance_new function is used to create a new matcher
+ *      of the class described by the method.  The derived instance is
+ *      remembered in the "this" field, and the methods recover it with
+ *      a type assertion.
+ *
+ * RETURNS
+ *      match_ty *; use match_delete when you are done with it.
+ */
+
+func match_instance_new(mp *match_method_ty, this interface{}) *match_ty {
+	trace(fmt.Sprintf("match_instance_new(mp = %q)\n{\n", mp.name))
+	assert(this != nil, "this != nil")
+	p := &match_ty{vptr: mp, this: this}
+	if mp.constructor != nil {
+		mp.constructor(p)
+	}
+	trace(fmt.Sprintf("return %p;\n", p))
+	trace("}\n")
+	return p
+}
+
+/*
+ * NAME
+ *      match_new - create a matcher
+ *
+ * SYNOPSIS
+ *      match_ty *match_new(void);
+ *
+ * DESCRIPTION
+ *      The match_new function is used to create a new matcher, of the
+ *      kind selected by the current match mode.  Set the recipe's flags
+ *      first if matching on behalf of a recipe.
+ *
+ * RETURNS
+ *      match_ty *; use match_delete when you are done with it.
+ */
+
+func match_new() *match_ty {
+	if option_test(OPTION_MATCH_MODE_REGEX) {
+		return match_regex_new()
+	}
+	return match_cook_new()
+}
+
+/*
+ * NAME
+ *      match_delete - release a matcher
+ *
+ * SYNOPSIS
+ *      void match_delete(match_ty *);
+ *
+ * DESCRIPTION
+ *      The match_delete function is used to release a matcher when it
+ *      is finished with.  It is safe to pass NULL.
+ */
+
+func match_delete(mp *match_ty) {
+	if mp == nil {
+		return
+	}
+	if mp.vptr.destructor != nil {
+		mp.vptr.destructor(mp)
+	}
+	mp.vptr = nil /* paranoia */
+	mp.this = nil
+}
+
+/*
+ * NAME
+ *      match_attempt - attempt to match a pattern
+ *
+ * SYNOPSIS
+ *      int match_attempt(match_ty *, string_ty *pattern, string_ty *s,
+ *              expr_position_ty *pp);
+ *
+ * DESCRIPTION
+ *      The match_attempt function is used to match a string against a
+ *      pattern.  On success, the fields of the pattern are remembered
+ *      by the matcher, for use by match_reconstruct_lhs and
+ *      match_reconstruct_rhs.
+ *
+ * RETURNS
+ *      int; 1 if the string matches, 0 if it does not, -1 on error
+ *      (already reported).
+ */
+
+func match_attempt(mp *match_ty, pattern, s *string_ty, pp *expr_position_ty) int {
+	trace(fmt.Sprintf("match_attempt(pattern = %q, s = %q)\n{\n", pattern.str, s.str))
+	result := mp.vptr.compile(mp, pattern, pp)
+	if result >= 0 {
+		result = mp.vptr.execute(mp, s, pp)
+	}
+	trace(fmt.Sprintf("return %d;\n", result))
+	trace("}\n")
+	return result
+}
+
+/*
+ * NAME
+ *      match_reconstruct_lhs - rebuild a target
+ *
+ * SYNOPSIS
+ *      string_ty *match_reconstruct_lhs(match_ty *, string_ty *pattern,
+ *              expr_position_ty *pp);
+ *
+ * DESCRIPTION
+ *      The match_reconstruct_lhs function is used to build a string
+ *      from a target pattern, using the fields of the last successful
+ *      match.  This is how the other targets of a recipe with more
+ *      than one target pattern are named.
+ *
+ * RETURNS
+ *      string_ty *; NULL on error (already reported).
+ */
+
+func match_reconstruct_lhs(mp *match_ty, pattern *string_ty, pp *expr_position_ty) *string_ty {
+	return mp.vptr.reconstruct_lhs(mp, pattern, pp)
+}
+
+/*
+ * NAME
+ *      match_reconstruct_rhs - rebuild an ingredient
+ *
+ * SYNOPSIS
+ *      string_ty *match_reconstruct_rhs(match_ty *, string_ty *pattern,
+ *              expr_position_ty *pp);
+ *
+ * DESCRIPTION
+ *      The match_reconstruct_rhs function is used to build a string
+ *      from an ingredient pattern, using the fields of the last
+ *      successful match.
+ *
+ * RETURNS
+ *      string_ty *; NULL on error (already reported).
+ */
+
+func match_reconstruct_rhs(mp *match_ty, pattern *string_ty, pp *expr_position_ty) *string_ty {
+	return mp.vptr.reconstruct_rhs(mp, pattern, pp)
+}
+
+/*
+ * NAME
+ *      match_usage_mask - fields used by a pattern
+ *
+ * SYNOPSIS
+ *      int match_usage_mask(match_ty *, string_ty *pattern,
+ *              expr_position_ty *pp);
+ *
+ * DESCRIPTION
+ *      The match_usage_mask function is used to determine which fields
+ *      a pattern uses.  A pattern which uses no fields is not a
+ *      pattern at all, it is a plain file name.
+ *
+ * RETURNS
+ *      int; a bit mask, one bit per field, or -1 on error (already
+ *      reported).
+ */
+
+func match_usage_mask(mp *match_ty, pattern *string_ty, pp *expr_position_ty) int {
+	return mp.vptr.usage_mask(mp, pattern, pp)
+}
@@ -0,0 +1,365 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+/*
+ * The cook pattern language.  A pattern is plain text with embedded
+ * fields:
+ *
+ *      %       matches one or more characters, not including slash
+ *      %1..%9  as for %, but numbered, so that more than one field may
+ *              be used
+ *      %0      matches zero or more leading directory components,
+ *              including the trailing slash, e.g. "" or "a/b/"
+ *      %%      is a literal %, for example in "printf %%s"
+ *
+ * A field which appears more than once in a pattern must match the
+ * same text each time.  The bare % is field 10, it is distinct from
+ * the numbered fields.
+ */
+
+const match_cook_fields = 11
+
+type match_cook_part_ty struct {
+	text  string /* literal text, when field < 0 */
+	field int
+}
+
+type match_cook_ty struct {
+	pattern *string_ty /* the pattern most recently compiled */
+	part    []match_cook_part_ty
+	fill    [match_cook_fields]*string_ty
+}
+
+func match_cook_destructor(mp *match_ty) {
+	this, ok := mp.this.(*match_cook_ty)
+	assert(ok, "mp.this.(*match_cook_ty)")
+	if this.pattern != nil {
+		str_free(this.pattern)
+		this.pattern = nil
+	}
+	match_cook_clear(this)
+}
+
+func match_cook_clear(this *match_cook_ty) {
+	for j, s := range this.fill {
+		if s != nil {
+			str_free(s)
+			this.fill[j] = nil
+		}
+	}
+}
+
+/*
+ * NAME
+ *      match_cook_parse - break a pattern into parts
+ *
+ * SYNOPSIS
+ *      void match_cook_parse(string_ty *pattern,
+ *              match_cook_part_ty **result);
+ *
+ * DESCRIPTION
+ *      The match_cook_parse function is used to break a pattern into
+ *      literal text and field references.
+ */
+
+func match_cook_parse(pattern string) []match_cook_part_ty {
+	var result []match_cook_part_ty
+	var text strings.Builder
+	for j := 0; j < len(pattern); j++ {
+		c := pattern[j]
+		if c != '%' {
+			text.WriteByte(c)
+			continue
+		}
+		if j+1 < len(pattern) && pattern[j+1] == '%' {
+			j++
+			text.WriteByte('%')
+			continue
+		}
+		if text.Len() > 0 {
+			result = append(result, match_cook_part_ty{text: text.String(), field: -1})
+			text.Reset()
+		}
+		field := 10
+		if j+1 < len(pattern) && pattern[j+1] >= '0' && pattern[j+1] <= '9' {
+			j++
+			field = int(pattern[j] - '0')
+		}
+		result = append(result, match_cook_part_ty{field: field})
+	}
+	if text.Len() > 0 {
+		result = append(result, match_cook_part_ty{text: text.String(), field: -1})
+	}
+	return result
+}
+
+/*
+ * NAME
+ *      match_cook_compile
+ *
+ * SYNOPSIS
+ *      int match_cook_compile(match_ty *, string_ty *pattern,
+ *              expr_position_ty *pp);
+ *
+ * DESCRIPTION
+ *      The match_cook_compile function is used to prepare a pattern for
+ *      matching.  The most recent pattern is remembered, so that
+ *      matching many strings against the one pattern is cheap.
+ *
+ * RETURNS
+ *      int; 0 on success, -1 on error.
+ */
+
+func match_cook_compile(mp *match_ty, pattern *string_ty, pp *expr_position_ty) int {
+	this, ok := mp.this.(*match_cook_ty)
+	assert(ok, "mp.this.(*match_cook_ty)")
+	if this.pattern != nil && str_equal(this.pattern, pattern) {
+		return 0
+	}
+	if this.pattern != nil {
+		str_free(this.pattern)
+	}
+	this.pattern = str_copy(pattern)
+	this.part = match_cook_parse(pattern.str)
+	return 0
+}
+
+/*
+ * NAME
+ *      match_cook_execute
+ *
+ * SYNOPSIS
+ *      int match_cook_execute(match_ty *, string_ty *s,
+ *              expr_position_ty *pp);
+ *
+ * DESCRIPTION
+ *      The match_cook_execute function is used to match a string
+ *      against the most recently compiled pattern.  On success the
+ *      fields are set; on failure all fields are cleared.
+ *
+ * RETURNS
+ *      int; 1 on match, 0 on no match.
+ */
+
+func match_cook_execute(mp *match_ty, s *string_ty, pp *expr_position_ty) int {
+	this, ok := mp.this.(*match_cook_ty)
+	assert(ok, "mp.this.(*match_cook_ty)")
+	match_cook_clear(this)
+	var fill [match_cook_fields]string
+	var set [match_cook_fields]bool
+	if !match_cook_execute_part(this.part, s.str, &fill, &set) {
+		trace(fmt.Sprintf("%q does not match %q\n", s.str, this.pattern.str))
+		return 0
+	}
+	for j := range fill {
+		if set[j] {
+			this.fill[j] = str_from_string(fill[j])
+		}
+	}
+	return 1
+}
+
+/*
+ * NAME
+ *      match_cook_execute_part
+ *
+ * DESCRIPTION
+ *      The match_cook_execute_part function is used to match the
+ *      remaining parts of a pattern against the remaining text.  It
+ *      backtracks over the possible lengths of each field.
+ */
+
+func match_cook_execute_part(part []match_cook_part_ty, s string, fill *[match_cook_fields]string, set *[match_cook_fields]bool) bool {
+	if len(part) == 0 {
+		return s == ""
+	}
+	p := part[0]
+	if p.field < 0 {
+		if !strings.HasPrefix(s, p.text) {
+			return false
+		}
+		return match_cook_execute_part(part[1:], s[len(p.text):], fill, set)
+	}
+	if set[p.field] {
+		if !strings.HasPrefix(s, fill[p.field]) {
+			return false
+		}
+		return match_cook_execute_part(part[1:], s[len(fill[p.field]):], fill, set)
+	}
+	set[p.field] = true
+	if p.field == 0 {
+		/*
+		 * zero or more whole directory components
+		 */
+		for n := 0; n <= len(s); n++ {
+			if n > 0 && s[n-1] != '/' {
+				continue
+			}
+			fill[0] = s[:n]
+			if match_cook_execute_part(part[1:], s[n:], fill, set) {
+				return true
+			}
+		}
+	} else {
+		/*
+		 * one or more characters, not including slash
+		 */
+		for n := 1; n <= len(s) && s[n-1] != '/'; n++ {
+			fill[p.field] = s[:n]
+			if match_cook_execute_part(part[1:], s[n:], fill, set) {
+				return true
+			}
+		}
+	}
+	set[p.field] = false
+	return false
+}
+
+/*
+ * NAME
+ *      match_cook_reconstruct
+ *
+ * SYNOPSIS
+ *      string_ty *match_cook_reconstruct(match_ty *, string_ty *pattern,
+ *              expr_position_ty *pp);
+ *
+ * DESCRIPTION
+ *      The match_cook_reconstruct function is used to build a string
+ *      from a pattern, replacing each field with the text it matched.
+ *      It is used for both targets and ingredients.  Fields this match
+ *      did not set are taken from the enclosing matches, if any.
+ *
+ * RETURNS
+ *      string_ty *; NULL on error (already reported).
+ */
+
+func match_cook_reconstruct(mp *match_ty, pattern *string_ty, pp *expr_position_ty) *string_ty {
+	var sb strings.Builder
+	for _, p := range match_cook_parse(pattern.str) {
+		if p.field < 0 {
+			sb.WriteString(p.text)
+			continue
+		}
+		fill := match_cook_fill(mp, p.field)
+		if fill == nil {
+			scp := sub_context_new()
+			sub_var_set_string(scp, "Name", pattern)
+			sub_var_set(scp, "Field", "%s", match_cook_field_name(p.field))
+			error_with_position(pp, scp, i18n("pattern \"$name\": the $field field was not matched"))
+			sub_context_delete(scp)
+			return nil
+		}
+		sb.WriteString(fill.str)
+	}
+	return str_from_string(sb.String())
+}
+
+/*
+ * NAME
+ *      match_cook_fill
+ *
+ * DESCRIPTION
+ *      The match_cook_fill function is used to find the text of a
+ *      field, searching the enclosing cook matches if this match did
+ *      not set it.  Returns NULL if no match set it.
+ */
+
+func match_cook_fill(mp *match_ty, field int) *string_ty {
+	for ; mp != nil; mp = mp.outer {
+		this, ok := mp.this.(*match_cook_ty)
+		if !ok {
+			break
+		}
+		if this.fill[field] != nil {
+			return this.fill[field]
+		}
+	}
+	return nil
+}
+
+func match_cook_field_name(field int) string {
+	if field == 10 {
+		return "%"
+	}
+	return fmt.Sprintf("%%%d", field)
+}
+
+/*
+ * NAME
+ *      match_cook_usage_mask
+ *
+ * SYNOPSIS
+ *      int match_cook_usage_mask(match_ty *, string_ty *pattern,
+ *              expr_position_ty *pp);
+ *
+ * DESCRIPTION
+ *      The match_cook_usage_mask function is used to determine which
+ *      fields the pattern uses.  Bit n is set if field %n is used, bit
+ *      10 for the bare %.
+ *
+ * RETURNS
+ *      int; the bit mask.
+ */
+
+func match_cook_usage_mask(mp *match_ty, pattern *string_ty, pp *expr_position_ty) int {
+	mask := 0
+	for _, p := range match_cook_parse(pattern.str) {
+		if p.field >= 0 {
+			mask |= 1 << uint(p.field)
+		}
+	}
+	return mask
+}
+
+var match_cook_method = match_method_ty{
+	name:            "cook",
+	size:            0, //todo: sizeof(match_cook_ty),
+	destructor:      match_cook_destructor,
+	compile:         match_cook_compile,
+	execute:         match_cook_execute,
+	reconstruct_lhs: match_cook_reconstruct,
+	reconstruct_rhs: match_cook_reconstruct,
+	usage_mask:      match_cook_usage_mask,
+}
+
+/*
+ * NAME
+ *      match_cook_new - create a cook pattern matcher
+ *
+ * SYNOPSIS
+ *      match_ty *match_cook_new(void);
+ *
+ * DESCRIPTION
+ *      The match_cook_new function is used to create a new matcher for
+ *      the cook pattern language.
+ *
+ * RETURNS
+ *      match_ty *; use match_delete when you are done with it.
+ */
+
+func match_cook_new() *match_ty {
+	return match_instance_new(&match_cook_method, &match_cook_ty{})
+}
@@ -0,0 +1,176 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMatchCookAttempt(t *testing.T) {
+	tests := []struct {
+		pattern string
+		s       string
+		want    int
+		fields  map[int]string
+	}{
+		{"%.o", "foo.o", 1, map[int]string{10: "foo"}},
+		{"%.o", "foo.c", 0, nil},
+		{"%.o", ".o", 0, nil},
+		{"%.o", "dir/foo.o", 0, nil},
+		{"%1.%2", "foo.tar", 1, map[int]string{1: "foo", 2: "tar"}},
+		{"%1.%2", "a.b.c", 1, map[int]string{1: "a", 2: "b.c"}},
+		{"%1-%1", "ab-ab", 1, map[int]string{1: "ab"}},
+		{"%1-%1", "ab-cd", 0, nil},
+		{"%0%.o", "foo.o", 1, map[int]string{0: "", 10: "foo"}},
+		{"%0%.o", "a/foo.o", 1, map[int]string{0: "a/", 10: "foo"}},
+		{"%0%.o", "a/b/foo.o", 1, map[int]string{0: "a/b/", 10: "foo"}},
+		{"%0%.o", "a/b/", 0, nil},
+		{"%0bin/%", "src/bin/x", 1, map[int]string{0: "src/", 10: "x"}},
+		{"%0bin/%", "bin/x", 1, map[int]string{0: "", 10: "x"}},
+		{"plain", "plain", 1, map[int]string{}},
+		{"plain", "plainer", 0, nil},
+		{"%%.o", "%.o", 1, map[int]string{}},
+		{"%%.o", "x.o", 0, nil},
+		{"%1%%%2", "a%b", 1, map[int]string{1: "a", 2: "b"}},
+	}
+	for _, tt := range tests {
+		mp := match_cook_new()
+		pattern := str_from_string(tt.pattern)
+		s := str_from_string(tt.s)
+		got := match_attempt(mp, pattern, s, nil)
+		if got != tt.want {
+			t.Errorf("match_attempt(%q, %q) = %d, want %d", tt.pattern, tt.s, got, tt.want)
+		}
+		this := mp.this.(*match_cook_ty)
+		for field := 0; field < match_cook_fields; field++ {
+			want, set := tt.fields[field]
+			fill := this.fill[field]
+			switch {
+			case !set && fill != nil:
+				t.Errorf("match_attempt(%q, %q): field %s = %q, want unset", tt.pattern, tt.s, match_cook_field_name(field), fill.str)
+			case set && fill == nil:
+				t.Errorf("match_attempt(%q, %q): field %s unset, want %q", tt.pattern, tt.s, match_cook_field_name(field), want)
+			case set && fill.str != want:
+				t.Errorf("match_attempt(%q, %q): field %s = %q, want %q", tt.pattern, tt.s, match_cook_field_name(field), fill.str, want)
+			}
+		}
+		str_free(pattern)
+		str_free(s)
+		match_delete(mp)
+	}
+}
+
+func TestMatchCookReconstruct(t *testing.T) {
+	tests := []struct {
+		target  string
+		s       string
+		pattern string
+		want    string
+		err     string
+	}{
+		{"%.o", "foo.o", "%.c", "foo.c", ""},
+		{"%.o", "foo.o", "%.d", "foo.d", ""},
+		{"%0%.o", "a/b/foo.o", "%0%.c", "a/b/foo.c", ""},
+		{"%0%.o", "foo.o", "%0src/%.c", "src/foo.c", ""},
+		{"%0%.o", "a/foo.o", "%0.deps/%.d", "a/.deps/foo.d", ""},
+		{"%1/%2.o", "lib/x.o", "%2/%1.c", "x/lib.c", ""},
+		{"%.o", "foo.o", "%-%.c", "foo-foo.c", ""},
+		{"%.o", "foo.o", "printf %%s", "printf %s", ""},
+		{"%.o", "foo.o", "%%%", "%foo", ""},
+		{"%.o", "foo.o", "%5.c", "", `pattern "%5.c": the %5 field was not matched`},
+		{"%1.o", "foo.o", "%.c", "", `pattern "%.c": the % field was not matched`},
+		{"%.o", "foo.o", "%0%.c", "", `pattern "%0%.c": the %0 field was not matched`},
+	}
+	pos := expr_position_ty{pos_name: str_from_string("test.cook"), pos_line: 7}
+	for _, tt := range tests {
+		mp := match_cook_new()
+		target := str_from_string(tt.target)
+		s := str_from_string(tt.s)
+		if match_attempt(mp, target, s, &pos) != 1 {
+			t.Fatalf("match_attempt(%q, %q) did not match", tt.target, tt.s)
+		}
+		pattern := str_from_string(tt.pattern)
+		for _, side := range []struct {
+			name string
+			f    func(*match_ty, *string_ty, *expr_position_ty) *string_ty
+		}{
+			{"match_reconstruct_lhs", match_reconstruct_lhs},
+			{"match_reconstruct_rhs", match_reconstruct_rhs},
+		} {
+			var got *string_ty
+			msg := capture_stderr(t, func() {
+				got = side.f(mp, pattern, &pos)
+			})
+			if tt.err != "" {
+				if got != nil {
+					t.Errorf("%s(%q) = %q, want error", side.name, tt.pattern, got.str)
+				}
+				want := "test.cook: 7: " + tt.err
+				if !strings.Contains(msg, want) {
+					t.Errorf("%s(%q) reported %q, want %q", side.name, tt.pattern, msg, want)
+				}
+			} else {
+				if got == nil || got.str != tt.want {
+					t.Errorf("%s(%q) = %v, want %q", side.name, tt.pattern, got, tt.want)
+				}
+				if msg != "" {
+					t.Errorf("%s(%q) reported %q", side.name, tt.pattern, msg)
+				}
+			}
+			if got != nil {
+				str_free(got)
+			}
+		}
+		str_free(pattern)
+		str_free(target)
+		str_free(s)
+		match_delete(mp)
+	}
+}
+
+/*
+ * Fields the inner match does not set come from the enclosing match,
+ * the way an implicit recipe's ingredients see the fields of the
+ * recipe which needed them.
+ */
+
+func TestMatchCookReconstructOuter(t *testing.T) {
+	msp := match_stack_new()
+	outer := match_cook_new()
+	inner := match_cook_new()
+	if match_attempt(outer, str_from_string("%0%1.o"), str_from_string("d/x.o"), nil) != 1 {
+		t.Fatal("outer match failed")
+	}
+	if match_attempt(inner, str_from_string("%.c"), str_from_string("y.c"), nil) != 1 {
+		t.Fatal("inner match failed")
+	}
+	match_stack_push(msp, outer)
+	match_stack_push(msp, inner)
+	got := match_reconstruct_rhs(inner, str_from_string("%0%1-%.h"), nil)
+	if got == nil || got.str != "d/x-y.h" {
+		t.Errorf("match_reconstruct_rhs = %v, want \"d/x-y.h\"", got)
+	}
+	match_stack_pop(msp)
+	match_stack_pop(msp)
+	match_stack_delete(msp)
+	match_delete(inner)
+	match_delete(outer)
+}
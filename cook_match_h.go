@@ -21,4 +21,12 @@ package main
 
 type match_ty struct {
 	vptr *match_method_ty
+	this interface{} /* the derived instance, see match_instance_new */
+
+	/*
+	 * The enclosing match, if any, see match_stack_push.  Fields not
+	 * set by this match are taken from the enclosing match when
+	 * reconstructing.
+	 */
+	outer *match_ty
 }
@@ -22,7 +22,7 @@ package main
 type char = byte
 
 type match_method_ty struct {
-	name            *char
+	name            string
 	size            int
 	destructor      func(*match_ty)
 	constructor     func(*match_ty)
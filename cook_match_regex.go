@@ -0,0 +1,338 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"regexp"
+	"strings"
+)
+
+/*
+ * The regular expression pattern language, selected by the
+ * match-mode-regex flag.  Target patterns are regular expressions (Go
+ * syntax), which must match the whole file name.  Ingredient patterns
+ * are plain text, with \1 through \9 replaced by the text matched by
+ * the corresponding parenthesised subexpression of the target, and
+ * \0 by the whole target.
+ */
+
+type match_regex_ty struct {
+	pattern *string_ty /* the pattern most recently compiled */
+	re      *regexp.Regexp
+	fill    []string /* of the last successful match */
+}
+
+func match_regex_destructor(mp *match_ty) {
+	this, ok := mp.this.(*match_regex_ty)
+	assert(ok, "mp.this.(*match_regex_ty)")
+	if this.pattern != nil {
+		str_free(this.pattern)
+		this.pattern = nil
+	}
+	this.re = nil
+	this.fill = nil
+}
+
+/*
+ * NAME
+ *      match_regex_compile
+ *
+ * SYNOPSIS
+ *      int match_regex_compile(match_ty *, string_ty *pattern,
+ *              expr_position_ty *pp);
+ *
+ * DESCRIPTION
+ *      The match_regex_compile function is used to compile a regular
+ *      expression.  The most recent pattern is remembered, so that
+ *      matching many strings against the one pattern is cheap.
+ *
+ * RETURNS
+ *      int; 0 on success, -1 on error (already reported).
+ */
+
+func match_regex_compile(mp *match_ty, pattern *string_ty, pp *expr_position_ty) int {
+	this, ok := mp.this.(*match_regex_ty)
+	assert(ok, "mp.this.(*match_regex_ty)")
+	if this.pattern != nil && str_equal(this.pattern, pattern) {
+		return 0
+	}
+	if this.pattern != nil {
+		str_free(this.pattern)
+		this.pattern = nil
+	}
+	this.re = nil
+	this.fill = nil
+	/*
+	 * Check the pattern as written, so that error messages make
+	 * sense, then anchor it to match whole file names.
+	 */
+	_, err := regexp.Compile(pattern.str)
+	var re *regexp.Regexp
+	if err == nil {
+		re, err = regexp.Compile("^(?:" + pattern.str + ")$")
+	}
+	if err != nil {
+		scp := sub_context_new()
+		sub_var_set_string(scp, "Name", pattern)
+		sub_var_set(scp, "Text", "%s", err.Error())
+		error_with_position(pp, scp, i18n("pattern \"$name\": $text"))
+		sub_context_delete(scp)
+		return -1
+	}
+	this.pattern = str_copy(pattern)
+	this.re = re
+	return 0
+}
+
+/*
+ * NAME
+ *      match_regex_execute
+ *
+ * SYNOPSIS
+ *      int match_regex_execute(match_ty *, string_ty *s,
+ *              expr_position_ty *pp);
+ *
+ * DESCRIPTION
+ *      The match_regex_execute function is used to match a string
+ *      against the most recently compiled regular expression.
+ *
+ * RETURNS
+ *      int; 1 on match, 0 on no match.
+ */
+
+func match_regex_execute(mp *match_ty, s *string_ty, pp *expr_position_ty) int {
+	this, ok := mp.this.(*match_regex_ty)
+	assert(ok, "mp.this.(*match_regex_ty)")
+	assert(this.re != nil, "this.re != nil")
+	this.fill = this.re.FindStringSubmatch(s.str)
+	if this.fill == nil {
+		return 0
+	}
+	return 1
+}
+
+/*
+ * NAME
+ *      match_regex_field
+ *
+ * DESCRIPTION
+ *      The match_regex_field function is used to obtain the text of a
+ *      subexpression of the last successful match, searching the
+ *      enclosing regular expression matches if this match has no such
+ *      subexpression.
+ */
+
+func match_regex_field(mp *match_ty, n int, pattern *string_ty, pp *expr_position_ty) (string, bool) {
+	for ; mp != nil; mp = mp.outer {
+		this, ok := mp.this.(*match_regex_ty)
+		if !ok {
+			break
+		}
+		if n < len(this.fill) {
+			return this.fill[n], true
+		}
+	}
+	scp := sub_context_new()
+	sub_var_set_string(scp, "Name", pattern)
+	sub_var_set(scp, "Field", "\\%d", n)
+	error_with_position(pp, scp, i18n("pattern \"$name\": the $field field was not matched"))
+	sub_context_delete(scp)
+	return "", false
+}
+
+/*
+ * NAME
+ *      match_regex_reconstruct_rhs
+ *
+ * SYNOPSIS
+ *      string_ty *match_regex_reconstruct_rhs(match_ty *,
+ *              string_ty *pattern, expr_position_ty *pp);
+ *
+ * DESCRIPTION
+ *      The match_regex_reconstruct_rhs function is used to build an
+ *      ingredient name, replacing \0 through \9 with the text of the
+ *      last successful match.  A doubled backslash is a backslash.
+ *
+ * RETURNS
+ *      string_ty *; NULL on error (already reported).
+ */
+
+func match_regex_reconstruct_rhs(mp *match_ty, pattern *string_ty, pp *expr_position_ty) *string_ty {
+	_, ok := mp.this.(*match_regex_ty)
+	assert(ok, "mp.this.(*match_regex_ty)")
+	var sb strings.Builder
+	s := pattern.str
+	for j := 0; j < len(s); j++ {
+		c := s[j]
+		if c != '\\' || j+1 >= len(s) {
+			sb.WriteByte(c)
+			continue
+		}
+		j++
+		c = s[j]
+		if c < '0' || c > '9' {
+			sb.WriteByte(c)
+			continue
+		}
+		text, ok := match_regex_field(mp, int(c-'0'), pattern, pp)
+		if !ok {
+			return nil
+		}
+		sb.WriteString(text)
+	}
+	return str_from_string(sb.String())
+}
+
+/*
+ * NAME
+ *      match_regex_reconstruct_lhs
+ *
+ * SYNOPSIS
+ *      string_ty *match_regex_reconstruct_lhs(match_ty *,
+ *              string_ty *pattern, expr_position_ty *pp);
+ *
+ * DESCRIPTION
+ *      The match_regex_reconstruct_lhs function is used to build a
+ *      target name from a target regular expression.  Each
+ *      parenthesised subexpression is replaced by the text it would
+ *      match, taken from the last successful match, in order; escaped
+ *      characters stand for themselves.  Any other regular expression
+ *      operator makes the pattern impossible to reconstruct.
+ *
+ * RETURNS
+ *      string_ty *; NULL on error (already reported).
+ */
+
+func match_regex_reconstruct_lhs(mp *match_ty, pattern *string_ty, pp *expr_position_ty) *string_ty {
+	_, ok := mp.this.(*match_regex_ty)
+	assert(ok, "mp.this.(*match_regex_ty)")
+	var sb strings.Builder
+	s := pattern.str
+	group := 0
+	for j := 0; j < len(s); j++ {
+		c := s[j]
+		switch {
+		case c == '\\' && j+1 < len(s):
+			j++
+			sb.WriteByte(s[j])
+
+		case c == '(':
+			depth := 1
+			for depth > 0 {
+				j++
+				if j >= len(s) {
+					break
+				}
+				switch s[j] {
+				case '\\':
+					j++
+				case '(':
+					depth++
+				case ')':
+					depth--
+				}
+			}
+			group++
+			text, ok := match_regex_field(mp, group, pattern, pp)
+			if !ok {
+				return nil
+			}
+			sb.WriteString(text)
+
+		case c == '^' && j == 0:
+		case c == '$' && j == len(s)-1:
+
+		case strings.IndexByte(".[]{}*+?|)^$", c) >= 0:
+			scp := sub_context_new()
+			sub_var_set_string(scp, "Name", pattern)
+			error_with_position(pp, scp, i18n("pattern \"$name\": unable to reconstruct target from regular expression"))
+			sub_context_delete(scp)
+			return nil
+
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return str_from_string(sb.String())
+}
+
+/*
+ * NAME
+ *      match_regex_usage_mask
+ *
+ * SYNOPSIS
+ *      int match_regex_usage_mask(match_ty *, string_ty *pattern,
+ *              expr_position_ty *pp);
+ *
+ * DESCRIPTION
+ *      The match_regex_usage_mask function is used to determine which
+ *      fields the pattern uses.  A regular expression which can only
+ *      match a single literal string uses no fields; otherwise bit 0
+ *      is set, plus bit n for each parenthesised subexpression n.
+ *
+ * RETURNS
+ *      int; the bit mask, or -1 on error (already reported).
+ */
+
+func match_regex_usage_mask(mp *match_ty, pattern *string_ty, pp *expr_position_ty) int {
+	this, ok := mp.this.(*match_regex_ty)
+	assert(ok, "mp.this.(*match_regex_ty)")
+	if match_regex_compile(mp, pattern, pp) < 0 {
+		return -1
+	}
+	if _, complete := this.re.LiteralPrefix(); complete {
+		return 0
+	}
+	mask := 1
+	for n := 1; n <= this.re.NumSubexp() && n <= 9; n++ {
+		mask |= 1 << uint(n)
+	}
+	return mask
+}
+
+var match_regex_method = match_method_ty{
+	name:            "regex",
+	size:            0, //todo: sizeof(match_regex_ty),
+	destructor:      match_regex_destructor,
+	compile:         match_regex_compile,
+	execute:         match_regex_execute,
+	reconstruct_lhs: match_regex_reconstruct_lhs,
+	reconstruct_rhs: match_regex_reconstruct_rhs,
+	usage_mask:      match_regex_usage_mask,
+}
+
+/*
+ * NAME
+ *      match_regex_new - create a regular expression matcher
+ *
+ * SYNOPSIS
+ *      match_ty *match_regex_new(void);
+ *
+ * DESCRIPTION
+ *      The match_regex_new function is used to create a new matcher for
+ *      regular expression patterns.
+ *
+ * RETURNS
+ *      match_ty *; use match_delete when you are done with it.
+ */
+
+func match_regex_new() *match_ty {
+	return match_instance_new(&match_regex_method, &match_regex_ty{})
+}
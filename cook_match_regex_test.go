@@ -0,0 +1,168 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMatchRegexAttempt(t *testing.T) {
+	tests := []struct {
+		pattern string
+		s       string
+		want    int
+		err     string
+	}{
+		{`(.*)\.o`, "foo.o", 1, ""},
+		{`(.*)\.o`, "foo.c", 0, ""},
+		{`(.*)\.o`, "foo.o.bak", 0, ""},
+		{`foo|bar`, "bar", 1, ""},
+		{`foo|bar`, "foobar", 0, ""},
+		{`([a-z]+)/([0-9]+)\.txt`, "abc/42.txt", 1, ""},
+		{`(.*\.o`, "foo.o", -1, "missing closing )"},
+		{`[a-`, "a", -1, "missing closing ]"},
+		{`x**`, "x", -1, "invalid nested repetition operator"},
+	}
+	pos := expr_position_ty{pos_name: str_from_string("test.cook"), pos_line: 3}
+	for _, tt := range tests {
+		mp := match_regex_new()
+		pattern := str_from_string(tt.pattern)
+		s := str_from_string(tt.s)
+		var got int
+		msg := capture_stderr(t, func() {
+			got = match_attempt(mp, pattern, s, &pos)
+		})
+		if got != tt.want {
+			t.Errorf("match_attempt(%q, %q) = %d, want %d", tt.pattern, tt.s, got, tt.want)
+		}
+		if tt.err == "" && msg != "" {
+			t.Errorf("match_attempt(%q, %q) reported %q", tt.pattern, tt.s, msg)
+		}
+		if tt.err != "" {
+			want := "test.cook: 3: pattern \"" + tt.pattern + "\": "
+			if !strings.Contains(msg, want) || !strings.Contains(msg, tt.err) {
+				t.Errorf("match_attempt(%q, %q) reported %q, want %q and %q", tt.pattern, tt.s, msg, want, tt.err)
+			}
+		}
+		str_free(pattern)
+		str_free(s)
+		match_delete(mp)
+	}
+}
+
+func TestMatchRegexReconstruct(t *testing.T) {
+	tests := []struct {
+		target string
+		s      string
+		rhs    string
+		want   string
+		err    string
+	}{
+		{`(.*)\.o`, "foo.o", `\1.c`, "foo.c", ""},
+		{`(.*)\.o`, "foo.o", `\0.bak`, "foo.o.bak", ""},
+		{`(.*)/(.*)\.o`, "lib/x.o", `\2/\1.c`, "x/lib.c", ""},
+		{`(a)(b)(c)(d)(e)(f)(g)(h)(i)`, "abcdefghi", `\9\8\7\6\5\4\3\2\1`, "ihgfedcba", ""},
+		{`(.*)\.o`, "foo.o", `\1\1.c`, "foofoo.c", ""},
+		{`(.*)\.o`, "foo.o", `a\\b\1`, `a\bfoo`, ""},
+		{`(.*)\.o`, "foo.o", `\x\1`, "xfoo", ""},
+		{`(.*)\.o`, "foo.o", `\2.c`, "", `pattern "\2.c": the \2 field was not matched`},
+		{`.*\.o`, "foo.o", `\1.c`, "", `pattern "\1.c": the \1 field was not matched`},
+	}
+	pos := expr_position_ty{pos_name: str_from_string("test.cook"), pos_line: 5}
+	for _, tt := range tests {
+		mp := match_regex_new()
+		target := str_from_string(tt.target)
+		s := str_from_string(tt.s)
+		if match_attempt(mp, target, s, &pos) != 1 {
+			t.Fatalf("match_attempt(%q, %q) did not match", tt.target, tt.s)
+		}
+		rhs := str_from_string(tt.rhs)
+		var got *string_ty
+		msg := capture_stderr(t, func() {
+			got = match_reconstruct_rhs(mp, rhs, &pos)
+		})
+		if tt.err != "" {
+			if got != nil {
+				t.Errorf("match_reconstruct_rhs(%q) = %q, want error", tt.rhs, got.str)
+			}
+			want := "test.cook: 5: " + tt.err
+			if !strings.Contains(msg, want) {
+				t.Errorf("match_reconstruct_rhs(%q) reported %q, want %q", tt.rhs, msg, want)
+			}
+		} else if got == nil || got.str != tt.want {
+			t.Errorf("match_reconstruct_rhs(%q) = %v, want %q", tt.rhs, got, tt.want)
+		}
+		if got != nil {
+			str_free(got)
+		}
+		str_free(rhs)
+		str_free(target)
+		str_free(s)
+		match_delete(mp)
+	}
+}
+
+func TestMatchRegexReconstructLhs(t *testing.T) {
+	tests := []struct {
+		target string
+		s      string
+		lhs    string
+		want   string
+		err    string
+	}{
+		{`(.*)\.o`, "foo.o", `(.*)\.d`, "foo.d", ""},
+		{`^(.*)/(.*)\.o$`, "a/b.o", `^(.*)/(.*)\.d$`, "a/b.d", ""},
+		{`(.*)\.o`, "foo.o", `(x(y))\.d`, "foo.d", ""},
+		{`(.*)\.o`, "foo.o", `(.*)\.d|(.*)\.e`, "", "unable to reconstruct target from regular expression"},
+		{`(.*)\.o`, "foo.o", `.*\.d`, "", "unable to reconstruct target from regular expression"},
+		{`(.*)\.o`, "foo.o", `(.*)/(.*)\.d`, "", `the \2 field was not matched`},
+	}
+	for _, tt := range tests {
+		mp := match_regex_new()
+		target := str_from_string(tt.target)
+		s := str_from_string(tt.s)
+		if match_attempt(mp, target, s, nil) != 1 {
+			t.Fatalf("match_attempt(%q, %q) did not match", tt.target, tt.s)
+		}
+		lhs := str_from_string(tt.lhs)
+		var got *string_ty
+		msg := capture_stderr(t, func() {
+			got = match_reconstruct_lhs(mp, lhs, nil)
+		})
+		if tt.err != "" {
+			if got != nil {
+				t.Errorf("match_reconstruct_lhs(%q) = %q, want error", tt.lhs, got.str)
+			}
+			if !strings.Contains(msg, tt.err) {
+				t.Errorf("match_reconstruct_lhs(%q) reported %q, want %q", tt.lhs, msg, tt.err)
+			}
+		} else if got == nil || got.str != tt.want {
+			t.Errorf("match_reconstruct_lhs(%q) = %v, want %q", tt.lhs, got, tt.want)
+		}
+		if got != nil {
+			str_free(got)
+		}
+		str_free(lhs)
+		str_free(target)
+		str_free(s)
+		match_delete(mp)
+	}
+}
@@ -0,0 +1,130 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+/*
+ * NAME
+ *      match_stack_new - create a match stack
+ *
+ * SYNOPSIS
+ *      match_stack_ty *match_stack_new(void);
+ *
+ * DESCRIPTION
+ *      The match_stack_new function is used to create a new, empty,
+ *      match stack.  Each interpretation context has one, so that the
+ *      fields of the implicit recipe being evaluated are available to
+ *      the functions it calls.
+ *
+ * RETURNS
+ *      match_stack_ty *; use match_stack_delete when you are done with
+ *      it.
+ */
+
+func match_stack_new() *match_stack_ty {
+	return &match_stack_ty{}
+}
+
+/*
+ * NAME
+ *      match_stack_delete - release a match stack
+ *
+ * SYNOPSIS
+ *      void match_stack_delete(match_stack_ty *);
+ *
+ * DESCRIPTION
+ *      The match_stack_delete function is used to release a match
+ *      stack.  The stack does not own the matches on it, they are not
+ *      deleted.
+ */
+
+func match_stack_delete(msp *match_stack_ty) {
+	for len(msp.stack) > 0 {
+		match_stack_pop(msp)
+	}
+}
+
+/*
+ * NAME
+ *      match_stack_push - push a match
+ *
+ * SYNOPSIS
+ *      void match_stack_push(match_stack_ty *, match_ty *);
+ *
+ * DESCRIPTION
+ *      The match_stack_push function is used to push a match onto the
+ *      stack.  The previous top of the stack becomes the enclosing
+ *      match, so fields the new match does not set are still visible
+ *      when reconstructing.  A NULL match may be pushed, it hides the
+ *      enclosing matches (for explicit recipes, say).
+ */
+
+func match_stack_push(msp *match_stack_ty, mp *match_ty) {
+	if mp != nil {
+		mp.outer = match_stack_top(msp)
+	}
+	msp.stack = append(msp.stack, mp)
+}
+
+/*
+ * NAME
+ *      match_stack_pop - pop a match
+ *
+ * SYNOPSIS
+ *      match_ty *match_stack_pop(match_stack_ty *);
+ *
+ * DESCRIPTION
+ *      The match_stack_pop function is used to remove the top-most
+ *      match from the stack.  It is not deleted.
+ *
+ * RETURNS
+ *      match_ty *; the match which was on top of the stack.
+ */
+
+func match_stack_pop(msp *match_stack_ty) *match_ty {
+	assert(len(msp.stack) > 0, "len(msp.stack) > 0")
+	mp := msp.stack[len(msp.stack)-1]
+	msp.stack = msp.stack[:len(msp.stack)-1]
+	if mp != nil {
+		mp.outer = nil
+	}
+	return mp
+}
+
+/*
+ * NAME
+ *      match_stack_top - top of match stack
+ *
+ * SYNOPSIS
+ *      match_ty *match_stack_top(match_stack_ty *);
+ *
+ * DESCRIPTION
+ *      The match_stack_top function is used to obtain the top-most
+ *      match of the stack, without removing it.
+ *
+ * RETURNS
+ *      match_ty *; NULL if the stack is empty.
+ */
+
+func match_stack_top(msp *match_stack_ty) *match_ty {
+	if len(msp.stack) == 0 {
+		return nil
+	}
+	return msp.stack[len(msp.stack)-1]
+}
@@ -20,7 +20,7 @@
 package main
 
 type match_stack_ty struct {
-	stack_depth     size_t
-	stack_depth_max size_t
-	stack           **match_ty
+	// stack_depth     size_t
+	// stack_depth_max size_t
+	stack []*match_ty
 }
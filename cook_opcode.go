@@ -0,0 +1,149 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import "fmt"
+
+/*
+ * NAME
+ *      opcode_new
+ *
+ * SYNOPSIS
+ *      opcode_ty *opcode_new(opcode_method_ty *, void *this);
+ *
+ * DESCRIPTION
+ *      The opcode_new function is used to create a new opcode of the
+ *      class described by the method.  The derived instance is
+ *      remembered in the "this" field, the methods recover it with a
+ *      type assertion.
+ *
+ * RETURNS
+ *      opcode_ty *; use opcode_delete when you are done with it.
+ */
+
+func opcode_new(mp *opcode_method_ty, this interface{}) *opcode_ty {
+	trace(fmt.Sprintf("opcode_new(mp = %q)\n{\n", mp.name))
+	assert(mp != nil, "mp != nil")
+	assert(this != nil, "this != nil")
+	op := &opcode_ty{
+		method: mp,
+		this:   this,
+	}
+	trace(fmt.Sprintf("return %p;\n", op))
+	trace("}\n")
+	return op
+}
+
+/*
+ * NAME
+ *      opcode_delete
+ *
+ * SYNOPSIS
+ *      void opcode_delete(opcode_ty *);
+ *
+ * DESCRIPTION
+ *      The opcode_delete function is used to release the resources
+ *      held by an opcode.
+ */
+
+func opcode_delete(op *opcode_ty) {
+	assert(op != nil, "op != nil")
+	assert(op.method != nil, "op.method != nil")
+	if op.method.destructor != nil {
+		op.method.destructor(op)
+	}
+	op.method = nil /* paranoia */
+	op.this = nil
+}
+
+/*
+ * NAME
+ *      opcode_execute
+ *
+ * SYNOPSIS
+ *      opcode_status_ty opcode_execute(opcode_ty *, opcode_context_ty *);
+ *
+ * DESCRIPTION
+ *      The opcode_execute function is used to execute the given opcode
+ *      within the given interpretation context.
+ *
+ * RETURNS
+ *      opcode_status_ty to indicate the result of the execution
+ */
+
+func opcode_execute(op *opcode_ty, ocp *opcode_context_ty) opcode_status_ty {
+	trace(fmt.Sprintf("opcode_execute(op = %p, ocp = %p)\n{\n", op, ocp))
+	trace(fmt.Sprintf("op is a %q\n", op.method.name))
+	assert(op.method.execute != nil, "op.method.execute != nil")
+	status := op.method.execute(op, ocp)
+	trace(fmt.Sprintf("return %d;\n", status))
+	trace("}\n")
+	return status
+}
+
+/*
+ * NAME
+ *      opcode_script
+ *
+ * SYNOPSIS
+ *      opcode_status_ty opcode_script(opcode_ty *, opcode_context_ty *);
+ *
+ * DESCRIPTION
+ *      The opcode_script function is used to execute the given opcode
+ *      within the given interpretation context, for the purposes of
+ *      writing a shell script.  Opcodes without a script method are
+ *      executed normally.
+ *
+ * RETURNS
+ *      opcode_status_ty to indicate the result of the execution
+ */
+
+func opcode_script(op *opcode_ty, ocp *opcode_context_ty) opcode_status_ty {
+	if op.method.script == nil {
+		return opcode_execute(op, ocp)
+	}
+	return op.method.script(op, ocp)
+}
+
+/*
+ * Positions are left out of the disassembly while an opcode list is
+ * being fingerprinted, see opcode_list_text.
+ */
+var opcode_disassemble_positions = true
+
+/*
+ * NAME
+ *      opcode_disassemble_position
+ *
+ * SYNOPSIS
+ *      char *opcode_disassemble_position(expr_position_ty *);
+ *
+ * DESCRIPTION
+ *      The opcode_disassemble_position function is used by the
+ *      disassemble methods of opcodes to describe the source position
+ *      the opcode was compiled from.
+ */
+
+func opcode_disassemble_position(pp *expr_position_ty) string {
+	if pp.pos_name == nil || !opcode_disassemble_positions {
+		return ""
+	}
+	return fmt.Sprintf("# %s: %d", pp.pos_name.str, pp.pos_line)
+}
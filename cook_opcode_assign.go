@@ -0,0 +1,133 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import "strings"
+
+/*
+ * The assign opcode pops the value (top-most) and the name (next) from
+ * the value stack, and assigns the value to the named variable.  The
+ * append form adds the value to the end of the variable's existing
+ * value.
+ */
+
+type opcode_assign_ty struct {
+	pos    expr_position_ty
+	append bool
+}
+
+func opcode_assign_destructor(op *opcode_ty) {
+	this, ok := op.this.(*opcode_assign_ty)
+	assert(ok, "op.this.(*opcode_assign_ty)")
+	expr_position_destructor(&this.pos)
+}
+
+/*
+ * NAME
+ *      opcode_assign_execute
+ *
+ * SYNOPSIS
+ *      opcode_status_ty opcode_assign_execute(opcode_ty *,
+ *              opcode_context_ty *);
+ *
+ * DESCRIPTION
+ *      The opcode_assign_execute function is used to execute the given
+ *      opcode within the given interpretation context.
+ */
+
+func opcode_assign_execute(op *opcode_ty, ocp *opcode_context_ty) opcode_status_ty {
+	this, ok := op.this.(*opcode_assign_ty)
+	assert(ok, "op.this.(*opcode_assign_ty)")
+	value := opcode_context_string_list_pop(ocp)
+	name := opcode_context_string_list_pop(ocp)
+	status := opcode_status_success
+	if len(name.strings) != 1 {
+		scp := sub_context_new()
+		sub_var_set_long(scp, "Number", long(len(name.strings)))
+		error_with_position(&this.pos, scp, i18n("assignment requires exactly one variable name (was given $number)"))
+		sub_context_delete(scp)
+		status = opcode_status_error
+	} else {
+		var result string_list_ty
+		string_list_constructor(&result)
+		if this.append {
+			idp := opcode_context_id_search(ocp, name.strings[0])
+			if idp != nil {
+				if vp, ok := idp.this.(*id_variable_ty); ok {
+					string_list_append_list(&result, &vp.value)
+				}
+			}
+		}
+		string_list_append_list(&result, value)
+		opcode_context_id_assign(ocp, name.strings[0], id_variable_new(&result))
+		string_list_destructor(&result)
+	}
+	string_list_delete(name)
+	string_list_delete(value)
+	return status
+}
+
+/*
+ * NAME
+ *      opcode_assign_disassemble
+ *
+ * SYNOPSIS
+ *      char *opcode_assign_disassemble(opcode_ty *);
+ *
+ * DESCRIPTION
+ *      The opcode_assign_disassemble function is used to disassemble
+ *      the opcode's form and position.
+ */
+
+func opcode_assign_disassemble(op *opcode_ty) string {
+	this, ok := op.this.(*opcode_assign_ty)
+	assert(ok, "op.this.(*opcode_assign_ty)")
+	result := ""
+	if this.append {
+		result = "append"
+	}
+	return strings.TrimSpace(result + " " + opcode_disassemble_position(&this.pos))
+}
+
+var opcode_assign_method = opcode_method_ty{
+	name:        "assign",
+	destructor:  opcode_assign_destructor,
+	execute:     opcode_assign_execute,
+	script:      opcode_assign_execute,
+	disassemble: opcode_assign_disassemble,
+}
+
+/*
+ * NAME
+ *      opcode_assign_new
+ *
+ * SYNOPSIS
+ *      opcode_ty *opcode_assign_new(int append, expr_position_ty *);
+ *
+ * DESCRIPTION
+ *      The opcode_assign_new function is used to allocate a new
+ *      instance of an assign opcode.
+ */
+
+func opcode_assign_new(append bool, pp *expr_position_ty) *opcode_ty {
+	this := &opcode_assign_ty{append: append}
+	expr_position_copy_constructor(&this.pos, pp)
+	return opcode_new(&opcode_assign_method, this)
+}
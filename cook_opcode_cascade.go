@@ -0,0 +1,108 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+/*
+ * The cascade opcode pops the ingredients (top-most) and the targets
+ * (next) from the value stack, and remembers the cascade.
+ */
+
+type opcode_cascade_ty struct {
+	pos expr_position_ty
+}
+
+func opcode_cascade_destructor(op *opcode_ty) {
+	this, ok := op.this.(*opcode_cascade_ty)
+	assert(ok, "op.this.(*opcode_cascade_ty)")
+	expr_position_destructor(&this.pos)
+}
+
+/*
+ * NAME
+ *      opcode_cascade_execute
+ *
+ * SYNOPSIS
+ *      opcode_status_ty opcode_cascade_execute(opcode_ty *,
+ *              opcode_context_ty *);
+ *
+ * DESCRIPTION
+ *      The opcode_cascade_execute function is used to execute the given
+ *      opcode within the given interpretation context.
+ */
+
+func opcode_cascade_execute(op *opcode_ty, ocp *opcode_context_ty) opcode_status_ty {
+	this, ok := op.this.(*opcode_cascade_ty)
+	assert(ok, "op.this.(*opcode_cascade_ty)")
+	ingredient := opcode_context_string_list_pop(ocp)
+	target := opcode_context_string_list_pop(ocp)
+	defer string_list_delete(ingredient)
+	defer string_list_delete(target)
+
+	if len(target.strings) == 0 {
+		error_with_position(&this.pos, nil, i18n("cascade has no targets"))
+		return opcode_status_error
+	}
+	cook_cascade_append(cascade_new(target, ingredient, &this.pos))
+	return opcode_status_success
+}
+
+/*
+ * NAME
+ *      opcode_cascade_disassemble
+ *
+ * SYNOPSIS
+ *      char *opcode_cascade_disassemble(opcode_ty *);
+ *
+ * DESCRIPTION
+ *      The opcode_cascade_disassemble function is used to disassemble
+ *      the opcode's position.
+ */
+
+func opcode_cascade_disassemble(op *opcode_ty) string {
+	this, ok := op.this.(*opcode_cascade_ty)
+	assert(ok, "op.this.(*opcode_cascade_ty)")
+	return opcode_disassemble_position(&this.pos)
+}
+
+var opcode_cascade_method = opcode_method_ty{
+	name:        "cascade",
+	destructor:  opcode_cascade_destructor,
+	execute:     opcode_cascade_execute,
+	script:      opcode_cascade_execute,
+	disassemble: opcode_cascade_disassemble,
+}
+
+/*
+ * NAME
+ *      opcode_cascade_new
+ *
+ * SYNOPSIS
+ *      opcode_ty *opcode_cascade_new(expr_position_ty *);
+ *
+ * DESCRIPTION
+ *      The opcode_cascade_new function is used to allocate a new
+ *      instance of a cascade opcode.
+ */
+
+func opcode_cascade_new(pp *expr_position_ty) *opcode_ty {
+	this := &opcode_cascade_ty{}
+	expr_position_copy_constructor(&this.pos, pp)
+	return opcode_new(&opcode_cascade_method, this)
+}
@@ -0,0 +1,125 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import "fmt"
+
+/*
+ * The catenate opcode pops the given number of string lists from the
+ * value stack, and joins them end to end: the last word of each list
+ * is joined to the first word of the next.  An empty list contributes
+ * nothing.  The result is appended to the (new) top-most string list.
+ */
+
+type opcode_catenate_ty struct {
+	n size_t
+}
+
+/*
+ * NAME
+ *      opcode_catenate_execute
+ *
+ * SYNOPSIS
+ *      opcode_status_ty opcode_catenate_execute(opcode_ty *,
+ *              opcode_context_ty *);
+ *
+ * DESCRIPTION
+ *      The opcode_catenate_execute function is used to execute the
+ *      given opcode within the given interpretation context.
+ */
+
+func opcode_catenate_execute(op *opcode_ty, ocp *opcode_context_ty) opcode_status_ty {
+	this, ok := op.this.(*opcode_catenate_ty)
+	assert(ok, "op.this.(*opcode_catenate_ty)")
+	assert(size_t(len(ocp.value_stack)) > this.n, "len(ocp.value_stack) > n")
+
+	base := size_t(len(ocp.value_stack)) - this.n
+	parts := make([]*string_list_ty, this.n)
+	copy(parts, ocp.value_stack[base:])
+	ocp.value_stack = ocp.value_stack[:base]
+
+	var result string_list_ty
+	string_list_constructor(&result)
+	for _, slp := range parts {
+		if len(slp.strings) == 0 {
+			string_list_delete(slp)
+			continue
+		}
+		if len(result.strings) == 0 {
+			string_list_append_list(&result, slp)
+			string_list_delete(slp)
+			continue
+		}
+		last := len(result.strings) - 1
+		s := str_catenate(result.strings[last], slp.strings[0])
+		str_free(result.strings[last])
+		result.strings[last] = s
+		for _, w := range slp.strings[1:] {
+			string_list_append(&result, w)
+		}
+		string_list_delete(slp)
+	}
+	opcode_context_string_push_list(ocp, &result)
+	string_list_destructor(&result)
+	return opcode_status_success
+}
+
+/*
+ * NAME
+ *      opcode_catenate_disassemble
+ *
+ * SYNOPSIS
+ *      char *opcode_catenate_disassemble(opcode_ty *);
+ *
+ * DESCRIPTION
+ *      The opcode_catenate_disassemble function is used to disassemble
+ *      the opcode's count.
+ */
+
+func opcode_catenate_disassemble(op *opcode_ty) string {
+	this, ok := op.this.(*opcode_catenate_ty)
+	assert(ok, "op.this.(*opcode_catenate_ty)")
+	return fmt.Sprintf("%d", this.n)
+}
+
+var opcode_catenate_method = opcode_method_ty{
+	name:        "catenate",
+	execute:     opcode_catenate_execute,
+	script:      opcode_catenate_execute,
+	disassemble: opcode_catenate_disassemble,
+}
+
+/*
+ * NAME
+ *      opcode_catenate_new
+ *
+ * SYNOPSIS
+ *      opcode_ty *opcode_catenate_new(size_t n);
+ *
+ * DESCRIPTION
+ *      The opcode_catenate_new function is used to allocate a new
+ *      instance of a catenate opcode, which will join the top-most n
+ *      string lists of the value stack.
+ */
+
+func opcode_catenate_new(n size_t) *opcode_ty {
+	assert(n >= 2, "n >= 2")
+	return opcode_new(&opcode_catenate_method, &opcode_catenate_ty{n: n})
+}
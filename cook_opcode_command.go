@@ -0,0 +1,268 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+/*
+ * The command opcode runs a command.  The command's flags, if it has a
+ * set clause, are the top-most string list of the value stack, the
+ * command words are next.  Both are popped.
+ */
+
+type opcode_command_ty struct {
+	has_flags bool
+	data      *string_ty /* NULL if no data clause */
+	pos       expr_position_ty
+}
+
+func opcode_command_destructor(op *opcode_ty) {
+	this, ok := op.this.(*opcode_command_ty)
+	assert(ok, "op.this.(*opcode_command_ty)")
+	if this.data != nil {
+		str_free(this.data)
+	}
+	expr_position_destructor(&this.pos)
+}
+
+/*
+ * NAME
+ *      opcode_command_text
+ *
+ * SYNOPSIS
+ *      string_ty *opcode_command_text(string_list_ty *);
+ *
+ * DESCRIPTION
+ *      The opcode_command_text function is used to form the text of a
+ *      command from its words.  Words are separated by single spaces;
+ *      words which are empty or contain white space (they can only have
+ *      come from quoted strings in the cookbook) are quoted, so that
+ *      the shell sees them as single words.  Everything else is passed
+ *      as-is, so that redirections and the like keep working.
+ */
+
+func opcode_command_text(slp *string_list_ty) *string_ty {
+	var sb strings.Builder
+	for j, s := range slp.strings {
+		if j > 0 {
+			sb.WriteByte(' ')
+		}
+		if s.str == "" || strings.ContainsAny(s.str, " \t\n") {
+			sb.WriteByte('\'')
+			sb.WriteString(strings.ReplaceAll(s.str, "'", "'\\''"))
+			sb.WriteByte('\'')
+		} else {
+			sb.WriteString(s.str)
+		}
+	}
+	return str_from_string(sb.String())
+}
+
+/*
+ * NAME
+ *      opcode_command_execute
+ *
+ * SYNOPSIS
+ *      opcode_status_ty opcode_command_execute(opcode_ty *,
+ *              opcode_context_ty *);
+ *
+ * DESCRIPTION
+ *      The opcode_command_execute function is used to execute the given
+ *      opcode within the given interpretation context.  In the body of
+ *      an implicit recipe, the command words are reconstructed from the
+ *      recipe's match; "%%" stands for a literal "%".  The command is
+ *      echoed first, unless the silent option is in effect.
+ *
+ *      The command is started (on the context's host, if it is bound
+ *      to one), and opcode_status_wait is returned.
+ *      The words and flags are kept in the context meanwhile.  Once the
+ *      child process has exited, and its exit status placed in the
+ *      context, the opcode is executed again to finish the job.
+ */
+
+func opcode_command_execute(op *opcode_ty, ocp *opcode_context_ty) opcode_status_ty {
+	this, ok := op.this.(*opcode_command_ty)
+	assert(ok, "op.this.(*opcode_command_ty)")
+	if ocp.pid != 0 {
+		return opcode_command_resume(this, ocp)
+	}
+
+	var flags *flag_ty
+	if this.has_flags {
+		slp := opcode_context_string_list_pop(ocp)
+		flags = flag_recast(slp, &this.pos)
+		string_list_delete(slp)
+		if flags == nil {
+			string_list_delete(opcode_context_string_list_pop(ocp))
+			return opcode_status_error
+		}
+	}
+	args := opcode_context_string_list_pop(ocp)
+	if len(args.strings) == 0 {
+		string_list_delete(args)
+		return opcode_status_success
+	}
+
+	/*
+	 * The commands in the body of an implicit recipe are patterns.
+	 */
+	if mp := opcode_context_match_top(ocp); mp != nil {
+		wl := &string_list_ty{}
+		for _, s := range args.strings {
+			s2 := match_reconstruct_rhs(mp, s, &this.pos)
+			if s2 == nil {
+				string_list_delete(wl)
+				string_list_delete(args)
+				return opcode_status_error
+			}
+			string_list_append(wl, s2)
+			str_free(s2)
+		}
+		string_list_delete(args)
+		args = wl
+	}
+
+	flag_set_options(flags, OPTION_LEVEL_EXECUTE)
+	defer option_undo_level(OPTION_LEVEL_EXECUTE)
+
+	cmd := opcode_command_text(args)
+	defer str_free(cmd)
+	if !option_test(OPTION_SILENT) {
+		star_eoln()
+		fmt.Println(cmd.str)
+		_ = fflush_slowly(os.Stdout)
+	}
+	if !option_test(OPTION_ACTION) {
+		string_list_delete(args)
+		return opcode_status_success
+	}
+
+	if ocp.host_binding != nil {
+		ocp.pid = runner_start(ocp.host_binding, cmd, this.data, &this.pos)
+	} else {
+		ocp.pid = os_execute_start(cmd, this.data, &this.pos)
+	}
+	if ocp.pid < 0 {
+		ocp.pid = 0
+		string_list_delete(args)
+		return opcode_status_error
+	}
+	ocp.wlp = args
+	ocp.flags = flags
+	return opcode_status_wait
+}
+
+/*
+ * NAME
+ *      opcode_command_resume
+ *
+ * SYNOPSIS
+ *      opcode_status_ty opcode_command_resume(opcode_command_ty *,
+ *              opcode_context_ty *);
+ *
+ * DESCRIPTION
+ *      The opcode_command_resume function is used to finish a command,
+ *      once its child process has exited.  The exit status is checked,
+ *      and the state kept in the context released.
+ */
+
+func opcode_command_resume(this *opcode_command_ty, ocp *opcode_context_ty) opcode_status_ty {
+	args, ok := ocp.wlp.(*string_list_ty)
+	assert(ok, "ocp.wlp.(*string_list_ty)")
+	flags, _ := ocp.flags.(*flag_ty)
+	ocp.pid = 0
+	ocp.wlp = nil
+	ocp.flags = nil
+	string_list_delete(args)
+	if ocp.exit_status == 0 {
+		return opcode_status_success
+	}
+
+	flag_set_options(flags, OPTION_LEVEL_EXECUTE)
+	defer option_undo_level(OPTION_LEVEL_EXECUTE)
+	scp := sub_context_new()
+	defer sub_context_delete(scp)
+	sub_var_set_long(scp, "Status", long(ocp.exit_status))
+	if option_test(OPTION_ERROK) {
+		error_with_position(&this.pos, scp, i18n("command exit status $status (ignored)"))
+		return opcode_status_success
+	}
+	error_with_position(&this.pos, scp, i18n("command exit status $status"))
+	return opcode_status_error
+}
+
+/*
+ * NAME
+ *      opcode_command_disassemble
+ *
+ * SYNOPSIS
+ *      char *opcode_command_disassemble(opcode_ty *);
+ *
+ * DESCRIPTION
+ *      The opcode_command_disassemble function is used to disassemble
+ *      the opcode's operands and position.
+ */
+
+func opcode_command_disassemble(op *opcode_ty) string {
+	this, ok := op.this.(*opcode_command_ty)
+	assert(ok, "op.this.(*opcode_command_ty)")
+	var result []string
+	if this.has_flags {
+		result = append(result, "flags")
+	}
+	if this.data != nil {
+		result = append(result, fmt.Sprintf("data=%q", this.data.str))
+	}
+	result = append(result, opcode_disassemble_position(&this.pos))
+	return strings.TrimSpace(strings.Join(result, " "))
+}
+
+var opcode_command_method = opcode_method_ty{
+	name:        "command",
+	destructor:  opcode_command_destructor,
+	execute:     opcode_command_execute,
+	disassemble: opcode_command_disassemble,
+}
+
+/*
+ * NAME
+ *      opcode_command_new
+ *
+ * SYNOPSIS
+ *      opcode_ty *opcode_command_new(int has_flags, string_ty *data,
+ *              expr_position_ty *pp);
+ *
+ * DESCRIPTION
+ *      The opcode_command_new function is used to allocate a new
+ *      instance of a command opcode.  The data, if any, is copied.
+ */
+
+func opcode_command_new(has_flags bool, data *string_ty, pp *expr_position_ty) *opcode_ty {
+	this := &opcode_command_ty{has_flags: has_flags}
+	if data != nil {
+		this.data = str_copy(data)
+	}
+	expr_position_copy_constructor(&this.pos, pp)
+	return opcode_new(&opcode_command_method, this)
+}
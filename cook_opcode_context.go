@@ -42,19 +42,465 @@ import "fmt"
 func opcode_context_string_list_pop(ocp *opcode_context_ty) *string_list_ty {
 	trace(fmt.Sprintf("opcode_context_string_list_pop(ocp = %p)\n{\n", ocp))
 	assert(ocp != nil, "ocp != nil")
-	assert(ocp.value_stack_length > 0, "ocp.value_stack_length > 0")
-	ocp.value_stack_length--
-	slp := ocp.value_stack[ocp.value_stack_length]
+	assert(len(ocp.value_stack) > 0, "len(ocp.value_stack) > 0")
+	slp := ocp.value_stack[len(ocp.value_stack)-1]
+	ocp.value_stack = ocp.value_stack[:len(ocp.value_stack)-1]
 	trace(fmt.Sprintf("return %p;\n", slp))
 	trace("}\n")
 	return slp
 }
 
+/*
+ * NAME
+ *      opcode_context_string_list_peek
+ *
+ * SYNOPSIS
+ *      string_list_ty *opcode_context_string_list_peek(opcode_context_ty *);
+ *
+ * DESCRIPTION
+ *      The opcode_context_string_list_peek function is used to obtain
+ *      the top-most string list from the value stack, without removing
+ *      it from the stack.
+ */
+
+func opcode_context_string_list_peek(ocp *opcode_context_ty) *string_list_ty {
+	assert(ocp != nil, "ocp != nil")
+	assert(len(ocp.value_stack) > 0, "len(ocp.value_stack) > 0")
+	return ocp.value_stack[len(ocp.value_stack)-1]
+}
+
+/*
+ * NAME
+ *      opcode_context_string_push
+ *
+ * SYNOPSIS
+ *      void opcode_context_string_push(opcode_context_ty *);
+ *
+ * DESCRIPTION
+ *      The opcode_context_string_push function is used to push a new,
+ *      empty, string list onto the value stack.  Subsequent values are
+ *      appended to it.
+ */
+
+func opcode_context_string_push(ocp *opcode_context_ty) {
+	trace(fmt.Sprintf("opcode_context_string_push(ocp = %p)\n{\n", ocp))
+	assert(ocp != nil, "ocp != nil")
+	slp := &string_list_ty{}
+	string_list_constructor(slp)
+	ocp.value_stack = append(ocp.value_stack, slp)
+	trace("}\n")
+}
+
+/*
+ * NAME
+ *      opcode_context_string_push_list
+ *
+ * SYNOPSIS
+ *      void opcode_context_string_push_list(opcode_context_ty *,
+ *              string_list_ty *);
+ *
+ * DESCRIPTION
+ *      The opcode_context_string_push_list function is used to append
+ *      the given strings to the top-most string list on the value
+ *      stack.  This is how results are returned.  The strings are
+ *      copied.
+ */
+
 func opcode_context_string_push_list(ocp *opcode_context_ty, i *string_list_ty) {
 	trace(fmt.Sprintf("opcode_context_string_push_list(ocp = %p)\n{\n", ocp))
 	assert(ocp != nil, "ocp != nil")
-	assert(ocp.value_stack_length > 0, "ocp.value_stack_length > 0")
-	slp := ocp.value_stack[ocp.value_stack_length-1]
+	assert(len(ocp.value_stack) > 0, "len(ocp.value_stack) > 0")
+	slp := ocp.value_stack[len(ocp.value_stack)-1]
 	string_list_append_list(slp, i)
 	trace("}\n")
 }
+
+/*
+ * NAME
+ *      opcode_context_string_push_string
+ *
+ * SYNOPSIS
+ *      void opcode_context_string_push_string(opcode_context_ty *,
+ *              string_ty *);
+ *
+ * DESCRIPTION
+ *      The opcode_context_string_push_string function is used to append
+ *      a single string to the top-most string list on the value stack.
+ *      The string is copied.
+ */
+
+func opcode_context_string_push_string(ocp *opcode_context_ty, s *string_ty) {
+	assert(ocp != nil, "ocp != nil")
+	assert(len(ocp.value_stack) > 0, "len(ocp.value_stack) > 0")
+	string_list_append(ocp.value_stack[len(ocp.value_stack)-1], s)
+}
+
+/*
+ * NAME
+ *      opcode_context_goto
+ *
+ * SYNOPSIS
+ *      void opcode_context_goto(opcode_context_ty *, size_t pc);
+ *
+ * DESCRIPTION
+ *      The opcode_context_goto function is used to set the program
+ *      counter of the current frame.  It is used by the jump opcodes.
+ */
+
+func opcode_context_goto(ocp *opcode_context_ty, pc size_t) {
+	assert(len(ocp.call_stack) > 0, "len(ocp.call_stack) > 0")
+	fp := &ocp.call_stack[len(ocp.call_stack)-1]
+	assert(pc >= 0 && pc <= size_t(len(fp.olp.list)), "pc in range")
+	fp.pc = pc
+}
+
+/*
+ * NAME
+ *      opcode_context_call
+ *
+ * SYNOPSIS
+ *      void opcode_context_call(opcode_context_ty *, opcode_list_ty *,
+ *              symtab_ty *);
+ *
+ * DESCRIPTION
+ *      The opcode_context_call function is used to push a new frame on
+ *      the call stack, to execute the given opcode list with the given
+ *      local symbol table (which may be NULL).  The frame will be
+ *      popped by its return opcode.
+ */
+
+func opcode_context_call(ocp *opcode_context_ty, olp *opcode_list_ty, stp *symtab_ty) {
+	trace(fmt.Sprintf("opcode_context_call(ocp = %p, olp = %p)\n{\n", ocp, olp))
+	ocp.call_stack = append(ocp.call_stack, opcode_frame_ty{
+		olp:        opcode_list_copy(olp),
+		sp:         stp,
+		value_base: size_t(len(ocp.value_stack)),
+	})
+	trace("}\n")
+}
+
+/*
+ * NAME
+ *      opcode_context_return
+ *
+ * SYNOPSIS
+ *      void opcode_context_return(opcode_context_ty *);
+ *
+ * DESCRIPTION
+ *      The opcode_context_return function is used to pop the current
+ *      frame from the call stack.  The top-most string list is the
+ *      result; anything else the frame left on the value stack is
+ *      discarded.  The result is appended to the caller's top-most
+ *      string list or, if this was the outermost frame, left on the
+ *      value stack for the creator of the context to pop.
+ */
+
+func opcode_context_return(ocp *opcode_context_ty) {
+	trace(fmt.Sprintf("opcode_context_return(ocp = %p)\n{\n", ocp))
+	assert(len(ocp.call_stack) > 0, "len(ocp.call_stack) > 0")
+	result := opcode_context_string_list_pop(ocp)
+	fp := &ocp.call_stack[len(ocp.call_stack)-1]
+	for size_t(len(ocp.value_stack)) > fp.value_base {
+		string_list_delete(opcode_context_string_list_pop(ocp))
+	}
+	opcode_list_delete(fp.olp)
+	if fp.sp != nil {
+		symtab_free(fp.sp)
+	}
+	ocp.call_stack = ocp.call_stack[:len(ocp.call_stack)-1]
+
+	if len(ocp.call_stack) == 0 {
+		ocp.value_stack = append(ocp.value_stack, result)
+	} else {
+		opcode_context_string_push_list(ocp, result)
+		string_list_delete(result)
+	}
+	trace("}\n")
+}
+
+/*
+ * NAME
+ *      opcode_context_id_search
+ *
+ * SYNOPSIS
+ *      id_ty *opcode_context_id_search(opcode_context_ty *, string_ty *);
+ *
+ * DESCRIPTION
+ *      The opcode_context_id_search function is used to look up a
+ *      variable or function.  The local variables of the current
+ *      frame are searched first, then the variables of the thread
+ *      (such as "target" in a recipe body), then the global variables.
+ *
+ * RETURNS
+ *      id_ty *; NULL if the name is not defined.
+ */
+
+func opcode_context_id_search(ocp *opcode_context_ty, name *string_ty) *id_ty {
+	if n := len(ocp.call_stack); n > 0 && ocp.call_stack[n-1].sp != nil {
+		if idp, ok := symtab_query(ocp.call_stack[n-1].sp, name).(*id_ty); ok {
+			return idp
+		}
+	}
+	if ocp.thread_stp != nil {
+		if idp, ok := symtab_query(ocp.thread_stp, name).(*id_ty); ok {
+			return idp
+		}
+	}
+	if idp, ok := symtab_query(id_global_stp(), name).(*id_ty); ok {
+		return idp
+	}
+	return nil
+}
+
+/*
+ * NAME
+ *      opcode_context_id_assign
+ *
+ * SYNOPSIS
+ *      void opcode_context_id_assign(opcode_context_ty *, string_ty *name,
+ *              id_ty *);
+ *
+ * DESCRIPTION
+ *      The opcode_context_id_assign function is used to assign a value
+ *      to a variable or function name.  If the name is already defined
+ *      in the local variables of the current frame, or the variables of
+ *      the thread, it is replaced there; otherwise the global variable
+ *      is assigned.  The symbol table takes over the id.
+ */
+
+func opcode_context_id_assign(ocp *opcode_context_ty, name *string_ty, idp *id_ty) {
+	if n := len(ocp.call_stack); n > 0 && ocp.call_stack[n-1].sp != nil {
+		if symtab_query(ocp.call_stack[n-1].sp, name) != nil {
+			symtab_assign(ocp.call_stack[n-1].sp, name, idp)
+			return
+		}
+	}
+	if ocp.thread_stp != nil && symtab_query(ocp.thread_stp, name) != nil {
+		symtab_assign(ocp.thread_stp, name, idp)
+		return
+	}
+	symtab_assign(id_global_stp(), name, idp)
+}
+
+/*
+ * NAME
+ *      opcode_context_match_push
+ *
+ * SYNOPSIS
+ *      void opcode_context_match_push(opcode_context_ty *, match_ty *);
+ *
+ * DESCRIPTION
+ *      The opcode_context_match_push function is used to push a match
+ *      onto the context's match stack.  The fields of the top-most
+ *      match (and those it encloses) are used to reconstruct patterns,
+ *      such as the ingredients of an implicit recipe.
+ */
+
+func opcode_context_match_push(ocp *opcode_context_ty, mp *match_ty) {
+	match_stack_push(ocp.msp, mp)
+}
+
+/*
+ * NAME
+ *      opcode_context_match_pop
+ *
+ * SYNOPSIS
+ *      match_ty *opcode_context_match_pop(opcode_context_ty *);
+ *
+ * DESCRIPTION
+ *      The opcode_context_match_pop function is used to pop the
+ *      top-most match from the context's match stack.
+ *
+ * RETURNS
+ *      match_ty *; the match popped, it is not deleted.
+ */
+
+func opcode_context_match_pop(ocp *opcode_context_ty) *match_ty {
+	return match_stack_pop(ocp.msp)
+}
+
+/*
+ * NAME
+ *      opcode_context_match_top
+ *
+ * SYNOPSIS
+ *      match_ty *opcode_context_match_top(opcode_context_ty *);
+ *
+ * DESCRIPTION
+ *      The opcode_context_match_top function is used to obtain the
+ *      top-most match of the context's match stack.
+ *
+ * RETURNS
+ *      match_ty *; NULL if there is none.
+ */
+
+func opcode_context_match_top(ocp *opcode_context_ty) *match_ty {
+	return match_stack_top(ocp.msp)
+}
+
+/*
+ * Each context is given a unique thread id, for the use of the graph
+ * walker and the disassembler.
+ */
+var opcode_context_thread_id long
+
+/*
+ * NAME
+ *      opcode_context_new
+ *
+ * SYNOPSIS
+ *      opcode_context_ty *opcode_context_new(opcode_list_ty *,
+ *              symtab_ty *);
+ *
+ * DESCRIPTION
+ *      The opcode_context_new function is used to create a new
+ *      interpretation context, ready to execute the given opcode list.
+ *      The symbol table, which may be NULL, holds the thread's own
+ *      variables (such as "target" for a recipe body).  The context
+ *      does not take over the symbol table.
+ *
+ * RETURNS
+ *      opcode_context_ty *; use opcode_context_delete when you are done
+ *      with it.
+ */
+
+func opcode_context_new(olp *opcode_list_ty, stp *symtab_ty) *opcode_context_ty {
+	trace(fmt.Sprintf("opcode_context_new(olp = %p)\n{\n", olp))
+	opcode_context_thread_id++
+	ocp := &opcode_context_ty{
+		thread_id:  opcode_context_thread_id,
+		thread_stp: stp,
+		msp:        match_stack_new(),
+	}
+	opcode_context_call(ocp, olp, nil)
+	trace(fmt.Sprintf("return %p;\n", ocp))
+	trace("}\n")
+	return ocp
+}
+
+/*
+ * NAME
+ *      opcode_context_delete
+ *
+ * SYNOPSIS
+ *      void opcode_context_delete(opcode_context_ty *);
+ *
+ * DESCRIPTION
+ *      The opcode_context_delete function is used to release an
+ *      interpretation context.  Any frames and values left behind (by
+ *      an error, for example) are released too.  It is an error to
+ *      delete a context which is waiting for a child process.
+ */
+
+func opcode_context_delete(ocp *opcode_context_ty) {
+	trace(fmt.Sprintf("opcode_context_delete(ocp = %p)\n{\n", ocp))
+	assert(ocp.pid == 0, "ocp.pid == 0")
+	for len(ocp.call_stack) > 0 {
+		fp := &ocp.call_stack[len(ocp.call_stack)-1]
+		opcode_list_delete(fp.olp)
+		if fp.sp != nil {
+			symtab_free(fp.sp)
+		}
+		ocp.call_stack = ocp.call_stack[:len(ocp.call_stack)-1]
+	}
+	for len(ocp.value_stack) > 0 {
+		string_list_delete(opcode_context_string_list_pop(ocp))
+	}
+	match_stack_delete(ocp.msp)
+	if ocp.host_binding != nil {
+		str_free(ocp.host_binding)
+		ocp.host_binding = nil
+	}
+	ocp.thread_stp = nil
+	trace("}\n")
+}
+
+/*
+ * NAME
+ *      opcode_context_execute
+ *
+ * SYNOPSIS
+ *      opcode_status_ty opcode_context_execute(opcode_context_ty *);
+ *
+ * DESCRIPTION
+ *      The opcode_context_execute function is used to execute the
+ *      opcodes of the context, one after the other, until the outermost
+ *      frame returns.
+ *
+ *      If an opcode needs to wait for a child process, the program
+ *      counter is wound back so that the opcode will be executed again,
+ *      and opcode_status_wait is returned.  The caller is expected to
+ *      wait for the child (see os_wait), place its exit status in the
+ *      context, and call opcode_context_execute again to resume.  This
+ *      is how the graph walker interleaves many recipe bodies.
+ *
+ * RETURNS
+ *      opcode_status_ty;
+ *      opcode_status_success when the outermost frame has returned, its
+ *          result is the top-most string list of the value stack;
+ *      opcode_status_wait when waiting for a child process;
+ *      opcode_status_error when an opcode failed (already reported);
+ *      opcode_status_interrupted when the user interrupted cook.
+ */
+
+func opcode_context_execute(ocp *opcode_context_ty) opcode_status_ty {
+	trace(fmt.Sprintf("opcode_context_execute(ocp = %p)\n{\n", ocp))
+	status := opcode_status_success
+	for len(ocp.call_stack) > 0 {
+		if desist_requested() && ocp.pid == 0 {
+			status = opcode_status_interrupted
+			break
+		}
+		depth := len(ocp.call_stack) - 1
+		fp := &ocp.call_stack[depth]
+		assert(fp.pc < size_t(len(fp.olp.list)), "fp.pc < len(fp.olp.list)")
+		op := fp.olp.list[fp.pc]
+		fp.pc++
+		status = opcode_execute(op, ocp)
+		if status == opcode_status_success {
+			continue
+		}
+		if status == opcode_status_wait {
+			/*
+			 * The opcode is executed again when the child exits.
+			 * (The call stack may have been reallocated.)
+			 */
+			assert(len(ocp.call_stack) == depth+1, "len(ocp.call_stack) == depth+1")
+			ocp.call_stack[depth].pc--
+		}
+		break
+	}
+	trace(fmt.Sprintf("return %d;\n", status))
+	trace("}\n")
+	return status
+}
+
+/*
+ * NAME
+ *      opcode_context_execute_nowait
+ *
+ * SYNOPSIS
+ *      opcode_status_ty opcode_context_execute_nowait(opcode_context_ty *);
+ *
+ * DESCRIPTION
+ *      The opcode_context_execute_nowait function is used to execute
+ *      the opcodes of the context to completion, waiting for any child
+ *      processes as required.  It is used when there is nothing else to
+ *      be interleaved, such as when reading the cookbook.  Only the
+ *      context's own children are waited for; any others which exit
+ *      meanwhile (the graph walker's, for instance) are left for
+ *      os_wait to hand out.
+ *
+ * RETURNS
+ *      opcode_status_ty; never opcode_status_wait.
+ */
+
+func opcode_context_execute_nowait(ocp *opcode_context_ty) opcode_status_ty {
+	for {
+		status := opcode_context_execute(ocp)
+		if status != opcode_status_wait {
+			return status
+		}
+		ocp.exit_status = os_wait_pid(ocp.pid)
+	}
+}
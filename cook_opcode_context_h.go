@@ -20,21 +20,22 @@
 package main
 
 type opcode_frame_ty struct {
-	olp *opcode_list_ty
-	pc  size_t
-	sp  *symtab_ty
+	olp        *opcode_list_ty
+	pc         size_t
+	sp         *symtab_ty
+	value_base size_t /* value stack depth when the frame was entered */
 }
 
 type long = int64
 
 type opcode_context_ty struct {
-	call_stack_length   size_t
-	call_stack_maximum  size_t
-	call_stack          opcode_frame_ty
-	value_stack_length  size_t
-	value_stack_maximum size_t
-	value_stack         []*string_list_ty
-	thread_id           long
+	// call_stack_length   size_t
+	// call_stack_maximum  size_t
+	call_stack []opcode_frame_ty
+	// value_stack_length  size_t
+	// value_stack_maximum size_t
+	value_stack []*string_list_ty
+	thread_id   long
 
 	thread_stp *symtab_ty
 	msp        *match_stack_ty
@@ -45,6 +46,8 @@ type opcode_context_ty struct {
 	wlp         interface{} /* used by opcode_command */ // was void *
 	need_age    int         /* used by graph_run */
 
+	edge_type map[*string_ty]edge_type_ty /* used by graph_build */
+
 	/* for suspend/resume */
 	flags        interface{} // was void *
 	mp           *match_ty
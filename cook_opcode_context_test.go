@@ -0,0 +1,48 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import "testing"
+
+func TestOpcodeContextReturnFromLoop(t *testing.T) {
+	book := "function first = {\n" +
+		"    loop w = [arg] { return [w]; }\n" +
+		"    return none;\n" +
+		"}\n" +
+		"function pair = {\n" +
+		"    loop x = [@1] {\n" +
+		"        loop y = [@2] { if [in [y] b] then return [x][y]; }\n" +
+		"    }\n" +
+		"}\n" +
+		"function stop = {\n" +
+		"    loop { loopstop; }\n" +
+		"    return stopped;\n" +
+		"}\n" +
+		"r = x [first a b c] y [first] z [pair p b] [stop] [first d e];\n"
+	cookbook_test_read(t, book)
+	got := id_variable_query("r")
+	if got == nil {
+		t.Fatalf("r is not set")
+	}
+	want := "x a y none z pb stopped d"
+	if s := wl2str(got, 0, len(got.strings)-1, " ").str; s != want {
+		t.Errorf("r = %q, want %q", s, want)
+	}
+}
@@ -0,0 +1,99 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+/*
+ * The edge opcode pops the top-most string list from the value stack,
+ * and notes the given edge type for each of its words in the context.
+ * The words are appended to the (new) top-most string list.
+ */
+
+type opcode_edge_ty struct {
+	edge_type edge_type_ty
+}
+
+/*
+ * NAME
+ *      opcode_edge_execute
+ *
+ * SYNOPSIS
+ *      opcode_status_ty opcode_edge_execute(opcode_ty *,
+ *              opcode_context_ty *);
+ *
+ * DESCRIPTION
+ *      The opcode_edge_execute function is used to execute the given
+ *      opcode within the given interpretation context.
+ */
+
+func opcode_edge_execute(op *opcode_ty, ocp *opcode_context_ty) opcode_status_ty {
+	this, ok := op.this.(*opcode_edge_ty)
+	assert(ok, "op.this.(*opcode_edge_ty)")
+	slp := opcode_context_string_list_pop(ocp)
+	if ocp.edge_type == nil {
+		ocp.edge_type = make(map[*string_ty]edge_type_ty)
+	}
+	for _, s := range slp.strings {
+		ocp.edge_type[s] = this.edge_type
+	}
+	opcode_context_string_push_list(ocp, slp)
+	string_list_delete(slp)
+	return opcode_status_success
+}
+
+/*
+ * NAME
+ *      opcode_edge_disassemble
+ *
+ * SYNOPSIS
+ *      char *opcode_edge_disassemble(opcode_ty *);
+ *
+ * DESCRIPTION
+ *      The opcode_edge_disassemble function is used to disassemble the
+ *      opcode's edge type.
+ */
+
+func opcode_edge_disassemble(op *opcode_ty) string {
+	this, ok := op.this.(*opcode_edge_ty)
+	assert(ok, "op.this.(*opcode_edge_ty)")
+	return edge_type_name(this.edge_type)
+}
+
+var opcode_edge_method = opcode_method_ty{
+	name:        "edge",
+	execute:     opcode_edge_execute,
+	script:      opcode_edge_execute,
+	disassemble: opcode_edge_disassemble,
+}
+
+/*
+ * NAME
+ *      opcode_edge_new
+ *
+ * SYNOPSIS
+ *      opcode_ty *opcode_edge_new(edge_type_ty);
+ *
+ * DESCRIPTION
+ *      The opcode_edge_new function is used to allocate a new instance
+ *      of an edge opcode.
+ */
+
+func opcode_edge_new(et edge_type_ty) *opcode_ty {
+	return opcode_new(&opcode_edge_method, &opcode_edge_ty{edge_type: et})
+}
@@ -0,0 +1,110 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+/*
+ * The fail opcode pops the top-most string list from the value stack,
+ * reports it as an error message, and fails.
+ */
+
+type opcode_fail_ty struct {
+	pos expr_position_ty
+}
+
+func opcode_fail_destructor(op *opcode_ty) {
+	this, ok := op.this.(*opcode_fail_ty)
+	assert(ok, "op.this.(*opcode_fail_ty)")
+	expr_position_destructor(&this.pos)
+}
+
+/*
+ * NAME
+ *      opcode_fail_execute
+ *
+ * SYNOPSIS
+ *      opcode_status_ty opcode_fail_execute(opcode_ty *,
+ *              opcode_context_ty *);
+ *
+ * DESCRIPTION
+ *      The opcode_fail_execute function is used to execute the given
+ *      opcode within the given interpretation context.
+ */
+
+func opcode_fail_execute(op *opcode_ty, ocp *opcode_context_ty) opcode_status_ty {
+	this, ok := op.this.(*opcode_fail_ty)
+	assert(ok, "op.this.(*opcode_fail_ty)")
+	slp := opcode_context_string_list_pop(ocp)
+	if len(slp.strings) == 0 {
+		error_with_position(&this.pos, nil, i18n("fail statement"))
+	} else {
+		s := wl2str(slp, 0, len(slp.strings)-1, "")
+		scp := sub_context_new()
+		sub_var_set_string(scp, "Text", s)
+		error_with_position(&this.pos, scp, i18n("$text"))
+		sub_context_delete(scp)
+		str_free(s)
+	}
+	string_list_delete(slp)
+	return opcode_status_error
+}
+
+/*
+ * NAME
+ *      opcode_fail_disassemble
+ *
+ * SYNOPSIS
+ *      char *opcode_fail_disassemble(opcode_ty *);
+ *
+ * DESCRIPTION
+ *      The opcode_fail_disassemble function is used to disassemble
+ *      the opcode's position.
+ */
+
+func opcode_fail_disassemble(op *opcode_ty) string {
+	this, ok := op.this.(*opcode_fail_ty)
+	assert(ok, "op.this.(*opcode_fail_ty)")
+	return opcode_disassemble_position(&this.pos)
+}
+
+var opcode_fail_method = opcode_method_ty{
+	name:        "fail",
+	destructor:  opcode_fail_destructor,
+	execute:     opcode_fail_execute,
+	script:      opcode_fail_execute,
+	disassemble: opcode_fail_disassemble,
+}
+
+/*
+ * NAME
+ *      opcode_fail_new
+ *
+ * SYNOPSIS
+ *      opcode_ty *opcode_fail_new(expr_position_ty *);
+ *
+ * DESCRIPTION
+ *      The opcode_fail_new function is used to allocate a new instance
+ *      of a fail opcode.
+ */
+
+func opcode_fail_new(pp *expr_position_ty) *opcode_ty {
+	this := &opcode_fail_ty{}
+	expr_position_copy_constructor(&this.pos, pp)
+	return opcode_new(&opcode_fail_method, this)
+}
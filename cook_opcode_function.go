@@ -0,0 +1,171 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+/*
+ * The function opcode calls a function, or references a variable.
+ * The top-most string list of the value stack holds the name of the
+ * function (the first word) and its arguments.  The list is popped,
+ * and the results are appended to the (new) top-most string list.
+ */
+
+type opcode_function_ty struct {
+	pos expr_position_ty
+}
+
+func opcode_function_destructor(op *opcode_ty) {
+	this, ok := op.this.(*opcode_function_ty)
+	assert(ok, "op.this.(*opcode_function_ty)")
+	expr_position_destructor(&this.pos)
+}
+
+/*
+ * NAME
+ *      opcode_function_find
+ *
+ * SYNOPSIS
+ *      id_ty *opcode_function_find(opcode_function_ty *,
+ *              opcode_context_ty *);
+ *
+ * DESCRIPTION
+ *      The opcode_function_find function is used to find the function
+ *      or variable named by the first word of the top-most string list
+ *      of the value stack.  On error, the argument list is discarded.
+ *
+ * RETURNS
+ *      id_ty *; NULL on error (already reported).
+ */
+
+func opcode_function_find(this *opcode_function_ty, ocp *opcode_context_ty) *id_ty {
+	args := opcode_context_string_list_peek(ocp)
+	if len(args.strings) == 0 {
+		error_with_position(&this.pos, nil, i18n("empty function name"))
+		string_list_delete(opcode_context_string_list_pop(ocp))
+		return nil
+	}
+	idp := opcode_context_id_search(ocp, args.strings[0])
+	if idp == nil {
+		scp := sub_context_new()
+		sub_var_set_string(scp, "Name", args.strings[0])
+		error_with_position(&this.pos, scp, i18n("the name \"$name\" is undefined"))
+		sub_context_delete(scp)
+		string_list_delete(opcode_context_string_list_pop(ocp))
+		return nil
+	}
+	return idp
+}
+
+/*
+ * NAME
+ *      opcode_function_execute
+ *
+ * SYNOPSIS
+ *      opcode_status_ty opcode_function_execute(opcode_ty *,
+ *              opcode_context_ty *);
+ *
+ * DESCRIPTION
+ *      The opcode_function_execute function is used to execute the
+ *      given opcode within the given interpretation context.
+ */
+
+func opcode_function_execute(op *opcode_ty, ocp *opcode_context_ty) opcode_status_ty {
+	this, ok := op.this.(*opcode_function_ty)
+	assert(ok, "op.this.(*opcode_function_ty)")
+	idp := opcode_function_find(this, ocp)
+	if idp == nil {
+		return opcode_status_error
+	}
+	if idp.method.interprets(idp, ocp, &this.pos) < 0 {
+		return opcode_status_error
+	}
+	return opcode_status_success
+}
+
+/*
+ * NAME
+ *      opcode_function_script
+ *
+ * SYNOPSIS
+ *      opcode_status_ty opcode_function_script(opcode_ty *,
+ *              opcode_context_ty *);
+ *
+ * DESCRIPTION
+ *      The opcode_function_script function is used to execute the
+ *      given opcode within the given interpretation context, for the
+ *      purposes of writing a shell script.
+ */
+
+func opcode_function_script(op *opcode_ty, ocp *opcode_context_ty) opcode_status_ty {
+	this, ok := op.this.(*opcode_function_ty)
+	assert(ok, "op.this.(*opcode_function_ty)")
+	idp := opcode_function_find(this, ocp)
+	if idp == nil {
+		return opcode_status_error
+	}
+	if idp.method.script(idp, ocp, &this.pos) < 0 {
+		return opcode_status_error
+	}
+	return opcode_status_success
+}
+
+/*
+ * NAME
+ *      opcode_function_disassemble
+ *
+ * SYNOPSIS
+ *      char *opcode_function_disassemble(opcode_ty *);
+ *
+ * DESCRIPTION
+ *      The opcode_function_disassemble function is used to disassemble
+ *      the opcode's position.
+ */
+
+func opcode_function_disassemble(op *opcode_ty) string {
+	this, ok := op.this.(*opcode_function_ty)
+	assert(ok, "op.this.(*opcode_function_ty)")
+	return opcode_disassemble_position(&this.pos)
+}
+
+var opcode_function_method = opcode_method_ty{
+	name:        "function",
+	destructor:  opcode_function_destructor,
+	execute:     opcode_function_execute,
+	script:      opcode_function_script,
+	disassemble: opcode_function_disassemble,
+}
+
+/*
+ * NAME
+ *      opcode_function_new
+ *
+ * SYNOPSIS
+ *      opcode_ty *opcode_function_new(expr_position_ty *);
+ *
+ * DESCRIPTION
+ *      The opcode_function_new function is used to allocate a new
+ *      instance of a function opcode.  The position is used for error
+ *      messages.
+ */
+
+func opcode_function_new(pp *expr_position_ty) *opcode_ty {
+	this := &opcode_function_ty{}
+	expr_position_copy_constructor(&this.pos, pp)
+	return opcode_new(&opcode_function_method, this)
+}
@@ -0,0 +1,134 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+/*
+ * The function_define opcode pops the top-most string list from the
+ * value stack, which is the name of a function, and defines that
+ * function to have the given body.
+ */
+
+type opcode_function_define_ty struct {
+	body *opcode_list_ty
+	pos  expr_position_ty
+}
+
+func opcode_function_define_destructor(op *opcode_ty) {
+	this, ok := op.this.(*opcode_function_define_ty)
+	assert(ok, "op.this.(*opcode_function_define_ty)")
+	opcode_list_delete(this.body)
+	expr_position_destructor(&this.pos)
+}
+
+/*
+ * NAME
+ *      opcode_function_define_execute
+ *
+ * SYNOPSIS
+ *      opcode_status_ty opcode_function_define_execute(opcode_ty *,
+ *              opcode_context_ty *);
+ *
+ * DESCRIPTION
+ *      The opcode_function_define_execute function is used to execute
+ *      the given opcode within the given interpretation context.
+ */
+
+func opcode_function_define_execute(op *opcode_ty, ocp *opcode_context_ty) opcode_status_ty {
+	this, ok := op.this.(*opcode_function_define_ty)
+	assert(ok, "op.this.(*opcode_function_define_ty)")
+	name := opcode_context_string_list_pop(ocp)
+	defer string_list_delete(name)
+	if len(name.strings) != 1 {
+		scp := sub_context_new()
+		sub_var_set_long(scp, "Number", long(len(name.strings)))
+		error_with_position(&this.pos, scp, i18n("function definition requires exactly one function name (was given $number)"))
+		sub_context_delete(scp)
+		return opcode_status_error
+	}
+
+	symtab_assign(id_global_stp(), name.strings[0], id_function_new(this.body))
+	return opcode_status_success
+}
+
+/*
+ * NAME
+ *      opcode_function_define_disassemble
+ *
+ * SYNOPSIS
+ *      char *opcode_function_define_disassemble(opcode_ty *);
+ *
+ * DESCRIPTION
+ *      The opcode_function_define_disassemble function is used to disassemble
+ *      the opcode's position.  The body is printed nested, see
+ *      opcode_function_define_disassemble_nested.
+ */
+
+func opcode_function_define_disassemble(op *opcode_ty) string {
+	this, ok := op.this.(*opcode_function_define_ty)
+	assert(ok, "op.this.(*opcode_function_define_ty)")
+	return opcode_disassemble_position(&this.pos)
+}
+
+/*
+ * NAME
+ *      opcode_function_define_disassemble_nested
+ *
+ * SYNOPSIS
+ *      void opcode_function_define_disassemble_nested(opcode_ty *);
+ *
+ * DESCRIPTION
+ *      The opcode_function_define_disassemble_nested function is used
+ *      to print the function body, nested below the opcode.
+ */
+
+func opcode_function_define_disassemble_nested(op *opcode_ty) {
+	this, ok := op.this.(*opcode_function_define_ty)
+	assert(ok, "op.this.(*opcode_function_define_ty)")
+	opcode_list_disassemble_nested("body", this.body)
+}
+
+var opcode_function_define_method = opcode_method_ty{
+	name:               "function_define",
+	destructor:         opcode_function_define_destructor,
+	execute:            opcode_function_define_execute,
+	script:             opcode_function_define_execute,
+	disassemble:        opcode_function_define_disassemble,
+	disassemble_nested: opcode_function_define_disassemble_nested,
+}
+
+/*
+ * NAME
+ *      opcode_function_define_new
+ *
+ * SYNOPSIS
+ *      opcode_ty *opcode_function_define_new(opcode_list_ty *body,
+ *              expr_position_ty *pp);
+ *
+ * DESCRIPTION
+ *      The opcode_function_define_new function is used to allocate a
+ *      new instance of a function_define opcode.  The opcode takes over
+ *      the body.
+ */
+
+func opcode_function_define_new(body *opcode_list_ty, pp *expr_position_ty) *opcode_ty {
+	this := &opcode_function_define_ty{body: body}
+	expr_position_copy_constructor(&this.pos, pp)
+	return opcode_new(&opcode_function_define_method, this)
+}
@@ -0,0 +1,114 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import "fmt"
+
+/*
+ * The goto opcode unconditionally transfers control to another opcode
+ * in the same opcode list.
+ */
+
+type opcode_goto_ty struct {
+	dest size_t /* set by opcode_label_refer */
+}
+
+/*
+ * NAME
+ *      opcode_goto_execute
+ *
+ * SYNOPSIS
+ *      opcode_status_ty opcode_goto_execute(opcode_ty *,
+ *              opcode_context_ty *);
+ *
+ * DESCRIPTION
+ *      The opcode_goto_execute function is used to execute the given
+ *      opcode within the given interpretation context.
+ */
+
+func opcode_goto_execute(op *opcode_ty, ocp *opcode_context_ty) opcode_status_ty {
+	this, ok := op.this.(*opcode_goto_ty)
+	assert(ok, "op.this.(*opcode_goto_ty)")
+	opcode_context_goto(ocp, this.dest)
+	return opcode_status_success
+}
+
+/*
+ * NAME
+ *      opcode_goto_disassemble
+ *
+ * SYNOPSIS
+ *      char *opcode_goto_disassemble(opcode_ty *);
+ *
+ * DESCRIPTION
+ *      The opcode_goto_disassemble function is used to disassemble
+ *      the opcode's destination.
+ */
+
+func opcode_goto_disassemble(op *opcode_ty) string {
+	this, ok := op.this.(*opcode_goto_ty)
+	assert(ok, "op.this.(*opcode_goto_ty)")
+	return fmt.Sprintf("L%d", this.dest)
+}
+
+/*
+ * NAME
+ *      opcode_goto_targets
+ *
+ * SYNOPSIS
+ *      size_t *opcode_goto_targets(opcode_ty *);
+ *
+ * DESCRIPTION
+ *      The opcode_goto_targets function is used to obtain the opcode's
+ *      destination, so that the disassembler can label it.
+ */
+
+func opcode_goto_targets(op *opcode_ty) []size_t {
+	this, ok := op.this.(*opcode_goto_ty)
+	assert(ok, "op.this.(*opcode_goto_ty)")
+	return []size_t{this.dest}
+}
+
+var opcode_goto_method = opcode_method_ty{
+	name:        "goto",
+	execute:     opcode_goto_execute,
+	script:      opcode_goto_execute,
+	disassemble: opcode_goto_disassemble,
+	targets:     opcode_goto_targets,
+}
+
+/*
+ * NAME
+ *      opcode_goto_new
+ *
+ * SYNOPSIS
+ *      opcode_ty *opcode_goto_new(opcode_label_ty *);
+ *
+ * DESCRIPTION
+ *      The opcode_goto_new function is used to allocate a new instance
+ *      of a goto opcode.  The destination is the given label, which
+ *      need not be defined yet.
+ */
+
+func opcode_goto_new(lp *opcode_label_ty) *opcode_ty {
+	this := &opcode_goto_ty{}
+	opcode_label_refer(lp, &this.dest)
+	return opcode_new(&opcode_goto_method, this)
+}
@@ -21,4 +21,5 @@ package main
 
 type opcode_ty struct {
 	method *opcode_method_ty
+	this   interface{} /* the derived instance, see opcode_new */
 }
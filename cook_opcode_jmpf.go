@@ -0,0 +1,119 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import "fmt"
+
+/*
+ * The jmpf opcode pops the top-most string list from the value stack,
+ * and transfers control to another opcode in the same opcode list if
+ * it is false.  (See string_list_bool for the definition of false.)
+ */
+
+type opcode_jmpf_ty struct {
+	dest size_t /* set by opcode_label_refer */
+}
+
+/*
+ * NAME
+ *      opcode_jmpf_execute
+ *
+ * SYNOPSIS
+ *      opcode_status_ty opcode_jmpf_execute(opcode_ty *,
+ *              opcode_context_ty *);
+ *
+ * DESCRIPTION
+ *      The opcode_jmpf_execute function is used to execute the given
+ *      opcode within the given interpretation context.
+ */
+
+func opcode_jmpf_execute(op *opcode_ty, ocp *opcode_context_ty) opcode_status_ty {
+	this, ok := op.this.(*opcode_jmpf_ty)
+	assert(ok, "op.this.(*opcode_jmpf_ty)")
+	slp := opcode_context_string_list_pop(ocp)
+	if !string_list_bool(slp) {
+		opcode_context_goto(ocp, this.dest)
+	}
+	string_list_delete(slp)
+	return opcode_status_success
+}
+
+/*
+ * NAME
+ *      opcode_jmpf_disassemble
+ *
+ * SYNOPSIS
+ *      char *opcode_jmpf_disassemble(opcode_ty *);
+ *
+ * DESCRIPTION
+ *      The opcode_jmpf_disassemble function is used to disassemble
+ *      the opcode's destination.
+ */
+
+func opcode_jmpf_disassemble(op *opcode_ty) string {
+	this, ok := op.this.(*opcode_jmpf_ty)
+	assert(ok, "op.this.(*opcode_jmpf_ty)")
+	return fmt.Sprintf("L%d", this.dest)
+}
+
+/*
+ * NAME
+ *      opcode_jmpf_targets
+ *
+ * SYNOPSIS
+ *      size_t *opcode_jmpf_targets(opcode_ty *);
+ *
+ * DESCRIPTION
+ *      The opcode_jmpf_targets function is used to obtain the opcode's
+ *      destination, so that the disassembler can label it.
+ */
+
+func opcode_jmpf_targets(op *opcode_ty) []size_t {
+	this, ok := op.this.(*opcode_jmpf_ty)
+	assert(ok, "op.this.(*opcode_jmpf_ty)")
+	return []size_t{this.dest}
+}
+
+var opcode_jmpf_method = opcode_method_ty{
+	name:        "jmpf",
+	execute:     opcode_jmpf_execute,
+	script:      opcode_jmpf_execute,
+	disassemble: opcode_jmpf_disassemble,
+	targets:     opcode_jmpf_targets,
+}
+
+/*
+ * NAME
+ *      opcode_jmpf_new
+ *
+ * SYNOPSIS
+ *      opcode_ty *opcode_jmpf_new(opcode_label_ty *);
+ *
+ * DESCRIPTION
+ *      The opcode_jmpf_new function is used to allocate a new instance
+ *      of a jmpf opcode.  The destination is the given label, which
+ *      need not be defined yet.
+ */
+
+func opcode_jmpf_new(lp *opcode_label_ty) *opcode_ty {
+	this := &opcode_jmpf_ty{}
+	opcode_label_refer(lp, &this.dest)
+	return opcode_new(&opcode_jmpf_method, this)
+}
@@ -0,0 +1,104 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+/*
+ * NAME
+ *      opcode_label_new
+ *
+ * SYNOPSIS
+ *      opcode_label_ty *opcode_label_new(void);
+ *
+ * DESCRIPTION
+ *      The opcode_label_new function is used to create a new, as yet
+ *      undefined, label.  Jumps may refer to it before it is defined;
+ *      they are back-patched when it is.
+ *
+ * RETURNS
+ *      opcode_label_ty *; use opcode_label_delete when you are done
+ *      with it.
+ */
+
+func opcode_label_new() *opcode_label_ty {
+	return &opcode_label_ty{pc: -1}
+}
+
+/*
+ * NAME
+ *      opcode_label_delete
+ *
+ * SYNOPSIS
+ *      void opcode_label_delete(opcode_label_ty *);
+ *
+ * DESCRIPTION
+ *      The opcode_label_delete function is used to release a label.
+ *      It is an error for a label to be deleted with references still
+ *      pending; that means it was referred to but never defined.
+ */
+
+func opcode_label_delete(lp *opcode_label_ty) {
+	assert(lp != nil, "lp != nil")
+	assert(len(lp.pending) == 0, "len(lp.pending) == 0")
+	lp.pending = nil
+}
+
+/*
+ * NAME
+ *      opcode_label_define
+ *
+ * SYNOPSIS
+ *      void opcode_label_define(opcode_label_ty *, opcode_list_ty *);
+ *
+ * DESCRIPTION
+ *      The opcode_label_define function is used to define the label
+ *      as the position of the next opcode to be appended to the given
+ *      opcode list.  All pending references are patched.
+ */
+
+func opcode_label_define(lp *opcode_label_ty, olp *opcode_list_ty) {
+	assert(lp.pc < 0, "lp.pc < 0")
+	lp.pc = size_t(len(olp.list))
+	for _, dest := range lp.pending {
+		*dest = lp.pc
+	}
+	lp.pending = nil
+}
+
+/*
+ * NAME
+ *      opcode_label_refer
+ *
+ * SYNOPSIS
+ *      void opcode_label_refer(opcode_label_ty *, size_t *dest);
+ *
+ * DESCRIPTION
+ *      The opcode_label_refer function is used to make the given jump
+ *      destination refer to the label.  If the label is already
+ *      defined the destination is set immediately, otherwise it will
+ *      be set when the label is defined.
+ */
+
+func opcode_label_refer(lp *opcode_label_ty, dest *size_t) {
+	if lp.pc >= 0 {
+		*dest = lp.pc
+		return
+	}
+	lp.pending = append(lp.pending, dest)
+}
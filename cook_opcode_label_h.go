@@ -20,8 +20,8 @@
 package main
 
 type opcode_label_ty struct {
-	pc           size_t
-	npending     size_t
-	npending_max size_t
-	pending      **size_t
+	pc size_t /* -1 until the label is defined */
+	// npending     size_t
+	// npending_max size_t
+	pending []*size_t /* jump destinations waiting for the label */
 }
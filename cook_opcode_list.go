@@ -0,0 +1,237 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+/*
+ * NAME
+ *      opcode_list_new
+ *
+ * SYNOPSIS
+ *      opcode_list_ty *opcode_list_new(void);
+ *
+ * DESCRIPTION
+ *      The opcode_list_new function is used to create a new, empty,
+ *      opcode list.
+ *
+ * RETURNS
+ *      opcode_list_ty *; use opcode_list_delete when you are done with
+ *      it.
+ */
+
+func opcode_list_new() *opcode_list_ty {
+	trace("opcode_list_new()\n{\n")
+	olp := &opcode_list_ty{reference_count: 1}
+	trace(fmt.Sprintf("return %p;\n", olp))
+	trace("}\n")
+	return olp
+}
+
+/*
+ * NAME
+ *      opcode_list_copy
+ *
+ * SYNOPSIS
+ *      opcode_list_ty *opcode_list_copy(opcode_list_ty *);
+ *
+ * DESCRIPTION
+ *      The opcode_list_copy function is used to make a copy of an
+ *      opcode list.  Opcode lists are never modified once compiled, so
+ *      this is simply a reference count increment.
+ */
+
+func opcode_list_copy(olp *opcode_list_ty) *opcode_list_ty {
+	assert(olp.reference_count > 0, "olp.reference_count > 0")
+	olp.reference_count++
+	return olp
+}
+
+/*
+ * NAME
+ *      opcode_list_delete
+ *
+ * SYNOPSIS
+ *      void opcode_list_delete(opcode_list_ty *);
+ *
+ * DESCRIPTION
+ *      The opcode_list_delete function is used to release an opcode
+ *      list when it is finished with.  It is safe to pass NULL.
+ */
+
+func opcode_list_delete(olp *opcode_list_ty) {
+	if olp == nil {
+		return
+	}
+	assert(olp.reference_count > 0, "olp.reference_count > 0")
+	olp.reference_count--
+	if olp.reference_count > 0 {
+		return
+	}
+	for _, op := range olp.list {
+		opcode_delete(op)
+	}
+	olp.list = nil
+	if olp.return_label != nil {
+		opcode_label_delete(olp.return_label)
+		olp.return_label = nil
+	}
+}
+
+/*
+ * NAME
+ *      opcode_list_append
+ *
+ * SYNOPSIS
+ *      void opcode_list_append(opcode_list_ty *, opcode_ty *);
+ *
+ * DESCRIPTION
+ *      The opcode_list_append function is used to append an opcode to
+ *      the end of an opcode list.  The opcode list takes over
+ *      responsibility for the opcode.
+ */
+
+func opcode_list_append(olp *opcode_list_ty, op *opcode_ty) {
+	assert(olp != nil, "olp != nil")
+	assert(op != nil, "op != nil")
+	olp.list = append(olp.list, op)
+}
+
+/*
+ * NAME
+ *      opcode_list_text
+ *
+ * SYNOPSIS
+ *      string opcode_list_text(opcode_list_ty *);
+ *
+ * DESCRIPTION
+ *      The opcode_list_text function is used to obtain a textual form
+ *      of an opcode list, for noticing when a recipe body has changed.
+ *      It is the disassembly, one opcode per line, without positions,
+ *      so that editing elsewhere in the cookbook does not change it.
+ */
+
+func opcode_list_text(olp *opcode_list_ty) string {
+	save := opcode_disassemble_positions
+	opcode_disassemble_positions = false
+	defer func() { opcode_disassemble_positions = save }()
+	var sb strings.Builder
+	for _, op := range olp.list {
+		sb.WriteString(op.method.name)
+		if op.method.disassemble != nil {
+			if operands := op.method.disassemble(op); operands != "" {
+				sb.WriteString(" ")
+				sb.WriteString(operands)
+			}
+		}
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+/*
+ * The indent of the opcode list being disassembled; opcode lists
+ * contained by opcodes are disassembled nested.
+ */
+var opcode_list_disassemble_indent string
+
+/*
+ * NAME
+ *      opcode_list_disassemble
+ *
+ * SYNOPSIS
+ *      void opcode_list_disassemble(opcode_list_ty *);
+ *
+ * DESCRIPTION
+ *      The opcode_list_disassemble function is used to print a human
+ *      readable listing of an opcode list on the standard output.  Each
+ *      opcode is printed with its program counter, name and operands.
+ *      Jump destinations are printed as labels, "L" followed by the
+ *      program counter, and the destinations are marked.
+ */
+
+func opcode_list_disassemble(olp *opcode_list_ty) {
+	/*
+	 * find all of the jump destinations
+	 */
+	labels := make(map[size_t]bool)
+	for _, op := range olp.list {
+		if op.method.targets == nil {
+			continue
+		}
+		for _, dest := range op.method.targets(op) {
+			labels[dest] = true
+		}
+	}
+
+	indent := opcode_list_disassemble_indent
+	for pc, op := range olp.list {
+		if labels[size_t(pc)] {
+			fmt.Printf("%sL%d:\n", indent, pc)
+		}
+		operands := ""
+		if op.method.disassemble != nil {
+			operands = op.method.disassemble(op)
+		}
+		if operands == "" {
+			fmt.Printf("%s%6d  %s\n", indent, pc, op.method.name)
+		} else {
+			fmt.Printf("%s%6d  %-16s%s\n", indent, pc, op.method.name, operands)
+		}
+
+		/*
+		 * contained opcode lists (function bodies) are printed nested
+		 */
+		if op.method.disassemble_nested != nil {
+			op.method.disassemble_nested(op)
+		}
+	}
+	if labels[size_t(len(olp.list))] {
+		fmt.Printf("%sL%d:\n", indent, len(olp.list))
+	}
+}
+
+/*
+ * NAME
+ *      opcode_list_disassemble_nested
+ *
+ * SYNOPSIS
+ *      void opcode_list_disassemble_nested(char *caption,
+ *              opcode_list_ty *);
+ *
+ * DESCRIPTION
+ *      The opcode_list_disassemble_nested function is used to print the
+ *      opcode lists contained by opcodes (such as function bodies),
+ *      indented below the opcode.  It is safe to pass NULL.
+ */
+
+func opcode_list_disassemble_nested(caption string, olp *opcode_list_ty) {
+	if olp == nil {
+		return
+	}
+	old := opcode_list_disassemble_indent
+	fmt.Printf("%s        %s:\n", old, caption)
+	opcode_list_disassemble_indent = old + "            "
+	opcode_list_disassemble(olp)
+	opcode_list_disassemble_indent = old
+}
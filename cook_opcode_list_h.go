@@ -21,10 +21,10 @@ package main
 
 type opcode_list_ty struct {
 	reference_count long
-	length          size_t
-	maximum         size_t
-	list            **opcode_ty
-	break_label     *opcode_label_ty
-	continue_label  *opcode_label_ty
-	return_label    *opcode_label_ty
+	// length          size_t
+	// maximum         size_t
+	list           []*opcode_ty
+	break_label    *opcode_label_ty
+	continue_label *opcode_label_ty
+	return_label   *opcode_label_ty
 }
@@ -0,0 +1,69 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestOpcodeListDisassemble(t *testing.T) {
+	dir := t.TempDir()
+	book := "function f = { if [a] then return b; }\n" +
+		"loop { loopstop; }\n" +
+		"loop x = a b { }\n"
+	name := filepath.Join(dir, "Howto.cook")
+	if err := ioutil.WriteFile(name, []byte(book), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cookbook := parse(str_from_string(name))
+	olp := stmt_compile(cookbook)
+	stmt_delete(cookbook)
+	if olp == nil {
+		t.Fatalf("can't compile cookbook:\n%s", book)
+	}
+	defer opcode_list_delete(olp)
+	save := opcode_disassemble_positions
+	opcode_disassemble_positions = false
+	defer func() { opcode_disassemble_positions = save }()
+	got := capture_stdout(t, func() { opcode_list_disassemble(olp) })
+
+	/*
+	 * Every jump destination is labelled, at every nesting level,
+	 * and the function body is printed nested below its opcode.
+	 */
+	for _, want := range []string{
+		"     2  function_define\n        body:\n",
+		"                 4  jmpf            L8\n",
+		"                 7  goto            L9\n",
+		"            L8:\n                 8  push\n",
+		"            L9:\n                 9  return\n",
+		"\nL3:\n     3  goto            L5\n",
+		"\nL5:\n     5  push\n",
+		"\nL10:\n    10  loop_next       L12",
+		"\nL12:\n    12  pop\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("disassembly does not contain %q:\n%s", want, got)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import "fmt"
+
+/*
+ * The loop_next opcode drives the "loop name = values" statement.  The
+ * values are the top-most string list of the value stack, and the name
+ * is the next.  If there are no values left control is transferred to
+ * the end of the loop, otherwise the first value is removed and
+ * assigned to the named variable.
+ */
+
+type opcode_loop_next_ty struct {
+	pos  expr_position_ty
+	dest size_t /* set by opcode_label_refer */
+}
+
+func opcode_loop_next_destructor(op *opcode_ty) {
+	this, ok := op.this.(*opcode_loop_next_ty)
+	assert(ok, "op.this.(*opcode_loop_next_ty)")
+	expr_position_destructor(&this.pos)
+}
+
+/*
+ * NAME
+ *      opcode_loop_next_execute
+ *
+ * SYNOPSIS
+ *      opcode_status_ty opcode_loop_next_execute(opcode_ty *,
+ *              opcode_context_ty *);
+ *
+ * DESCRIPTION
+ *      The opcode_loop_next_execute function is used to execute the
+ *      given opcode within the given interpretation context.
+ */
+
+func opcode_loop_next_execute(op *opcode_ty, ocp *opcode_context_ty) opcode_status_ty {
+	this, ok := op.this.(*opcode_loop_next_ty)
+	assert(ok, "op.this.(*opcode_loop_next_ty)")
+	assert(len(ocp.value_stack) >= 2, "len(ocp.value_stack) >= 2")
+	values := ocp.value_stack[len(ocp.value_stack)-1]
+	name := ocp.value_stack[len(ocp.value_stack)-2]
+	if len(name.strings) != 1 {
+		scp := sub_context_new()
+		sub_var_set_long(scp, "Number", long(len(name.strings)))
+		error_with_position(&this.pos, scp, i18n("loop requires exactly one variable name (was given $number)"))
+		sub_context_delete(scp)
+		return opcode_status_error
+	}
+	if len(values.strings) == 0 {
+		opcode_context_goto(ocp, this.dest)
+		return opcode_status_success
+	}
+
+	var value string_list_ty
+	string_list_constructor(&value)
+	string_list_append(&value, values.strings[0])
+	string_list_remove_nth(values, 0)
+	opcode_context_id_assign(ocp, name.strings[0], id_variable_new(&value))
+	string_list_destructor(&value)
+	return opcode_status_success
+}
+
+/*
+ * NAME
+ *      opcode_loop_next_disassemble
+ *
+ * SYNOPSIS
+ *      char *opcode_loop_next_disassemble(opcode_ty *);
+ *
+ * DESCRIPTION
+ *      The opcode_loop_next_disassemble function is used to disassemble
+ *      the opcode's destination and position.
+ */
+
+func opcode_loop_next_disassemble(op *opcode_ty) string {
+	this, ok := op.this.(*opcode_loop_next_ty)
+	assert(ok, "op.this.(*opcode_loop_next_ty)")
+	return fmt.Sprintf("L%d %s", this.dest, opcode_disassemble_position(&this.pos))
+}
+
+/*
+ * NAME
+ *      opcode_loop_next_targets
+ *
+ * SYNOPSIS
+ *      size_t *opcode_loop_next_targets(opcode_ty *);
+ *
+ * DESCRIPTION
+ *      The opcode_loop_next_targets function is used to obtain the opcode's
+ *      destination, so that the disassembler can label it.
+ */
+
+func opcode_loop_next_targets(op *opcode_ty) []size_t {
+	this, ok := op.this.(*opcode_loop_next_ty)
+	assert(ok, "op.this.(*opcode_loop_next_ty)")
+	return []size_t{this.dest}
+}
+
+var opcode_loop_next_method = opcode_method_ty{
+	name:        "loop_next",
+	destructor:  opcode_loop_next_destructor,
+	execute:     opcode_loop_next_execute,
+	script:      opcode_loop_next_execute,
+	disassemble: opcode_loop_next_disassemble,
+	targets:     opcode_loop_next_targets,
+}
+
+/*
+ * NAME
+ *      opcode_loop_next_new
+ *
+ * SYNOPSIS
+ *      opcode_ty *opcode_loop_next_new(opcode_label_ty *,
+ *              expr_position_ty *);
+ *
+ * DESCRIPTION
+ *      The opcode_loop_next_new function is used to allocate a new
+ *      instance of a loop_next opcode.  The given label is the end of
+ *      the loop.
+ */
+
+func opcode_loop_next_new(lp *opcode_label_ty, pp *expr_position_ty) *opcode_ty {
+	this := &opcode_loop_next_ty{}
+	expr_position_copy_constructor(&this.pos, pp)
+	opcode_label_refer(lp, &this.dest)
+	return opcode_new(&opcode_loop_next_method, this)
+}
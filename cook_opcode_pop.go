@@ -0,0 +1,66 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+/*
+ * The pop opcode discards the top-most string list of the value stack.
+ */
+
+type opcode_pop_ty struct{}
+
+/*
+ * NAME
+ *      opcode_pop_execute
+ *
+ * SYNOPSIS
+ *      opcode_status_ty opcode_pop_execute(opcode_ty *,
+ *              opcode_context_ty *);
+ *
+ * DESCRIPTION
+ *      The opcode_pop_execute function is used to execute the given
+ *      opcode within the given interpretation context.
+ */
+
+func opcode_pop_execute(op *opcode_ty, ocp *opcode_context_ty) opcode_status_ty {
+	string_list_delete(opcode_context_string_list_pop(ocp))
+	return opcode_status_success
+}
+
+var opcode_pop_method = opcode_method_ty{
+	name:    "pop",
+	execute: opcode_pop_execute,
+	script:  opcode_pop_execute,
+}
+
+/*
+ * NAME
+ *      opcode_pop_new
+ *
+ * SYNOPSIS
+ *      opcode_ty *opcode_pop_new(void);
+ *
+ * DESCRIPTION
+ *      The opcode_pop_new function is used to allocate a new instance
+ *      of a pop opcode.
+ */
+
+func opcode_pop_new() *opcode_ty {
+	return opcode_new(&opcode_pop_method, &opcode_pop_ty{})
+}
@@ -20,10 +20,17 @@
 package main
 
 type opcode_method_ty struct {
-	name        *char
+	name        string
 	size        int
 	destructor  func(*opcode_ty)
 	execute     func(*opcode_ty, *opcode_context_ty) opcode_status_ty
 	script      func(*opcode_ty, *opcode_context_ty) opcode_status_ty
-	disassemble func(*opcode_ty)
+	disassemble func(*opcode_ty) string
+
+	/*
+	 * For the disassembler: the jump destinations of the opcode, and
+	 * the printing of any opcode lists it contains.  Both may be NULL.
+	 */
+	targets            func(*opcode_ty) []size_t
+	disassemble_nested func(*opcode_ty)
 }
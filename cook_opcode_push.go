@@ -0,0 +1,67 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+/*
+ * The push opcode pushes a new, empty, string list onto the value
+ * stack.  Subsequent opcodes append their results to it.
+ */
+
+type opcode_push_ty struct{}
+
+/*
+ * NAME
+ *      opcode_push_execute
+ *
+ * SYNOPSIS
+ *      opcode_status_ty opcode_push_execute(opcode_ty *,
+ *              opcode_context_ty *);
+ *
+ * DESCRIPTION
+ *      The opcode_push_execute function is used to execute the given
+ *      opcode within the given interpretation context.
+ */
+
+func opcode_push_execute(op *opcode_ty, ocp *opcode_context_ty) opcode_status_ty {
+	opcode_context_string_push(ocp)
+	return opcode_status_success
+}
+
+var opcode_push_method = opcode_method_ty{
+	name:    "push",
+	execute: opcode_push_execute,
+	script:  opcode_push_execute,
+}
+
+/*
+ * NAME
+ *      opcode_push_new
+ *
+ * SYNOPSIS
+ *      opcode_ty *opcode_push_new(void);
+ *
+ * DESCRIPTION
+ *      The opcode_push_new function is used to allocate a new instance
+ *      of a push opcode.
+ */
+
+func opcode_push_new() *opcode_ty {
+	return opcode_new(&opcode_push_method, &opcode_push_ty{})
+}
@@ -0,0 +1,202 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import "strings"
+
+/*
+ * The recipe opcode defines a recipe.  The flags (top-most) and the
+ * targets (next) are popped from the value stack; everything else was
+ * compiled into opcode lists, to be evaluated when the recipe is used.
+ */
+
+type opcode_recipe_ty struct {
+	need1         *opcode_list_ty
+	need2         *opcode_list_ty /* NULL if no second ingredients */
+	precondition  *opcode_list_ty /* NULL if no if clause */
+	single_thread *opcode_list_ty /* NULL if no single-thread clause */
+	host_binding  *opcode_list_ty /* NULL if no host-binding clause */
+	out_of_date   *opcode_list_ty /* NULL if no body */
+	up_to_date    *opcode_list_ty /* NULL if no else clause */
+	multiple      int
+	pos           expr_position_ty
+}
+
+func opcode_recipe_destructor(op *opcode_ty) {
+	this, ok := op.this.(*opcode_recipe_ty)
+	assert(ok, "op.this.(*opcode_recipe_ty)")
+	opcode_list_delete(this.need1)
+	opcode_list_delete(this.need2)
+	opcode_list_delete(this.precondition)
+	opcode_list_delete(this.single_thread)
+	opcode_list_delete(this.host_binding)
+	opcode_list_delete(this.out_of_date)
+	opcode_list_delete(this.up_to_date)
+	expr_position_destructor(&this.pos)
+}
+
+/*
+ * NAME
+ *      opcode_recipe_execute
+ *
+ * SYNOPSIS
+ *      opcode_status_ty opcode_recipe_execute(opcode_ty *,
+ *              opcode_context_ty *);
+ *
+ * DESCRIPTION
+ *      The opcode_recipe_execute function is used to execute the given
+ *      opcode within the given interpretation context.
+ */
+
+func opcode_recipe_execute(op *opcode_ty, ocp *opcode_context_ty) opcode_status_ty {
+	this, ok := op.this.(*opcode_recipe_ty)
+	assert(ok, "op.this.(*opcode_recipe_ty)")
+	flags_words := opcode_context_string_list_pop(ocp)
+	target := opcode_context_string_list_pop(ocp)
+	defer string_list_delete(flags_words)
+	defer string_list_delete(target)
+
+	if len(target.strings) == 0 {
+		error_with_position(&this.pos, nil, i18n("recipe has no targets"))
+		return opcode_status_error
+	}
+	flags := flag_recast(flags_words, &this.pos)
+	if flags == nil {
+		return opcode_status_error
+	}
+
+	/*
+	 * A recipe is implicit if any of its targets is a pattern.  The
+	 * recipe's flags may select the match mode.
+	 */
+	implicit := 0
+	flag_set_options(flags, OPTION_LEVEL_RECIPE)
+	mp := match_new()
+	option_undo_level(OPTION_LEVEL_RECIPE)
+	for _, s := range target.strings {
+		mask := match_usage_mask(mp, s, &this.pos)
+		if mask < 0 {
+			match_delete(mp)
+			return opcode_status_error
+		}
+		if mask != 0 {
+			implicit = 1
+			break
+		}
+	}
+	match_delete(mp)
+
+	rp := recipe_new(
+		target,
+		this.need1,
+		this.need2,
+		flags,
+		this.multiple,
+		this.precondition,
+		this.single_thread,
+		this.host_binding,
+		this.out_of_date,
+		this.up_to_date,
+		&this.pos,
+		implicit,
+	)
+	if implicit != 0 {
+		cook_implicit_append(rp)
+	} else {
+		cook_explicit_append(rp)
+	}
+	recipe_delete(rp)
+	return opcode_status_success
+}
+
+/*
+ * NAME
+ *      opcode_recipe_disassemble
+ *
+ * SYNOPSIS
+ *      char *opcode_recipe_disassemble(opcode_ty *);
+ *
+ * DESCRIPTION
+ *      The opcode_recipe_disassemble function is used to disassemble
+ *      the opcode's position.  The recipe's own opcode lists are printed
+ *      with the recipes, see recipe_disassemble.
+ */
+
+func opcode_recipe_disassemble(op *opcode_ty) string {
+	this, ok := op.this.(*opcode_recipe_ty)
+	assert(ok, "op.this.(*opcode_recipe_ty)")
+	result := ""
+	if this.multiple != 0 {
+		result = "multiple"
+	}
+	return strings.TrimSpace(result + " " + opcode_disassemble_position(&this.pos))
+}
+
+var opcode_recipe_method = opcode_method_ty{
+	name:        "recipe",
+	destructor:  opcode_recipe_destructor,
+	execute:     opcode_recipe_execute,
+	script:      opcode_recipe_execute,
+	disassemble: opcode_recipe_disassemble,
+}
+
+/*
+ * NAME
+ *      opcode_recipe_new
+ *
+ * SYNOPSIS
+ *      opcode_ty *opcode_recipe_new(opcode_list_ty *need1,
+ *              opcode_list_ty *need2, opcode_list_ty *precondition,
+ *              opcode_list_ty *single_thread,
+ *              opcode_list_ty *host_binding,
+ *              opcode_list_ty *out_of_date,
+ *              opcode_list_ty *up_to_date, int multiple,
+ *              expr_position_ty *pp);
+ *
+ * DESCRIPTION
+ *      The opcode_recipe_new function is used to allocate a new
+ *      instance of a recipe opcode.  The opcode takes over the opcode
+ *      lists.
+ */
+
+func opcode_recipe_new(
+	need1 *opcode_list_ty,
+	need2 *opcode_list_ty,
+	precondition *opcode_list_ty,
+	single_thread *opcode_list_ty,
+	host_binding *opcode_list_ty,
+	out_of_date *opcode_list_ty,
+	up_to_date *opcode_list_ty,
+	multiple int,
+	pp *expr_position_ty,
+) *opcode_ty {
+	this := &opcode_recipe_ty{
+		need1:         need1,
+		need2:         need2,
+		precondition:  precondition,
+		single_thread: single_thread,
+		host_binding:  host_binding,
+		out_of_date:   out_of_date,
+		up_to_date:    up_to_date,
+		multiple:      multiple,
+	}
+	expr_position_copy_constructor(&this.pos, pp)
+	return opcode_new(&opcode_recipe_method, this)
+}
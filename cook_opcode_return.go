@@ -0,0 +1,67 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+/*
+ * The return opcode ends the execution of the current opcode list.
+ * The top-most string list of the value stack is the result.
+ */
+
+type opcode_return_ty struct{}
+
+/*
+ * NAME
+ *      opcode_return_execute
+ *
+ * SYNOPSIS
+ *      opcode_status_ty opcode_return_execute(opcode_ty *,
+ *              opcode_context_ty *);
+ *
+ * DESCRIPTION
+ *      The opcode_return_execute function is used to execute the given
+ *      opcode within the given interpretation context.
+ */
+
+func opcode_return_execute(op *opcode_ty, ocp *opcode_context_ty) opcode_status_ty {
+	opcode_context_return(ocp)
+	return opcode_status_success
+}
+
+var opcode_return_method = opcode_method_ty{
+	name:    "return",
+	execute: opcode_return_execute,
+	script:  opcode_return_execute,
+}
+
+/*
+ * NAME
+ *      opcode_return_new
+ *
+ * SYNOPSIS
+ *      opcode_ty *opcode_return_new(void);
+ *
+ * DESCRIPTION
+ *      The opcode_return_new function is used to allocate a new
+ *      instance of a return opcode.
+ */
+
+func opcode_return_new() *opcode_ty {
+	return opcode_new(&opcode_return_method, &opcode_return_ty{})
+}
@@ -0,0 +1,105 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+/*
+ * The set opcode pops the top-most string list from the value stack,
+ * and sets the named flags as cookbook options.
+ */
+
+type opcode_set_ty struct {
+	pos expr_position_ty
+}
+
+func opcode_set_destructor(op *opcode_ty) {
+	this, ok := op.this.(*opcode_set_ty)
+	assert(ok, "op.this.(*opcode_set_ty)")
+	expr_position_destructor(&this.pos)
+}
+
+/*
+ * NAME
+ *      opcode_set_execute
+ *
+ * SYNOPSIS
+ *      opcode_status_ty opcode_set_execute(opcode_ty *,
+ *              opcode_context_ty *);
+ *
+ * DESCRIPTION
+ *      The opcode_set_execute function is used to execute the given
+ *      opcode within the given interpretation context.
+ */
+
+func opcode_set_execute(op *opcode_ty, ocp *opcode_context_ty) opcode_status_ty {
+	this, ok := op.this.(*opcode_set_ty)
+	assert(ok, "op.this.(*opcode_set_ty)")
+	slp := opcode_context_string_list_pop(ocp)
+	fp := flag_recast(slp, &this.pos)
+	string_list_delete(slp)
+	if fp == nil {
+		return opcode_status_error
+	}
+	flag_set_options(fp, OPTION_LEVEL_COOKBOOK)
+	return opcode_status_success
+}
+
+/*
+ * NAME
+ *      opcode_set_disassemble
+ *
+ * SYNOPSIS
+ *      char *opcode_set_disassemble(opcode_ty *);
+ *
+ * DESCRIPTION
+ *      The opcode_set_disassemble function is used to disassemble
+ *      the opcode's position.
+ */
+
+func opcode_set_disassemble(op *opcode_ty) string {
+	this, ok := op.this.(*opcode_set_ty)
+	assert(ok, "op.this.(*opcode_set_ty)")
+	return opcode_disassemble_position(&this.pos)
+}
+
+var opcode_set_method = opcode_method_ty{
+	name:        "set",
+	destructor:  opcode_set_destructor,
+	execute:     opcode_set_execute,
+	script:      opcode_set_execute,
+	disassemble: opcode_set_disassemble,
+}
+
+/*
+ * NAME
+ *      opcode_set_new
+ *
+ * SYNOPSIS
+ *      opcode_ty *opcode_set_new(expr_position_ty *);
+ *
+ * DESCRIPTION
+ *      The opcode_set_new function is used to allocate a new instance
+ *      of a set opcode.
+ */
+
+func opcode_set_new(pp *expr_position_ty) *opcode_ty {
+	this := &opcode_set_ty{}
+	expr_position_copy_constructor(&this.pos, pp)
+	return opcode_new(&opcode_set_method, this)
+}
@@ -0,0 +1,99 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import "fmt"
+
+/*
+ * The string opcode appends a constant string to the top-most string
+ * list of the value stack.
+ */
+
+type opcode_string_ty struct {
+	value *string_ty
+}
+
+func opcode_string_destructor(op *opcode_ty) {
+	this, ok := op.this.(*opcode_string_ty)
+	assert(ok, "op.this.(*opcode_string_ty)")
+	str_free(this.value)
+}
+
+/*
+ * NAME
+ *      opcode_string_execute
+ *
+ * SYNOPSIS
+ *      opcode_status_ty opcode_string_execute(opcode_ty *,
+ *              opcode_context_ty *);
+ *
+ * DESCRIPTION
+ *      The opcode_string_execute function is used to execute the given
+ *      opcode within the given interpretation context.
+ */
+
+func opcode_string_execute(op *opcode_ty, ocp *opcode_context_ty) opcode_status_ty {
+	this, ok := op.this.(*opcode_string_ty)
+	assert(ok, "op.this.(*opcode_string_ty)")
+	opcode_context_string_push_string(ocp, this.value)
+	return opcode_status_success
+}
+
+/*
+ * NAME
+ *      opcode_string_disassemble
+ *
+ * SYNOPSIS
+ *      char *opcode_string_disassemble(opcode_ty *);
+ *
+ * DESCRIPTION
+ *      The opcode_string_disassemble function is used to disassemble
+ *      the opcode's string.
+ */
+
+func opcode_string_disassemble(op *opcode_ty) string {
+	this, ok := op.this.(*opcode_string_ty)
+	assert(ok, "op.this.(*opcode_string_ty)")
+	return fmt.Sprintf("%q", this.value.str)
+}
+
+var opcode_string_method = opcode_method_ty{
+	name:        "string",
+	destructor:  opcode_string_destructor,
+	execute:     opcode_string_execute,
+	script:      opcode_string_execute,
+	disassemble: opcode_string_disassemble,
+}
+
+/*
+ * NAME
+ *      opcode_string_new
+ *
+ * SYNOPSIS
+ *      opcode_ty *opcode_string_new(string_ty *);
+ *
+ * DESCRIPTION
+ *      The opcode_string_new function is used to allocate a new
+ *      instance of a string opcode.  The string is copied.
+ */
+
+func opcode_string_new(s *string_ty) *opcode_ty {
+	return opcode_new(&opcode_string_method, &opcode_string_ty{value: str_copy(s)})
+}
@@ -0,0 +1,220 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+/*
+ * For each option, which levels have set it, and to what.
+ * Bit n of each word corresponds to level n.
+ */
+var option_set_mask [OPTION_max]uint
+var option_value_mask [OPTION_max]uint
+
+var option_name_table = [OPTION_max]string{
+	OPTION_ACTION:                  "action",
+	OPTION_CASCADE:                 "cascade",
+	OPTION_CTIME:                   "ctime",
+	OPTION_DEEP:                    "deep",
+	OPTION_ERROK:                   "errok",
+	OPTION_FINGERPRINT:             "fingerprint",
+	OPTION_FINGERPRINT_WRITE:       "fingerprint-write",
+	OPTION_FORCE:                   "force",
+	OPTION_GATEFIRST:               "gate-first",
+	OPTION_IMPLICIT_ALLOWED:        "implicit-ingredients",
+	OPTION_INCLUDE_COOKED:          "include-cooked",
+	OPTION_INCLUDE_COOKED_WARNING:  "include-cooked-warning",
+	OPTION_INGREDIENTS_FINGERPRINT: "ingredients-fingerprint",
+	OPTION_INVALIDATE_STAT_CACHE:   "clear-stat",
+	OPTION_MATCH_MODE_REGEX:        "match-mode-regex",
+	OPTION_METER:                   "meter",
+	OPTION_MKDIR:                   "mkdir",
+	OPTION_PERSEVERE:               "persevere",
+	OPTION_PRECIOUS:                "precious",
+	OPTION_RECURSE:                 "recurse",
+	OPTION_SHALLOW:                 "shallow",
+	OPTION_SILENT:                  "silent",
+	OPTION_STAR:                    "star",
+	OPTION_STRIP_DOT:               "strip-dot",
+	OPTION_SYMLINK_INGREDIENTS:     "symlink-ingredients",
+	OPTION_TELL_POSITION:           "tell-position",
+	OPTION_UNLINK:                  "unlink",
+	OPTION_UPDATE:                  "update",
+	OPTION_UPDATE_MAX:              "update-max",
+}
+
+/*
+ * NAME
+ *      option_set - set an option
+ *
+ * SYNOPSIS
+ *      void option_set(option_number_ty, option_level_ty, int state);
+ *
+ * DESCRIPTION
+ *      The option_set function is used to set the given option at the
+ *      given level to the given state.
+ */
+
+func option_set(o option_number_ty, level option_level_ty, state bool) {
+	assert(o >= 0 && o < OPTION_max, "o in range")
+	assert(level >= 0 && level < OPTION_LEVEL_max, "level in range")
+	mask := uint(1) << uint(level)
+	option_set_mask[o] |= mask
+	if state {
+		option_value_mask[o] |= mask
+	} else {
+		option_value_mask[o] &^= mask
+	}
+}
+
+/*
+ * NAME
+ *      option_already - see if an option is already set
+ *
+ * SYNOPSIS
+ *      int option_already(option_number_ty, option_level_ty);
+ *
+ * DESCRIPTION
+ *      The option_already function is used to test if a given option
+ *      at a given level has been set.
+ */
+
+func option_already(o option_number_ty, level option_level_ty) bool {
+	return option_set_mask[o]&(uint(1)<<uint(level)) != 0
+}
+
+/*
+ * NAME
+ *      option_undo - remove an option setting
+ *
+ * SYNOPSIS
+ *      void option_undo(option_number_ty, option_level_ty);
+ *
+ * DESCRIPTION
+ *      The option_undo function is used to forget the setting of the
+ *      given option at the given level, exposing any setting at lower
+ *      levels.
+ */
+
+func option_undo(o option_number_ty, level option_level_ty) {
+	mask := uint(1) << uint(level)
+	option_set_mask[o] &^= mask
+	option_value_mask[o] &^= mask
+}
+
+/*
+ * NAME
+ *      option_undo_level - remove options settings
+ *
+ * SYNOPSIS
+ *      void option_undo_level(option_level_ty);
+ *
+ * DESCRIPTION
+ *      The option_undo_level function is used to forget the setting of
+ *      all options at the given level.
+ */
+
+func option_undo_level(level option_level_ty) {
+	for o := option_number_ty(0); o < OPTION_max; o++ {
+		option_undo(o, level)
+	}
+}
+
+/*
+ * NAME
+ *      option_test - test an option
+ *
+ * SYNOPSIS
+ *      int option_test(option_number_ty);
+ *
+ * DESCRIPTION
+ *      The option_test function is used to test the setting of an
+ *      option.  The setting at the highest priority level is used.
+ *
+ * RETURNS
+ *      int; zero if the option is not set, or is set false, non-zero
+ *      if it is set true.
+ */
+
+func option_test(o option_number_ty) bool {
+	assert(o >= 0 && o < OPTION_max, "o in range")
+	set := option_set_mask[o]
+	if set == 0 {
+		return false
+	}
+	lowest := set & -set
+	return option_value_mask[o]&lowest != 0
+}
+
+/*
+ * NAME
+ *      option_tidy_up - start up options
+ *
+ * SYNOPSIS
+ *      void option_tidy_up(void);
+ *
+ * DESCRIPTION
+ *      The option_tidy_up function is used to set the default values
+ *      of those options which default to true.  (Everything else
+ *      defaults to false.)
+ */
+
+func option_tidy_up() {
+	option_set(OPTION_ACTION, OPTION_LEVEL_DEFAULT, true)
+	option_set(OPTION_CASCADE, OPTION_LEVEL_DEFAULT, true)
+	option_set(OPTION_FINGERPRINT_WRITE, OPTION_LEVEL_DEFAULT, true)
+	option_set(OPTION_IMPLICIT_ALLOWED, OPTION_LEVEL_DEFAULT, true)
+	option_set(OPTION_INCLUDE_COOKED, OPTION_LEVEL_DEFAULT, true)
+	option_set(OPTION_INCLUDE_COOKED_WARNING, OPTION_LEVEL_DEFAULT, true)
+}
+
+/*
+ * NAME
+ *      option_set_errors - turn off options after errors
+ *
+ * SYNOPSIS
+ *      void option_set_errors(void);
+ *
+ * DESCRIPTION
+ *      The option_set_errors function is used to turn off those options
+ *      which make no sense once the cookbook contained errors.
+ */
+
+func option_set_errors() {
+	option_set(OPTION_ACTION, OPTION_LEVEL_ERROR, false)
+	option_set(OPTION_FINGERPRINT_WRITE, OPTION_LEVEL_ERROR, false)
+}
+
+/*
+ * NAME
+ *      option_number_name - name of an option
+ *
+ * SYNOPSIS
+ *      char *option_number_name(option_number_ty);
+ *
+ * DESCRIPTION
+ *      The option_number_name function is used to obtain the name of
+ *      an option, for use in error messages.
+ */
+
+func option_number_name(o option_number_ty) string {
+	if o < 0 || o >= OPTION_max {
+		return "unknown"
+	}
+	return option_name_table[o]
+}
@@ -0,0 +1,93 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+type option_ty struct {
+	o_book    *string_ty     /* the cookbook, from -Book or the default */
+	o_include string_list_ty /* directories to search for #include */
+	o_target  string_list_ty /* targets named on the command line */
+	o_vardef  string_list_ty /* name=value assignments on the command line */
+
+	o_disassemble bool /* list the compiled cookbook, don't cook */
+	o_statistics  bool /* print dependency graph statistics */
+	o_parallel    long /* how many recipe bodies may run at once */
+
+	o_fingerprint_update bool /* rescan the fingerprints, don't cook */
+}
+
+var option option_ty
+
+/*
+ * The options are set at several levels.  An option set at a higher
+ * level (lower number) masks any setting at lower levels, so that,
+ * for example, the command line overrides the cookbook, which in turn
+ * overrides the defaults.
+ */
+
+type option_level_ty int
+
+// enum option_level_ty
+const (
+	OPTION_LEVEL_ERROR option_level_ty = iota
+	OPTION_LEVEL_AUTO
+	OPTION_LEVEL_COMMAND_LINE
+	OPTION_LEVEL_EXECUTE
+	OPTION_LEVEL_RECIPE
+	OPTION_LEVEL_COOKBOOK
+	OPTION_LEVEL_ENVIRONMENT
+	OPTION_LEVEL_DEFAULT
+	OPTION_LEVEL_max /* MUST be last */
+)
+
+type option_number_ty int
+
+// enum option_number_ty
+const (
+	OPTION_ACTION option_number_ty = iota
+	OPTION_CASCADE
+	OPTION_CTIME
+	OPTION_DEEP
+	OPTION_ERROK
+	OPTION_FINGERPRINT
+	OPTION_FINGERPRINT_WRITE
+	OPTION_FORCE
+	OPTION_GATEFIRST
+	OPTION_IMPLICIT_ALLOWED
+	OPTION_INCLUDE_COOKED
+	OPTION_INCLUDE_COOKED_WARNING
+	OPTION_INGREDIENTS_FINGERPRINT
+	OPTION_INVALIDATE_STAT_CACHE
+	OPTION_MATCH_MODE_REGEX
+	OPTION_METER
+	OPTION_MKDIR
+	OPTION_PERSEVERE
+	OPTION_PRECIOUS
+	OPTION_RECURSE
+	OPTION_SHALLOW
+	OPTION_SILENT
+	OPTION_STAR
+	OPTION_STRIP_DOT
+	OPTION_SYMLINK_INGREDIENTS
+	OPTION_TELL_POSITION
+	OPTION_UNLINK
+	OPTION_UPDATE
+	OPTION_UPDATE_MAX
+	OPTION_max /* MUST be last */
+)
@@ -0,0 +1,44 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"os"
+	"syscall"
+)
+
+/*
+ * NAME
+ *      os_ctime - file's last status change time
+ *
+ * SYNOPSIS
+ *      long os_ctime(struct stat *);
+ *
+ * DESCRIPTION
+ *      The os_ctime function is used to obtain the last status change
+ *      time of a file, in nanoseconds since the epoch.
+ */
+
+func os_ctime(fi os.FileInfo) long {
+	if st, ok := fi.Sys().(*syscall.Stat_t); ok {
+		return long(st.Ctim.Sec)*1e9 + long(st.Ctim.Nsec)
+	}
+	return fi.ModTime().UnixNano()
+}
@@ -0,0 +1,43 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import "errors"
+
+/*
+ * NAME
+ *      os_error - report a system call error
+ *
+ * SYNOPSIS
+ *      void os_error(string_ty *path, error, char *msg);
+ *
+ * DESCRIPTION
+ *      The os_error function is used to report an error from a system
+ *      call, for the given file.  The message may use the $filename
+ *      and $errno substitutions.
+ */
+
+func os_error(path *string_ty, err error, msg string) {
+	scp := sub_context_new()
+	sub_var_set(scp, "ERRNO", "%s", errors.Unwrap(err))
+	sub_var_set_string(scp, "File_Name", path)
+	error_intl(scp, msg)
+	sub_context_delete(scp)
+}
@@ -0,0 +1,242 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+/*
+ * When a child process exits, its process id and exit status are
+ * posted to this channel by the goroutine waiting for it.  The
+ * interpreter itself only ever runs in the main goroutine.
+ */
+
+type os_child_ty struct {
+	pid    int
+	status int
+}
+
+var os_child_channel = make(chan os_child_ty)
+
+/*
+ * The number of child processes started, and not yet collected by
+ * os_wait.
+ */
+var os_child_count int
+
+/*
+ * Children which exited while os_wait_pid was waiting for another
+ * child; os_wait hands them out before waiting for any more.
+ */
+var os_child_reaped []os_child_ty
+
+/*
+ * NAME
+ *      os_execute_start - start a command
+ *
+ * SYNOPSIS
+ *      int os_execute_start(string_ty *cmd, string_ty *input,
+ *              expr_position_ty *pp);
+ *
+ * DESCRIPTION
+ *      The os_execute_start function is used to start the given
+ *      command, using the shell.  If input is not NULL it is supplied
+ *      to the command's standard input.  It does not wait for the
+ *      command to complete, use os_wait for that.
+ *
+ * RETURNS
+ *      int; the process id of the child, or -1 if it could not be
+ *      started (already reported).
+ */
+
+func os_execute_start(cmd *string_ty, input *string_ty, pp *expr_position_ty) int {
+	return os_execute_start_in(cmd, input, "", pp)
+}
+
+/*
+ * NAME
+ *      os_execute_start_in - start a command in a directory
+ *
+ * SYNOPSIS
+ *      int os_execute_start_in(string_ty *cmd, string_ty *input,
+ *              char *dir, expr_position_ty *pp);
+ *
+ * DESCRIPTION
+ *      The os_execute_start_in function is used to start the given
+ *      command, as for os_execute_start, with the given directory as
+ *      its current directory.  An empty dir means cook's own current
+ *      directory.
+ *
+ * RETURNS
+ *      int; the process id of the child, or -1 if it could not be
+ *      started (already reported).
+ */
+
+func os_execute_start_in(cmd *string_ty, input *string_ty, dir string, pp *expr_position_ty) int {
+	c := exec.Command("/bin/sh", "-c", cmd.str)
+	c.Dir = dir
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	if input != nil {
+		c.Stdin = strings.NewReader(input.str)
+	} else {
+		c.Stdin = os.Stdin
+	}
+	if err := c.Start(); err != nil {
+		scp := sub_context_new()
+		sub_var_set(scp, "ERRNO", "%s", err.Error())
+		error_with_position(pp, scp, i18n("sh: $errno"))
+		sub_context_delete(scp)
+		return -1
+	}
+	pid := c.Process.Pid
+	os_child_count++
+	go func() {
+		status := 0
+		if err := c.Wait(); err != nil {
+			var ee *exec.ExitError
+			if errors.As(err, &ee) && ee.ExitCode() >= 0 {
+				status = ee.ExitCode()
+			} else {
+				status = -1 /* killed by a signal */
+			}
+		}
+		os_child_channel <- os_child_ty{pid: pid, status: status}
+	}()
+	return pid
+}
+
+/*
+ * NAME
+ *      os_wait - wait for a child process
+ *
+ * SYNOPSIS
+ *      int os_wait(int *status);
+ *
+ * DESCRIPTION
+ *      The os_wait function is used to wait for any child process
+ *      started by os_execute_start to exit.
+ *
+ * RETURNS
+ *      int; the process id of the child, and its exit status (-1 if it
+ *      was killed by a signal).
+ */
+
+func os_wait() (pid int, status int) {
+	assert(os_child_count > 0, "os_child_count > 0")
+	var child os_child_ty
+	if len(os_child_reaped) > 0 {
+		child = os_child_reaped[0]
+		os_child_reaped = os_child_reaped[1:]
+	} else {
+		child = <-os_child_channel
+	}
+	os_child_count--
+	return child.pid, child.status
+}
+
+/*
+ * NAME
+ *      os_wait_pid - wait for a particular child process
+ *
+ * SYNOPSIS
+ *      int os_wait_pid(int pid);
+ *
+ * DESCRIPTION
+ *      The os_wait_pid function is used to wait for the given child
+ *      process to exit.  Other children which exit in the meantime
+ *      (those of the graph walker's recipes, say) are remembered, and
+ *      handed to their owners by later calls to os_wait.
+ *
+ * RETURNS
+ *      int; the exit status of the child (-1 if it was killed by a
+ *      signal).
+ */
+
+func os_wait_pid(pid int) int {
+	assert(os_child_count > 0, "os_child_count > 0")
+	for j, child := range os_child_reaped {
+		if child.pid == pid {
+			os_child_reaped = append(os_child_reaped[:j], os_child_reaped[j+1:]...)
+			os_child_count--
+			return child.status
+		}
+	}
+	for {
+		child := <-os_child_channel
+		if child.pid == pid {
+			os_child_count--
+			return child.status
+		}
+		os_child_reaped = append(os_child_reaped, child)
+	}
+}
+
+/*
+ * NAME
+ *      os_execute - run a command
+ *
+ * SYNOPSIS
+ *      int os_execute(string_ty *cmd, string_ty *output,
+ *              expr_position_ty *pp);
+ *
+ * DESCRIPTION
+ *      The os_execute function is used to run the given command, using
+ *      the shell, and wait for it to complete.  Unlike os_execute_start
+ *      the interpreter does not continue while the command runs; this
+ *      is for the builtin functions.  If output is not NULL the
+ *      command's standard output is collected into it, otherwise it
+ *      goes to cook's standard output.
+ *
+ * RETURNS
+ *      int; the exit status of the command (-1 if it was killed by a
+ *      signal), and false if it could not be started (already
+ *      reported).
+ */
+
+func os_execute(cmd *string_ty, output *strings.Builder, pp *expr_position_ty) (int, bool) {
+	c := exec.Command("/bin/sh", "-c", cmd.str)
+	if output != nil {
+		c.Stdout = output
+	} else {
+		c.Stdout = os.Stdout
+	}
+	c.Stderr = os.Stderr
+	c.Stdin = os.Stdin
+	if err := c.Start(); err != nil {
+		scp := sub_context_new()
+		sub_var_set(scp, "ERRNO", "%s", err.Error())
+		error_with_position(pp, scp, i18n("sh: $errno"))
+		sub_context_delete(scp)
+		return 0, false
+	}
+	if err := c.Wait(); err != nil {
+		var ee *exec.ExitError
+		if errors.As(err, &ee) && ee.ExitCode() >= 0 {
+			return ee.ExitCode(), true
+		}
+		return -1, true /* killed by a signal */
+	}
+	return 0, true
+}
@@ -0,0 +1,72 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import "testing"
+
+func TestOsWaitPid(t *testing.T) {
+	fast := os_execute_start(str_from_string("exit 3"), nil, nil)
+	slow := os_execute_start(str_from_string("sleep 0.2; exit 5"), nil, nil)
+	if fast < 0 || slow < 0 {
+		t.Fatal("can't start children")
+	}
+	if status := os_wait_pid(slow); status != 5 {
+		t.Errorf("os_wait_pid(slow) = %d, want 5", status)
+	}
+
+	/*
+	 * The fast child exited while waiting for the slow one; it must
+	 * still be handed out.
+	 */
+	pid, status := os_wait()
+	if pid != fast || status != 3 {
+		t.Errorf("os_wait() = %d, %d, want %d, 3", pid, status, fast)
+	}
+	if os_child_count != 0 || len(os_child_reaped) != 0 {
+		t.Errorf("%d children outstanding, %d reaped", os_child_count, len(os_child_reaped))
+	}
+}
+
+/*
+ * A body run to completion while the graph walker has other children
+ * running (as the include-cooked files or builtins may be) must not
+ * take their exit status.
+ */
+
+func TestOpcodeContextExecuteNowaitOtherChild(t *testing.T) {
+	cookbook_test_read(t, "x: { sleep 0.2; exit 0; }\n")
+	other := os_execute_start(str_from_string("exit 4"), nil, nil)
+	if other < 0 {
+		t.Fatal("can't start child")
+	}
+	ocp := opcode_context_new(explicit.recipe[0].out_of_date, nil)
+	var status opcode_status_ty
+	capture_stderr(t, func() {
+		status = opcode_context_execute_nowait(ocp)
+	})
+	opcode_context_delete(ocp)
+	if status != opcode_status_success {
+		t.Errorf("opcode_context_execute_nowait = %d, want success", status)
+	}
+	pid, exit_status := os_wait()
+	if pid != other || exit_status != 4 {
+		t.Errorf("os_wait() = %d, %d, want %d, 4", pid, exit_status, other)
+	}
+}
@@ -0,0 +1,53 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+)
+
+/*
+ * NAME
+ *      os_mkdir_parent - make a file's directory
+ *
+ * SYNOPSIS
+ *      int os_mkdir_parent(string_ty *path);
+ *
+ * DESCRIPTION
+ *      The os_mkdir_parent function is used to create the directory
+ *      the given file is to be placed in, and any missing directories
+ *      leading to it.  It is not an error if it already exists.
+ *
+ * RETURNS
+ *      int; 0 on success, -1 on error (already reported).
+ */
+
+func os_mkdir_parent(path *string_ty) int {
+	dir := filepath.Dir(path.str)
+	if dir == "." || dir == "/" {
+		return 0
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		os_error(path, err, i18n("mkdir $filename: $errno"))
+		return -1
+	}
+	return 0
+}
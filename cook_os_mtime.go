@@ -0,0 +1,127 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"syscall"
+)
+
+/*
+ * NAME
+ *      os_stat_error - report a stat error
+ *
+ * SYNOPSIS
+ *      void os_stat_error(string_ty *path, error);
+ *
+ * DESCRIPTION
+ *      The os_stat_error function is used to report an error from the
+ *      stat system call, other than the file not existing.
+ */
+
+func os_stat_error(path *string_ty, err error) {
+	scp := sub_context_new()
+	sub_var_set(scp, "ERRNO", "%s", errors.Unwrap(err))
+	sub_var_set_string(scp, "File_Name", path)
+	error_intl(scp, i18n("stat $filename: $errno"))
+	sub_context_delete(scp)
+}
+
+/*
+ * NAME
+ *      os_mtime_newest - file's last modification time
+ *
+ * SYNOPSIS
+ *      long os_mtime_newest(string_ty *path);
+ *
+ * DESCRIPTION
+ *      The os_mtime_newest function is used to obtain the last
+ *      modification time of a file, in nanoseconds since the epoch.
+ *
+ * RETURNS
+ *      long; 0 if the file does not exist, -1 on error (already
+ *      reported).
+ */
+
+func os_mtime_newest(path *string_ty) long {
+	st, err := os.Stat(path.str)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) || errors.Is(err, syscall.ENOTDIR) {
+			return 0
+		}
+		os_stat_error(path, err)
+		return -1
+	}
+	return st.ModTime().UnixNano()
+}
+
+/*
+ * NAME
+ *      os_exists - test for file existence
+ *
+ * SYNOPSIS
+ *      int os_exists(string_ty *path);
+ *
+ * DESCRIPTION
+ *      The os_exists function is used to determine whether a file
+ *      exists.  Symbolic links are followed.
+ *
+ * RETURNS
+ *      int; 1 if the file exists, 0 if it does not, -1 on error
+ *      (already reported).
+ */
+
+func os_exists(path *string_ty) int {
+	return os_exists_stat(path, os.Stat)
+}
+
+/*
+ * NAME
+ *      os_exists_symlink - test for file existence
+ *
+ * SYNOPSIS
+ *      int os_exists_symlink(string_ty *path);
+ *
+ * DESCRIPTION
+ *      The os_exists_symlink function is used to determine whether a
+ *      file exists.  Symbolic links are not followed, so a dangling
+ *      symbolic link exists.
+ *
+ * RETURNS
+ *      int; 1 if the file exists, 0 if it does not, -1 on error
+ *      (already reported).
+ */
+
+func os_exists_symlink(path *string_ty) int {
+	return os_exists_stat(path, os.Lstat)
+}
+
+func os_exists_stat(path *string_ty, stat func(string) (os.FileInfo, error)) int {
+	if _, err := stat(path.str); err != nil {
+		if errors.Is(err, fs.ErrNotExist) || errors.Is(err, syscall.ENOTDIR) {
+			return 0
+		}
+		os_stat_error(path, err)
+		return -1
+	}
+	return 1
+}
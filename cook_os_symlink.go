@@ -0,0 +1,90 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+)
+
+/*
+ * NAME
+ *      os_readlink - read a symbolic link
+ *
+ * SYNOPSIS
+ *      string_ty *os_readlink(string_ty *path, int *status);
+ *
+ * DESCRIPTION
+ *      The os_readlink function is used to obtain the contents of a
+ *      symbolic link.
+ *
+ * RETURNS
+ *      string_ty *; NULL if the file does not exist or is not a
+ *      symbolic link.  The status is -1 on error (already reported), 0
+ *      otherwise.  Use str_free when you are done with the result.
+ */
+
+func os_readlink(path *string_ty) (*string_ty, int) {
+	st, err := os.Lstat(path.str)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, 0
+		}
+		os_stat_error(path, err)
+		return nil, -1
+	}
+	if st.Mode()&fs.ModeSymlink == 0 {
+		return nil, 0
+	}
+	s, err := os.Readlink(path.str)
+	if err != nil {
+		os_error(path, err, i18n("readlink $filename: $errno"))
+		return nil, -1
+	}
+	return str_from_string(s), 0
+}
+
+/*
+ * NAME
+ *      os_symlink - make a symbolic link
+ *
+ * SYNOPSIS
+ *      int os_symlink(string_ty *target, string_ty *path);
+ *
+ * DESCRIPTION
+ *      The os_symlink function is used to make a symbolic link, with
+ *      the given path, referring to the given target.  Any missing
+ *      directories leading to the path are created.
+ *
+ * RETURNS
+ *      int; 0 on success, -1 on error (already reported).
+ */
+
+func os_symlink(target, path *string_ty) int {
+	if os_mkdir_parent(path) < 0 {
+		return -1
+	}
+	if err := os.Symlink(target.str, path.str); err != nil {
+		os_error(path, err, i18n("symlink $filename: $errno"))
+		return -1
+	}
+	return 0
+}
@@ -0,0 +1,49 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+)
+
+/*
+ * NAME
+ *      os_unlink - remove a file
+ *
+ * SYNOPSIS
+ *      int os_unlink(string_ty *path);
+ *
+ * DESCRIPTION
+ *      The os_unlink function is used to remove a file.  It is not an
+ *      error if the file does not exist.
+ *
+ * RETURNS
+ *      int; 0 on success, -1 on error (already reported).
+ */
+
+func os_unlink(path *string_ty) int {
+	if err := os.Remove(path.str); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		os_error(path, err, i18n("unlink $filename: $errno"))
+		return -1
+	}
+	return 0
+}
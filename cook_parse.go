@@ -0,0 +1,664 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"fmt"
+	"strconv"
+)
+
+/*
+ * The grammar of the cookbook language is
+ *
+ *      cookbook    : statement*
+ *      statement   : '{' statement* '}'
+ *                  | ';'
+ *                  | 'if' explist 'then' statement [ 'else' statement ]
+ *                  | 'loop' compound
+ *                  | 'loop' explist '=' explist compound
+ *                  | 'loopstop' ';'
+ *                  | 'return' explist ';'
+ *                  | 'fail' explist ';'
+ *                  | 'set' explist ';'
+ *                  | 'function' explist '=' compound
+ *                  | 'function' explist ';'
+ *                  | explist ( '=' | '+=' ) explist ';'
+ *                  | explist [ 'set' explist ] ( ';' | 'data' text 'dataend' )
+ *                  | recipe
+ *      explist     : element*
+ *      element     : primary+      (no white space between them)
+ *      primary     : word
+ *                  | '[' explist ']'
+ *
+ * Recipes are described in cook_stmt_recipe.go.  This is a simple
+ * recursive descent parser with one token of look-ahead.
+ */
+
+var parse_token token_ty
+
+func parse_advance() {
+	if parse_token.value != nil {
+		str_free(parse_token.value)
+	}
+	parse_token = lex()
+}
+
+/*
+ * The parse_token_text function is used to describe the current token
+ * in error messages.
+ */
+
+func parse_token_text() string {
+	switch parse_token.kind {
+	case token_eof:
+		return "end of file"
+	case token_colon:
+		return "\":\""
+	case token_colon2:
+		return "\"::\""
+	case token_equals:
+		return "\"=\""
+	case token_plus_equals:
+		return "\"+=\""
+	case token_semicolon:
+		return "\";\""
+	case token_lbrace:
+		return "\"{\""
+	case token_rbrace:
+		return "\"}\""
+	case token_lbracket:
+		return "\"[\""
+	case token_rbracket:
+		return "\"]\""
+	}
+	return strconv.Quote(parse_token.value.str)
+}
+
+/*
+ * The parse_error_unexpected function is used to report a syntax error
+ * at the current token.  The end of file is only reported once; every
+ * enclosing construct would otherwise complain about it too.
+ */
+
+var parse_eof_reported bool
+
+func parse_error_unexpected() {
+	if parse_token.kind == token_eof {
+		if parse_eof_reported {
+			return
+		}
+		parse_eof_reported = true
+	}
+	scp := sub_context_new()
+	sub_var_set(scp, "Name", "%s", parse_token_text())
+	lex_error(&parse_token.pos, scp, i18n("syntax error, unexpected $name"))
+	sub_context_delete(scp)
+}
+
+/*
+ * The parse_recover function is used after a syntax error to skip
+ * forward to a likely statement boundary.  A semicolon is consumed, a
+ * close brace is left for the enclosing compound statement.
+ */
+
+func parse_recover() {
+	for {
+		switch parse_token.kind {
+		case token_eof, token_rbrace:
+			return
+		case token_semicolon:
+			parse_advance()
+			return
+		}
+		parse_advance()
+	}
+}
+
+/*
+ * The parse_recover_bracket function is used after a syntax error
+ * inside a function call, to skip forward past the close bracket, so
+ * that the error is not reported again by the enclosing statement.
+ * The end of the statement is not skipped.
+ */
+
+func parse_recover_bracket() {
+	depth := 0
+	for {
+		switch parse_token.kind {
+		case token_eof, token_semicolon, token_lbrace, token_rbrace:
+			return
+
+		case token_lbracket:
+			depth++
+
+		case token_rbracket:
+			if depth == 0 {
+				parse_advance()
+				return
+			}
+			depth--
+		}
+		parse_advance()
+	}
+}
+
+/*
+ * The parse_expect function is used to consume a token of the given
+ * kind.  If the current token is something else, a syntax error is
+ * reported and false is returned.
+ */
+
+func parse_expect(kind lex_token_ty) bool {
+	if parse_token.kind != kind {
+		parse_error_unexpected()
+		return false
+	}
+	parse_advance()
+	return true
+}
+
+/*
+ * The parse_is_word function is used to determine if the current token
+ * can start an expression element.  Inside brackets, keywords lose
+ * their special meaning, so that "[if [x] then y else z]" works.  The
+ * cascade keyword is only special at the start of a statement, so that
+ * "set cascade" still names the flag.
+ */
+
+func parse_is_word(in_brackets bool) bool {
+	switch parse_token.kind {
+	case token_word, token_lbracket, token_cascade:
+		return true
+	}
+	return in_brackets && parse_token.value != nil
+}
+
+/*
+ * NAME
+ *      parse - read a cookbook
+ *
+ * SYNOPSIS
+ *      stmt_ty *parse(string_ty *filename);
+ *
+ * DESCRIPTION
+ *      The parse function is used to read and parse a cookbook, and any
+ *      files it includes.  If there are any syntax errors, they are
+ *      reported and cook exits.
+ *
+ * RETURNS
+ *      stmt_ty *; a compound statement holding the whole cookbook.
+ *      Use stmt_delete when finished with it.
+ */
+
+func parse(filename *string_ty) *stmt_ty {
+	trace(fmt.Sprintf("parse(filename = %q)\n{\n", filename.str))
+	lex_open(filename)
+	parse_eof_reported = false
+	pos := lex_position()
+	cookbook := stmt_compound_new(&pos)
+	parse_advance()
+	for parse_token.kind != token_eof {
+		if parse_token.kind == token_rbrace {
+			parse_error_unexpected()
+			parse_advance()
+			continue
+		}
+		sp := parse_statement()
+		stmt_compound_append(cookbook, sp)
+		stmt_delete(sp)
+	}
+	lex_close()
+	trace(fmt.Sprintf("return %p;\n", cookbook))
+	trace("}\n")
+	return cookbook
+}
+
+/*
+ * NAME
+ *      parse_explist - parse an expression list
+ *
+ * SYNOPSIS
+ *      expr_list_ty *parse_explist(int in_brackets);
+ *
+ * DESCRIPTION
+ *      The parse_explist function is used to parse a white space
+ *      separated list of expressions.  The list may be empty.
+ */
+
+func parse_explist(in_brackets bool) *expr_list_ty {
+	elp := expr_list_new()
+	for parse_is_word(in_brackets) {
+		ep := parse_element(in_brackets)
+		expr_list_append(elp, ep)
+		expr_delete(ep)
+	}
+	return elp
+}
+
+/*
+ * The parse_element function is used to parse one element of an
+ * expression list.  Adjacent primaries are catenated.
+ */
+
+func parse_element(in_brackets bool) *expr_ty {
+	pos := parse_token.pos
+	parts := expr_list_new()
+	for {
+		ep := parse_primary(in_brackets)
+		expr_list_append(parts, ep)
+		expr_delete(ep)
+		if parse_token.space_before || !parse_is_word(in_brackets) {
+			break
+		}
+	}
+	if len(parts.el_expr) == 1 {
+		ep := expr_copy(parts.el_expr[0])
+		expr_list_delete(parts)
+		return ep
+	}
+	ep := expr_catenate_new(parts, &pos)
+	expr_list_delete(parts)
+	return ep
+}
+
+func parse_primary(in_brackets bool) *expr_ty {
+	pos := parse_token.pos
+	if parse_token.kind != token_lbracket {
+		ep := expr_constant_new(parse_token.value, &pos)
+		parse_advance()
+		return ep
+	}
+
+	parse_advance()
+	args := parse_explist(true)
+	if len(args.el_expr) == 0 {
+		lex_error(&pos, nil, i18n("function call with no function name"))
+	}
+	if parse_token.kind == token_rbracket {
+		parse_advance()
+	} else {
+		parse_error_unexpected()
+		parse_recover_bracket()
+	}
+	ep := expr_function_new(args, &pos)
+	expr_list_delete(args)
+	return ep
+}
+
+/*
+ * NAME
+ *      parse_ingredients - parse an ingredients list
+ *
+ * SYNOPSIS
+ *      expr_list_ty *parse_ingredients(void);
+ *
+ * DESCRIPTION
+ *      The parse_ingredients function is used to parse the ingredients
+ *      list of a recipe.  Each element may be followed by an edge type,
+ *      one of "(strict)", "(weak)" or "(exists)", which says how its
+ *      timestamp is used when deciding if the targets are out of date.
+ */
+
+func parse_ingredients() *expr_list_ty {
+	elp := expr_list_new()
+	for parse_is_word(false) {
+		if _, ok := parse_edge_type(); ok {
+			scp := sub_context_new()
+			sub_var_set_string(scp, "Name", parse_token.value)
+			lex_error(&parse_token.pos, scp, i18n("$name must follow an ingredient"))
+			sub_context_delete(scp)
+			parse_advance()
+			continue
+		}
+		ep := parse_element(false)
+		if et, ok := parse_edge_type(); ok {
+			ep2 := expr_edge_new(ep, et, &parse_token.pos)
+			expr_delete(ep)
+			ep = ep2
+			parse_advance()
+		}
+		expr_list_append(elp, ep)
+		expr_delete(ep)
+	}
+	return elp
+}
+
+/*
+ * The parse_edge_type function is used to determine whether the
+ * current token is an edge type, and if so, which.
+ */
+
+func parse_edge_type() (edge_type_ty, bool) {
+	if parse_token.kind != token_word {
+		return edge_type_default, false
+	}
+	switch parse_token.value.str {
+	case "(strict)":
+		return edge_type_strict, true
+	case "(weak)":
+		return edge_type_weak, true
+	case "(exists)":
+		return edge_type_exists, true
+	}
+	return edge_type_default, false
+}
+
+/*
+ * The parse_explist_required function is used to parse an expression
+ * list which must not be empty.  The what argument names the construct
+ * for the error message.
+ */
+
+func parse_explist_required(what string) *expr_list_ty {
+	pos := parse_token.pos
+	elp := parse_explist(false)
+	if len(elp.el_expr) == 0 {
+		scp := sub_context_new()
+		sub_var_set(scp, "Name", "%s", what)
+		lex_error(&pos, scp, i18n("$name requires a value"))
+		sub_context_delete(scp)
+	}
+	return elp
+}
+
+/*
+ * NAME
+ *      parse_compound - parse a compound statement
+ *
+ * SYNOPSIS
+ *      stmt_ty *parse_compound(void);
+ *
+ * DESCRIPTION
+ *      The parse_compound function is used to parse a brace-enclosed
+ *      list of statements.
+ */
+
+func parse_compound() *stmt_ty {
+	pos := parse_token.pos
+	sp := stmt_compound_new(&pos)
+	if !parse_expect(token_lbrace) {
+		parse_recover()
+		return sp
+	}
+	for parse_token.kind != token_rbrace && parse_token.kind != token_eof {
+		child := parse_statement()
+		stmt_compound_append(sp, child)
+		stmt_delete(child)
+	}
+	parse_expect(token_rbrace)
+	return sp
+}
+
+/*
+ * The parse_semicolon function is used to finish a simple statement.
+ * On error, it recovers so that parsing may continue.
+ */
+
+func parse_semicolon() {
+	if !parse_expect(token_semicolon) {
+		parse_recover()
+	}
+}
+
+/*
+ * NAME
+ *      parse_statement - parse a statement
+ *
+ * SYNOPSIS
+ *      stmt_ty *parse_statement(void);
+ *
+ * DESCRIPTION
+ *      The parse_statement function is used to parse a single
+ *      statement.  Syntax errors are reported, and a nop statement is
+ *      returned in their place.
+ */
+
+func parse_statement() *stmt_ty {
+	pos := parse_token.pos
+	switch parse_token.kind {
+	case token_lbrace:
+		return parse_compound()
+
+	case token_semicolon:
+		parse_advance()
+		return stmt_nop_new(&pos)
+
+	case token_if:
+		parse_advance()
+		condition := parse_explist_required("if")
+		if !parse_expect(token_then) {
+			expr_list_delete(condition)
+			parse_recover()
+			return stmt_nop_new(&pos)
+		}
+		then_clause := parse_statement()
+		var else_clause *stmt_ty
+		if parse_token.kind == token_else {
+			parse_advance()
+			else_clause = parse_statement()
+		}
+		sp := stmt_if_new(condition, then_clause, else_clause, &pos)
+		stmt_delete(then_clause)
+		stmt_delete(else_clause)
+		return sp
+
+	case token_loop:
+		parse_advance()
+		if parse_token.kind == token_lbrace {
+			body := parse_compound()
+			sp := stmt_loop_new(body, &pos)
+			stmt_delete(body)
+			return sp
+		}
+		name := parse_explist_required("loop")
+		if !parse_expect(token_equals) {
+			expr_list_delete(name)
+			parse_recover()
+			return stmt_nop_new(&pos)
+		}
+		values := parse_explist(false)
+		body := parse_compound()
+		sp := stmt_loopvar_new(name, values, body, &pos)
+		stmt_delete(body)
+		return sp
+
+	case token_loopstop:
+		parse_advance()
+		parse_semicolon()
+		return stmt_loopstop_new(&pos)
+
+	case token_return:
+		parse_advance()
+		value := parse_explist(false)
+		parse_semicolon()
+		return stmt_return_new(value, &pos)
+
+	case token_fail:
+		parse_advance()
+		value := parse_explist(false)
+		parse_semicolon()
+		return stmt_fail_new(value, &pos)
+
+	case token_set:
+		parse_advance()
+		flags := parse_explist_required("set")
+		parse_semicolon()
+		return stmt_set_new(flags, &pos)
+
+	case token_cascade:
+		parse_advance()
+		target := parse_explist_required("cascade")
+		if !parse_expect(token_equals) {
+			expr_list_delete(target)
+			parse_recover()
+			return stmt_nop_new(&pos)
+		}
+		ingredient := parse_explist(false)
+		parse_semicolon()
+		return stmt_cascade_new(target, ingredient, &pos)
+
+	case token_function:
+		parse_advance()
+		name := parse_explist_required("function")
+		if parse_token.kind == token_equals {
+			parse_advance()
+			body := parse_compound()
+			sp := stmt_function_new(name, body, &pos)
+			stmt_delete(body)
+			return sp
+		}
+		parse_semicolon()
+		return stmt_gosub_new(name, &pos)
+
+	case token_word, token_lbracket:
+		return parse_word_statement()
+	}
+
+	parse_error_unexpected()
+	if parse_token.kind != token_rbrace {
+		parse_advance()
+	}
+	parse_recover()
+	return stmt_nop_new(&pos)
+}
+
+/*
+ * The parse_word_statement function is used to parse the statements
+ * which start with an expression list: assignments, commands and
+ * recipes.  Which one it is only becomes clear after the list.
+ */
+
+func parse_word_statement() *stmt_ty {
+	pos := parse_token.pos
+	list := parse_explist(false)
+
+	switch parse_token.kind {
+	case token_colon, token_colon2:
+		return parse_recipe(list)
+
+	case token_equals, token_plus_equals:
+		append := parse_token.kind == token_plus_equals
+		parse_advance()
+		value := parse_explist(false)
+		parse_semicolon()
+		return stmt_assign_new(list, value, append, &pos)
+	}
+
+	var flags *expr_list_ty
+	if parse_token.kind == token_set {
+		parse_advance()
+		flags = parse_explist_required("set")
+	}
+	var data *string_ty
+	if parse_token.kind == token_data {
+		data = lex_data()
+		parse_advance()
+		if parse_token.kind == token_semicolon {
+			parse_advance()
+		}
+	} else {
+		parse_semicolon()
+	}
+	sp := stmt_command_new(list, flags, data, &pos)
+	if data != nil {
+		str_free(data)
+	}
+	return sp
+}
+
+/*
+ * NAME
+ *      parse_recipe - parse a recipe
+ *
+ * SYNOPSIS
+ *      stmt_ty *parse_recipe(expr_list_ty *target);
+ *
+ * DESCRIPTION
+ *      The parse_recipe function is used to parse the rest of a recipe,
+ *      the targets have already been read and the current token is the
+ *      first colon.  The target list is taken over.
+ */
+
+func parse_recipe(target *expr_list_ty) *stmt_ty {
+	pos := parse_token.pos
+	multiple := 0
+	if parse_token.kind == token_colon2 {
+		multiple = 1
+	}
+	if len(target.el_expr) == 0 {
+		lex_error(&pos, nil, i18n("recipe has no targets"))
+	}
+	parse_advance()
+	need1 := parse_ingredients()
+	var need2 *expr_list_ty
+	if parse_token.kind == token_colon || parse_token.kind == token_colon2 {
+		parse_advance()
+		need2 = parse_ingredients()
+	}
+
+	var flags, precondition, single_thread, host_binding *expr_list_ty
+	for {
+		var clause **expr_list_ty
+		var what string
+		switch parse_token.kind {
+		case token_set:
+			clause, what = &flags, "set"
+		case token_if:
+			clause, what = &precondition, "if"
+		case token_single_thread:
+			clause, what = &single_thread, "single-thread"
+		case token_host_binding:
+			clause, what = &host_binding, "host-binding"
+		}
+		if clause == nil {
+			break
+		}
+		if *clause != nil {
+			scp := sub_context_new()
+			sub_var_set(scp, "Name", "%s", what)
+			lex_error(&parse_token.pos, scp, i18n("duplicate \"$name\" clause"))
+			sub_context_delete(scp)
+			expr_list_delete(*clause)
+		}
+		parse_advance()
+		*clause = parse_explist_required(what)
+	}
+
+	var out_of_date, up_to_date *stmt_ty
+	if parse_token.kind == token_semicolon {
+		parse_advance()
+	} else {
+		out_of_date = parse_compound()
+		if parse_token.kind == token_else {
+			parse_advance()
+			up_to_date = parse_compound()
+		}
+	}
+
+	sp := stmt_recipe_new(
+		target, need1, need2, flags, precondition, single_thread, host_binding,
+		out_of_date, up_to_date,
+		multiple,
+		&pos,
+	)
+	stmt_delete(out_of_date)
+	stmt_delete(up_to_date)
+	return sp
+}
@@ -0,0 +1,207 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import "fmt"
+
+/*
+ * NAME
+ *      recipe_new - create a new recipe
+ *
+ * SYNOPSIS
+ *      recipe_ty *recipe_new(string_list_ty *target,
+ *              opcode_list_ty *need1, opcode_list_ty *need2,
+ *              flag_ty *flags, int multiple,
+ *              opcode_list_ty *precondition,
+ *              opcode_list_ty *single_thread,
+ *              opcode_list_ty *host_binding,
+ *              opcode_list_ty *out_of_date,
+ *              opcode_list_ty *up_to_date,
+ *              expr_position_ty *pp, int implicit);
+ *
+ * DESCRIPTION
+ *      The recipe_new function is used to create a new recipe.  The
+ *      targets and flags are copied, the opcode lists are copied (any
+ *      of them except need1 may be NULL).
+ *
+ * RETURNS
+ *      recipe_ty *; use recipe_delete when you are done with it.
+ */
+
+func recipe_new(
+	target *string_list_ty,
+	need1 *opcode_list_ty,
+	need2 *opcode_list_ty,
+	flags *flag_ty,
+	multiple int,
+	precondition *opcode_list_ty,
+	single_thread *opcode_list_ty,
+	host_binding *opcode_list_ty,
+	out_of_date *opcode_list_ty,
+	up_to_date *opcode_list_ty,
+	pp *expr_position_ty,
+	implicit int,
+) *recipe_ty {
+	trace("recipe_new()\n{\n")
+	rp := &recipe_ty{
+		reference_count: 1,
+		target:          &string_list_ty{},
+		flags:           flag_copy(flags),
+		multiple:        multiple,
+		implicit:        implicit,
+	}
+	string_list_copy_constructor(rp.target, target)
+	rp.need1 = recipe_opcode_list_copy(need1)
+	rp.need2 = recipe_opcode_list_copy(need2)
+	rp.precondition = recipe_opcode_list_copy(precondition)
+	rp.single_thread = recipe_opcode_list_copy(single_thread)
+	rp.host_binding = recipe_opcode_list_copy(host_binding)
+	rp.out_of_date = recipe_opcode_list_copy(out_of_date)
+	rp.up_to_date = recipe_opcode_list_copy(up_to_date)
+	if pp != nil {
+		rp.pos = *pp
+		if pp.pos_name != nil {
+			rp.pos.pos_name = str_copy(pp.pos_name)
+		}
+	}
+	trace(fmt.Sprintf("return %p;\n", rp))
+	trace("}\n")
+	return rp
+}
+
+func recipe_opcode_list_copy(olp *opcode_list_ty) *opcode_list_ty {
+	if olp == nil {
+		return nil
+	}
+	return opcode_list_copy(olp)
+}
+
+/*
+ * NAME
+ *      recipe_copy - copy a recipe
+ *
+ * SYNOPSIS
+ *      recipe_ty *recipe_copy(recipe_ty *);
+ *
+ * DESCRIPTION
+ *      The recipe_copy function is used to make a copy of a recipe.
+ *      Recipes are never modified once built, so this is simply a
+ *      reference count increment.
+ */
+
+func recipe_copy(rp *recipe_ty) *recipe_ty {
+	assert(rp.reference_count > 0, "rp.reference_count > 0")
+	rp.reference_count++
+	return rp
+}
+
+/*
+ * NAME
+ *      recipe_delete - release a recipe
+ *
+ * SYNOPSIS
+ *      void recipe_delete(recipe_ty *);
+ *
+ * DESCRIPTION
+ *      The recipe_delete function is used to release a recipe when it
+ *      is finished with.
+ */
+
+func recipe_delete(rp *recipe_ty) {
+	assert(rp.reference_count > 0, "rp.reference_count > 0")
+	rp.reference_count--
+	if rp.reference_count > 0 {
+		return
+	}
+	string_list_delete(rp.target)
+	rp.target = nil
+	opcode_list_delete(rp.need1)
+	opcode_list_delete(rp.need2)
+	opcode_list_delete(rp.precondition)
+	opcode_list_delete(rp.single_thread)
+	opcode_list_delete(rp.host_binding)
+	opcode_list_delete(rp.out_of_date)
+	opcode_list_delete(rp.up_to_date)
+	rp.flags = nil
+	if rp.pos.pos_name != nil {
+		str_free(rp.pos.pos_name)
+		rp.pos.pos_name = nil
+	}
+}
+
+/*
+ * NAME
+ *      recipe_disassemble - print a recipe
+ *
+ * SYNOPSIS
+ *      void recipe_disassemble(recipe_ty *);
+ *
+ * DESCRIPTION
+ *      The recipe_disassemble function is used to print a human
+ *      readable listing of a recipe on the standard output: its
+ *      targets, flags and the disassembly of each of its opcode lists.
+ */
+
+func recipe_disassemble(rp *recipe_ty) {
+	kind := "explicit"
+	if rp.implicit != 0 {
+		kind = "implicit"
+	}
+	fmt.Printf("recipe (%s)", kind)
+	if rp.pos.pos_name != nil {
+		fmt.Printf("  # %s: %d", rp.pos.pos_name.str, rp.pos.pos_line)
+	}
+	fmt.Printf("\n")
+	s := wl2str(rp.target, 0, len(rp.target.strings)-1, "")
+	fmt.Printf("    target: %s\n", s.str)
+	str_free(s)
+	if rp.multiple != 0 {
+		fmt.Printf("    multiple\n")
+	}
+	s = flag_to_string(rp.flags)
+	if s.str != "" {
+		fmt.Printf("    flags: %s\n", s.str)
+	}
+	str_free(s)
+
+	lists := []struct {
+		name string
+		olp  *opcode_list_ty
+	}{
+		{"need1", rp.need1},
+		{"need2", rp.need2},
+		{"precondition", rp.precondition},
+		{"single_thread", rp.single_thread},
+		{"host_binding", rp.host_binding},
+		{"out_of_date", rp.out_of_date},
+		{"up_to_date", rp.up_to_date},
+	}
+	old := opcode_list_disassemble_indent
+	opcode_list_disassemble_indent = old + "        "
+	for _, l := range lists {
+		if l.olp == nil {
+			continue
+		}
+		fmt.Printf("    %s:\n", l.name)
+		opcode_list_disassemble(l.olp)
+	}
+	opcode_list_disassemble_indent = old
+	fmt.Printf("\n")
+}
@@ -0,0 +1,71 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+/*
+ * NAME
+ *      recipe_list_constructor
+ *
+ * SYNOPSIS
+ *      void recipe_list_constructor(recipe_list_ty *);
+ *
+ * DESCRIPTION
+ *      The recipe_list_constructor function is used to initialize a
+ *      recipe list to be empty.
+ */
+
+func recipe_list_constructor(rlp *recipe_list_ty) {
+	rlp.recipe = nil
+}
+
+/*
+ * NAME
+ *      recipe_list_destructor
+ *
+ * SYNOPSIS
+ *      void recipe_list_destructor(recipe_list_ty *);
+ *
+ * DESCRIPTION
+ *      The recipe_list_destructor function is used to release the
+ *      recipes held by a recipe list.
+ */
+
+func recipe_list_destructor(rlp *recipe_list_ty) {
+	for _, rp := range rlp.recipe {
+		recipe_delete(rp)
+	}
+	rlp.recipe = nil
+}
+
+/*
+ * NAME
+ *      recipe_list_append
+ *
+ * SYNOPSIS
+ *      void recipe_list_append(recipe_list_ty *, recipe_ty *);
+ *
+ * DESCRIPTION
+ *      The recipe_list_append function is used to append a recipe to
+ *      a recipe list.  The recipe is copied.
+ */
+
+func recipe_list_append(rlp *recipe_list_ty, rp *recipe_ty) {
+	rlp.recipe = append(rlp.recipe, recipe_copy(rp))
+}
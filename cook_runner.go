@@ -0,0 +1,78 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import "strings"
+
+var runner_table = []*runner_method_ty{
+	&runner_remote_method,
+	&runner_local_method,
+}
+
+/*
+ * NAME
+ *      runner_quote - quote a shell word
+ *
+ * SYNOPSIS
+ *      char *runner_quote(char *);
+ *
+ * DESCRIPTION
+ *      The runner_quote function is used to quote a string so that the
+ *      shell sees it as a single word, whatever it contains.
+ */
+
+func runner_quote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "'\\''") + "'"
+}
+
+/*
+ * NAME
+ *      runner_start - run a command on a host
+ *
+ * SYNOPSIS
+ *      int runner_start(string_ty *host, string_ty *cmd,
+ *              string_ty *input, expr_position_ty *pp);
+ *
+ * DESCRIPTION
+ *      The runner_start function is used to start the given command on
+ *      the given host, using the runner named by the "parallel_runner"
+ *      variable.  The default is the "remote" runner.
+ *
+ * RETURNS
+ *      int; the process id of the local child, or -1 if it could not be
+ *      started (already reported).
+ */
+
+func runner_start(host, cmd, input *string_ty, pp *expr_position_ty) int {
+	name := runner_remote_method.name
+	if wlp := id_variable_query("parallel_runner"); wlp != nil && len(wlp.strings) > 0 {
+		name = wlp.strings[0].str
+	}
+	for _, mp := range runner_table {
+		if mp.name == name {
+			return mp.start(host, cmd, input, pp)
+		}
+	}
+	scp := sub_context_new()
+	sub_var_set(scp, "Name", "%s", name)
+	error_with_position(pp, scp, i18n("parallel_runner \"$name\" unknown"))
+	sub_context_delete(scp)
+	return -1
+}
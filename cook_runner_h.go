@@ -0,0 +1,31 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+/*
+ * A runner is a means of running a command on another host, chosen by
+ * the "parallel_runner" variable.  The start method starts the command
+ * on the given host, and returns the process id of the local child
+ * process (see os_execute_start).
+ */
+type runner_method_ty struct {
+	name  string
+	start func(host, cmd, input *string_ty, pp *expr_position_ty) int
+}
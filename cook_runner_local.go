@@ -0,0 +1,64 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+)
+
+/*
+ * NAME
+ *      runner_local_start
+ *
+ * SYNOPSIS
+ *      int runner_local_start(string_ty *host, string_ty *cmd,
+ *              string_ty *input, expr_position_ty *pp);
+ *
+ * DESCRIPTION
+ *      The runner_local_start function is used to pretend to run a
+ *      command on another host, by running it locally in a directory
+ *      of its own for each host.  The directories are below the one
+ *      named by the "parallel_local_root" variable (default
+ *      ".cook.hosts"), and are created as required.  This allows host
+ *      bindings to be tried out without any other hosts.
+ */
+
+func runner_local_start(host, cmd, input *string_ty, pp *expr_position_ty) int {
+	root := ".cook.hosts"
+	if wlp := id_variable_query("parallel_local_root"); wlp != nil && len(wlp.strings) > 0 {
+		root = wlp.strings[0].str
+	}
+	dir := filepath.Join(root, host.str)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		scp := sub_context_new()
+		sub_var_set(scp, "Name", "%s", dir)
+		sub_var_set(scp, "ERRNO", "%s", err.Error())
+		error_with_position(pp, scp, i18n("mkdir $name: $errno"))
+		sub_context_delete(scp)
+		return -1
+	}
+	return os_execute_start_in(cmd, input, dir, pp)
+}
+
+var runner_local_method = runner_method_ty{
+	name:  "local",
+	start: runner_local_start,
+}
@@ -0,0 +1,71 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"os"
+	"strings"
+)
+
+/*
+ * NAME
+ *      runner_remote_start
+ *
+ * SYNOPSIS
+ *      int runner_remote_start(string_ty *host, string_ty *cmd,
+ *              string_ty *input, expr_position_ty *pp);
+ *
+ * DESCRIPTION
+ *      The runner_remote_start function is used to run a command on
+ *      another host, using the command named by the "parallel_rsh"
+ *      variable (default "ssh").  It is given the host name and a
+ *      command which changes to cook's current directory before running
+ *      the command, so the hosts are expected to share the file system.
+ *      Any ssh-compatible wrapper may be used.
+ */
+
+func runner_remote_start(host, cmd, input *string_ty, pp *expr_position_ty) int {
+	dir, err := os.Getwd()
+	if err != nil {
+		scp := sub_context_new()
+		sub_var_set(scp, "ERRNO", "%s", err.Error())
+		error_with_position(pp, scp, i18n("getcwd: $errno"))
+		sub_context_delete(scp)
+		return -1
+	}
+	var words []string
+	if wlp := id_variable_query("parallel_rsh"); wlp != nil && len(wlp.strings) > 0 {
+		for _, s := range wlp.strings {
+			words = append(words, s.str)
+		}
+	} else {
+		words = append(words, "ssh")
+	}
+	words = append(words, runner_quote(host.str))
+	words = append(words, runner_quote("cd "+runner_quote(dir)+" && "+cmd.str))
+	s := str_from_string(strings.Join(words, " "))
+	defer str_free(s)
+	return os_execute_start(s, input, pp)
+}
+
+var runner_remote_method = runner_method_ty{
+	name:  "remote",
+	start: runner_remote_start,
+}
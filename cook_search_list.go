@@ -0,0 +1,241 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import "strings"
+
+/*
+ * NAME
+ *      search_list_get - the search list
+ *
+ * SYNOPSIS
+ *      void search_list_get(string_list_ty *result);
+ *
+ * DESCRIPTION
+ *      The search_list_get function is used to obtain the directories
+ *      of the search list, from the search_list variable.  The current
+ *      directory is always first, it is added if the variable does not
+ *      start with it, because that is where targets are cooked.  The
+ *      result is appended to.
+ */
+
+func search_list_get(result *string_list_ty) {
+	dot := str_from_string(".")
+	defer str_free(dot)
+	string_list_append(result, dot)
+	wlp := id_variable_query(id_search_list.str)
+	if wlp == nil {
+		return
+	}
+	for j, s := range wlp.strings {
+		if j == 0 && str_equal(s, dot) {
+			continue
+		}
+		string_list_append(result, s)
+	}
+}
+
+/*
+ * NAME
+ *      search_list_path - name of a copy
+ *
+ * SYNOPSIS
+ *      string_ty *search_list_path(string_ty *dir, string_ty *name);
+ *
+ * DESCRIPTION
+ *      The search_list_path function is used to form the name of the
+ *      copy of a file in a search list directory.
+ *
+ * RETURNS
+ *      string_ty *; use str_free when you are done with it.
+ */
+
+func search_list_path(dir, name *string_ty) *string_ty {
+	if dir.str == "." {
+		return str_copy(name)
+	}
+	return str_from_string(strings.TrimSuffix(dir.str, "/") + "/" + name.str)
+}
+
+/*
+ * NAME
+ *      search_list_copies - all copies of a file
+ *
+ * SYNOPSIS
+ *      void search_list_copies(string_ty *name, string_list_ty *result);
+ *
+ * DESCRIPTION
+ *      The search_list_copies function is used to obtain the names of
+ *      the possible copies of a file, one in each directory of the
+ *      search list, in search list order.  Absolute names have only one
+ *      copy.  The result is appended to.
+ */
+
+func search_list_copies(name *string_ty, result *string_list_ty) {
+	if strings.HasPrefix(name.str, "/") {
+		string_list_append(result, name)
+		return
+	}
+	var dirs string_list_ty
+	search_list_get(&dirs)
+	for _, dir := range dirs.strings {
+		path := search_list_path(dir, name)
+		string_list_append(result, path)
+		str_free(path)
+	}
+	string_list_destructor(&dirs)
+}
+
+/*
+ * NAME
+ *      search_list_resolve - find a file
+ *
+ * SYNOPSIS
+ *      string_ty *search_list_resolve(string_ty *name);
+ *
+ * DESCRIPTION
+ *      The search_list_resolve function is used to find the first copy
+ *      of a file along the search list.
+ *
+ * RETURNS
+ *      string_ty *; the name of the first copy which exists, or the
+ *      name itself if there are none; NULL on error (already reported).
+ *      Use str_free when you are done with it.
+ */
+
+func search_list_resolve(name *string_ty) *string_ty {
+	var copies string_list_ty
+	defer string_list_destructor(&copies)
+	search_list_copies(name, &copies)
+	for _, path := range copies.strings {
+		switch stat_cache_exists(path) {
+		case -1:
+			return nil
+
+		case 1:
+			return str_copy(path)
+		}
+	}
+	return str_copy(name)
+}
+
+/*
+ * NAME
+ *      search_list_exists - test for file existence
+ *
+ * SYNOPSIS
+ *      int search_list_exists(string_ty *name);
+ *
+ * DESCRIPTION
+ *      The search_list_exists function is used to determine whether
+ *      any copy of a file exists along the search list.
+ *
+ * RETURNS
+ *      int; 1 if it exists, 0 if it does not, -1 on error (already
+ *      reported).
+ */
+
+func search_list_exists(name *string_ty) int {
+	var copies string_list_ty
+	defer string_list_destructor(&copies)
+	search_list_copies(name, &copies)
+	for _, path := range copies.strings {
+		if status := stat_cache_exists(path); status != 0 {
+			return status
+		}
+	}
+	return 0
+}
+
+/*
+ * NAME
+ *      search_list_mtime_newest - age of a target
+ *
+ * SYNOPSIS
+ *      long search_list_mtime_newest(string_ty *name);
+ *
+ * DESCRIPTION
+ *      The search_list_mtime_newest function is used to obtain the
+ *      last modification time of a target: that of the first copy
+ *      along the search list.  A target found in a later directory
+ *      (such as a baseline) need not be cooked again if it is up to
+ *      date; if it is cooked, the new copy is written in the current
+ *      directory.
+ *
+ * RETURNS
+ *      long; nanoseconds since the epoch, 0 if there are no copies, -1
+ *      on error (already reported).
+ */
+
+func search_list_mtime_newest(name *string_ty) long {
+	var copies string_list_ty
+	defer string_list_destructor(&copies)
+	search_list_copies(name, &copies)
+	for _, path := range copies.strings {
+		if mtime := stat_cache_mtime_newest(path); mtime != 0 {
+			return mtime
+		}
+	}
+	return 0
+}
+
+/*
+ * NAME
+ *      search_list_mtime_oldest - age of an ingredient
+ *
+ * SYNOPSIS
+ *      long search_list_mtime_oldest(string_ty *name);
+ *
+ * DESCRIPTION
+ *      The search_list_mtime_oldest function is used to obtain the age
+ *      of an ingredient, the time its contents last changed (see
+ *      stat_cache_mtime_oldest): that of the first copy along the
+ *      search list, the one which will actually be used.
+ *
+ *      With the deep flag, all copies along the search list are
+ *      considered, and the youngest is used, so that a change in a
+ *      baseline copy is noticed even when it is shadowed by a copy in
+ *      the work area.
+ *
+ * RETURNS
+ *      long; nanoseconds since the epoch, 0 if there are no copies, -1
+ *      on error (already reported).
+ */
+
+func search_list_mtime_oldest(name *string_ty) long {
+	var copies string_list_ty
+	defer string_list_destructor(&copies)
+	search_list_copies(name, &copies)
+	deep := option_test(OPTION_DEEP)
+	result := long(0)
+	for _, path := range copies.strings {
+		mtime := stat_cache_mtime_oldest(path)
+		if mtime < 0 {
+			return -1
+		}
+		if mtime > result {
+			result = mtime
+		}
+		if result != 0 && !deep {
+			break
+		}
+	}
+	return result
+}
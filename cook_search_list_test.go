@@ -0,0 +1,90 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"os"
+	"testing"
+)
+
+func TestSearchListMtimeOldest(t *testing.T) {
+	tests := []struct {
+		name  string
+		book  string
+		files []string
+		want  string /* the copy whose age is used */
+	}{
+		{
+			name:  "first copy",
+			book:  "search_list = . bl;\n",
+			files: []string{"x", "bl/x"},
+			want:  "x",
+		},
+		{
+			name:  "deeper copy when there is no first copy",
+			book:  "search_list = . bl;\n",
+			files: []string{"bl/x"},
+			want:  "bl/x",
+		},
+		{
+			name:  "youngest copy with the deep flag",
+			book:  "search_list = . bl;\nset deep;\n",
+			files: []string{"x", "bl/x"},
+			want:  "bl/x",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cookbook_test_read(t, tt.book, tt.files...)
+			for j, name := range tt.files {
+				file_test_age(t, name, 100*(len(tt.files)-j))
+			}
+			st, err := os.Stat(tt.want)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := search_list_mtime_oldest(str_from_string("x"))
+			if want := long(st.ModTime().UnixNano()); got != want {
+				t.Errorf("search_list_mtime_oldest = %d, want %d (%s)", got, want, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchListShadowedIngredient(t *testing.T) {
+	for _, deep := range []bool{false, true} {
+		book := "search_list = . bl;\n" +
+			"y: x { touch [target] ran; }\n"
+		if deep {
+			book = "set deep;\n" + book
+		}
+		cookbook_test_read(t, book, "x", "y", "bl/x")
+		file_test_age(t, "x", 300)
+		file_test_age(t, "y", 200)
+		file_test_age(t, "bl/x", 100)
+		if status, msg := cook_test_run(t, "y"); status != 0 {
+			t.Fatalf("deep = %v: cook = %d\n%s", deep, status, msg)
+		}
+		_, err := os.Stat("ran")
+		if ran := err == nil; ran != deep {
+			t.Errorf("deep = %v: recipe ran = %v, want %v", deep, ran, deep)
+		}
+	}
+}
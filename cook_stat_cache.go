@@ -0,0 +1,198 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"syscall"
+)
+
+/*
+ * The stat cache, indexed by file name.  As the file names are
+ * interned, the string pointer is the key.
+ */
+var stat_cache = make(map[*string_ty]*stat_cache_ty)
+
+/*
+ * NAME
+ *      stat_cache_query - file status
+ *
+ * SYNOPSIS
+ *      stat_cache_ty *stat_cache_query(string_ty *path);
+ *
+ * DESCRIPTION
+ *      The stat_cache_query function is used to obtain the status of
+ *      the given file, from the cache if it has been asked about
+ *      before.  Symbolic links are followed.
+ *
+ * RETURNS
+ *      stat_cache_ty *; NULL on error (already reported).  Do not
+ *      change the result.
+ */
+
+func stat_cache_query(path *string_ty) *stat_cache_ty {
+	if scp, ok := stat_cache[path]; ok {
+		return scp
+	}
+	scp := &stat_cache_ty{}
+	st, err := os.Stat(path.str)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) && !errors.Is(err, syscall.ENOTDIR) {
+			os_stat_error(path, err)
+			return nil
+		}
+	} else {
+		scp.exists = true
+		scp.mtime = st.ModTime().UnixNano()
+		scp.ctime = os_ctime(st)
+		scp.size = st.Size()
+		scp.mode = uint32(st.Mode())
+	}
+	stat_cache[str_copy(path)] = scp
+	return scp
+}
+
+/*
+ * NAME
+ *      stat_cache_clear - forget a file's status
+ *
+ * SYNOPSIS
+ *      void stat_cache_clear(string_ty *path);
+ *
+ * DESCRIPTION
+ *      The stat_cache_clear function is used to forget the status of
+ *      the given file, typically because a recipe has just cooked it.
+ */
+
+func stat_cache_clear(path *string_ty) {
+	if _, ok := stat_cache[path]; ok {
+		delete(stat_cache, path)
+		str_free(path)
+	}
+}
+
+/*
+ * NAME
+ *      stat_cache_clear_all - forget all file status
+ *
+ * SYNOPSIS
+ *      void stat_cache_clear_all(void);
+ *
+ * DESCRIPTION
+ *      The stat_cache_clear_all function is used to forget the status
+ *      of all files.  This is done after running the body of a recipe
+ *      with the clearstat flag, for recipes which change files other
+ *      than their targets.
+ */
+
+func stat_cache_clear_all() {
+	for key := range stat_cache {
+		delete(stat_cache, key)
+		str_free(key)
+	}
+}
+
+/*
+ * NAME
+ *      stat_cache_exists - test for file existence
+ *
+ * SYNOPSIS
+ *      int stat_cache_exists(string_ty *path);
+ *
+ * DESCRIPTION
+ *      The stat_cache_exists function is used to determine whether a
+ *      file exists, using the stat cache.
+ *
+ * RETURNS
+ *      int; 1 if the file exists, 0 if it does not, -1 on error
+ *      (already reported).
+ */
+
+func stat_cache_exists(path *string_ty) int {
+	scp := stat_cache_query(path)
+	if scp == nil {
+		return -1
+	}
+	if scp.exists {
+		return 1
+	}
+	return 0
+}
+
+/*
+ * NAME
+ *      stat_cache_mtime_newest - file's last modification time
+ *
+ * SYNOPSIS
+ *      long stat_cache_mtime_newest(string_ty *path);
+ *
+ * DESCRIPTION
+ *      The stat_cache_mtime_newest function is used to obtain the time
+ *      the given file was last written, using the stat cache.  When the
+ *      ctime option is in force, the last status change time is used
+ *      if it is later, so that files restored with their old
+ *      modification times are noticed.
+ *
+ * RETURNS
+ *      long; nanoseconds since the epoch, 0 if the file does not exist,
+ *      -1 on error (already reported).
+ */
+
+func stat_cache_mtime_newest(path *string_ty) long {
+	scp := stat_cache_query(path)
+	if scp == nil {
+		return -1
+	}
+	if !scp.exists {
+		return 0
+	}
+	if option_test(OPTION_CTIME) && scp.ctime > scp.mtime {
+		return scp.ctime
+	}
+	return scp.mtime
+}
+
+/*
+ * NAME
+ *      stat_cache_mtime_oldest - when did a file's contents change
+ *
+ * SYNOPSIS
+ *      long stat_cache_mtime_oldest(string_ty *path);
+ *
+ * DESCRIPTION
+ *      The stat_cache_mtime_oldest function is used to obtain the time
+ *      the contents of the given file last changed.  When the
+ *      fingerprint option is in force this may be earlier than the time
+ *      it was last written (see fingerprint_mtime_oldest), otherwise it
+ *      is the same as stat_cache_mtime_newest.
+ *
+ * RETURNS
+ *      long; nanoseconds since the epoch, 0 if the file does not exist,
+ *      -1 on error (already reported).
+ */
+
+func stat_cache_mtime_oldest(path *string_ty) long {
+	if !option_test(OPTION_FINGERPRINT) {
+		return stat_cache_mtime_newest(path)
+	}
+	return fingerprint_mtime_oldest(path)
+}
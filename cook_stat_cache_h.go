@@ -0,0 +1,33 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+/*
+ * The stat cache remembers the status of each file asked about, so that
+ * building and walking the dependency graph stat each file only once.
+ * Files which do not exist are remembered too.
+ */
+type stat_cache_ty struct {
+	exists bool
+	mtime  long /* nanoseconds since the epoch */
+	ctime  long /* nanoseconds since the epoch */
+	size   long
+	mode   uint32 /* os.FileMode */
+}
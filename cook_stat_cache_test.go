@@ -0,0 +1,68 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"os"
+	"testing"
+)
+
+/*
+ * A recipe which changes a file other than its targets needs the
+ * clearstat flag, so that later recipes see the change.
+ */
+
+func TestStatCacheClearstat(t *testing.T) {
+	for _, clearstat := range []bool{false, true} {
+		flags := ""
+		if clearstat {
+			flags = "set clearstat "
+		}
+		book := "b: a (weak) side { touch [target] ran; }\n" +
+			"a: " + flags + "{ touch [target] side; }\n"
+		cookbook_test_read(t, book, "side", "b")
+		file_test_age(t, "side", 300)
+		file_test_age(t, "b", 200)
+		if status, msg := cook_test_run(t, "b"); status != 0 {
+			t.Fatalf("clearstat = %v: cook = %d\n%s", clearstat, status, msg)
+		}
+		_, err := os.Stat("ran")
+		if ran := err == nil; ran != clearstat {
+			t.Errorf("clearstat = %v: recipe for b ran = %v, want %v", clearstat, ran, clearstat)
+		}
+	}
+}
+
+func TestStatCacheCtime(t *testing.T) {
+	cookbook_test_read(t, "", "x")
+	file_test_age(t, "x", 300)
+	name := str_from_string("x")
+	mtime := stat_cache_mtime_newest(name)
+	option_set(OPTION_CTIME, OPTION_LEVEL_RECIPE, true)
+	ctime := stat_cache_mtime_newest(name)
+	option_undo(OPTION_CTIME, OPTION_LEVEL_RECIPE)
+	if ctime <= mtime {
+		t.Errorf("with ctime, age = %d, want later than the mtime %d", ctime, mtime)
+	}
+	stat_cache_clear(name)
+	if got := stat_cache_mtime_newest(name); got != mtime {
+		t.Errorf("without ctime, age = %d, want %d", got, mtime)
+	}
+}
@@ -0,0 +1,173 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import "fmt"
+
+/*
+ * NAME
+ *      stmt_instance_new - create a statement node
+ *
+ * SYNOPSIS
+ *      stmt_ty *stmt_instance_new(stmt_method_ty *, void *this,
+ *              expr_position_ty *);
+ *
+ * DESCRIPTION
+ *      The stmt_instance_new function is used to create a new
+ *      statement node of the class described by the method.  The
+ *      derived instance is remembered in the "this" field.
+ *
+ * RETURNS
+ *      stmt_ty *; use stmt_delete when finished with it.
+ */
+
+func stmt_instance_new(mp *stmt_method_ty, this interface{}, pp *expr_position_ty) *stmt_ty {
+	trace(fmt.Sprintf("stmt_instance_new(mp = %q)\n{\n", mp.name))
+	sp := &stmt_ty{
+		method:          mp,
+		this:            this,
+		reference_count: 1,
+	}
+	if pp != nil {
+		sp.s_position = *pp
+		if pp.pos_name != nil {
+			sp.s_position.pos_name = str_copy(pp.pos_name)
+		}
+	}
+	trace(fmt.Sprintf("return %p;\n", sp))
+	trace("}\n")
+	return sp
+}
+
+/*
+ * NAME
+ *      stmt_copy - copy a statement
+ *
+ * SYNOPSIS
+ *      stmt_ty *stmt_copy(stmt_ty *);
+ *
+ * DESCRIPTION
+ *      The stmt_copy function is used to make a copy of a statement
+ *      tree.  Statements are never modified once built, so this is
+ *      simply a reference count increment.
+ */
+
+func stmt_copy(sp *stmt_ty) *stmt_ty {
+	sp.reference_count++
+	return sp
+}
+
+/*
+ * NAME
+ *      stmt_delete - release a statement
+ *
+ * SYNOPSIS
+ *      void stmt_delete(stmt_ty *);
+ *
+ * DESCRIPTION
+ *      The stmt_delete function is used to release a statement tree
+ *      when it is finished with.  It is safe to pass NULL.
+ */
+
+func stmt_delete(sp *stmt_ty) {
+	if sp == nil {
+		return
+	}
+	assert(sp.reference_count > 0, "sp.reference_count > 0")
+	sp.reference_count--
+	if sp.reference_count > 0 {
+		return
+	}
+	if sp.method.destructor != nil {
+		sp.method.destructor(sp)
+	}
+	if sp.s_position.pos_name != nil {
+		str_free(sp.s_position.pos_name)
+	}
+	sp.method = nil /* paranoia */
+	sp.this = nil
+}
+
+/*
+ * NAME
+ *      stmt_code - generate opcodes for a statement
+ *
+ * SYNOPSIS
+ *      int stmt_code(stmt_ty *, opcode_list_ty *);
+ *
+ * DESCRIPTION
+ *      The stmt_code function is used to append to the given opcode
+ *      list the opcodes which execute the statement.  Statements leave
+ *      the value stack as they found it.
+ *
+ * RETURNS
+ *      int; 0 on success, -1 on error (already reported).
+ */
+
+func stmt_code(sp *stmt_ty, olp *opcode_list_ty) int {
+	trace(fmt.Sprintf("stmt_code(sp = %p, olp = %p)\n{\n", sp, olp))
+	trace(fmt.Sprintf("sp is a %q\n", sp.method.name))
+	status := 0
+	if sp.method.code != nil {
+		status = sp.method.code(sp, olp)
+	}
+	trace(fmt.Sprintf("return %d;\n", status))
+	trace("}\n")
+	return status
+}
+
+/*
+ * NAME
+ *      stmt_compile - compile a statement
+ *
+ * SYNOPSIS
+ *      opcode_list_ty *stmt_compile(stmt_ty *);
+ *
+ * DESCRIPTION
+ *      The stmt_compile function is used to compile a statement (usually
+ *      a whole cookbook, a recipe body or a function body) into an
+ *      opcode list of its own.  When executed, the opcode list returns
+ *      the value given to the return statement, or an empty list if
+ *      execution falls off the end.
+ *
+ * RETURNS
+ *      opcode_list_ty *; NULL on error (already reported).
+ *      Use opcode_list_delete when you are done with it.
+ */
+
+func stmt_compile(sp *stmt_ty) *opcode_list_ty {
+	trace(fmt.Sprintf("stmt_compile(sp = %p)\n{\n", sp))
+	olp := opcode_list_new()
+	olp.return_label = opcode_label_new()
+	if stmt_code(sp, olp) < 0 {
+		/* the labels may have pending references, forget them */
+		olp.return_label.pending = nil
+		opcode_list_delete(olp)
+		trace("return NULL;\n")
+		trace("}\n")
+		return nil
+	}
+	opcode_list_append(olp, opcode_push_new())
+	opcode_label_define(olp.return_label, olp)
+	opcode_list_append(olp, opcode_return_new())
+	trace(fmt.Sprintf("return %p;\n", olp))
+	trace("}\n")
+	return olp
+}
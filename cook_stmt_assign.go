@@ -0,0 +1,85 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+/*
+ * An assignment statement, "name = value;" or "name += value;".
+ */
+
+type stmt_assign_ty struct {
+	name   *expr_list_ty
+	value  *expr_list_ty
+	append bool /* true for the += form */
+}
+
+func stmt_assign_destructor(sp *stmt_ty) {
+	this, ok := sp.this.(*stmt_assign_ty)
+	assert(ok, "sp.this.(*stmt_assign_ty)")
+	expr_list_delete(this.name)
+	expr_list_delete(this.value)
+}
+
+/*
+ * NAME
+ *      stmt_assign_code
+ *
+ * SYNOPSIS
+ *      int stmt_assign_code(stmt_ty *, opcode_list_ty *);
+ *
+ * DESCRIPTION
+ *      The stmt_assign_code function is used to generate the opcodes
+ *      for an assignment statement.
+ */
+
+func stmt_assign_code(sp *stmt_ty, olp *opcode_list_ty) int {
+	this, ok := sp.this.(*stmt_assign_ty)
+	assert(ok, "sp.this.(*stmt_assign_ty)")
+	opcode_list_append(olp, opcode_push_new())
+	expr_list_code(this.name, olp)
+	opcode_list_append(olp, opcode_push_new())
+	expr_list_code(this.value, olp)
+	opcode_list_append(olp, opcode_assign_new(this.append, &sp.s_position))
+	return 0
+}
+
+var stmt_assign_method = stmt_method_ty{
+	name:       "assign",
+	destructor: stmt_assign_destructor,
+	code:       stmt_assign_code,
+}
+
+/*
+ * NAME
+ *      stmt_assign_new - create an assignment statement
+ *
+ * SYNOPSIS
+ *      stmt_ty *stmt_assign_new(expr_list_ty *name, expr_list_ty *value,
+ *              int append, expr_position_ty *pp);
+ *
+ * DESCRIPTION
+ *      The stmt_assign_new function is used to create a new assignment
+ *      statement node.  The expression lists are taken over, not
+ *      copied.
+ */
+
+func stmt_assign_new(name, value *expr_list_ty, append bool, pp *expr_position_ty) *stmt_ty {
+	this := &stmt_assign_ty{name: name, value: value, append: append}
+	return stmt_instance_new(&stmt_assign_method, this, pp)
+}
@@ -0,0 +1,88 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+/*
+ * A cascade statement, "cascade targets = ingredients;".  Whenever one
+ * of the targets is an ingredient of a recipe, the cascaded
+ * ingredients are implicitly ingredients too.
+ */
+
+type stmt_cascade_ty struct {
+	target     *expr_list_ty
+	ingredient *expr_list_ty
+}
+
+func stmt_cascade_destructor(sp *stmt_ty) {
+	this, ok := sp.this.(*stmt_cascade_ty)
+	assert(ok, "sp.this.(*stmt_cascade_ty)")
+	expr_list_delete(this.target)
+	expr_list_delete(this.ingredient)
+}
+
+/*
+ * NAME
+ *      stmt_cascade_code
+ *
+ * SYNOPSIS
+ *      int stmt_cascade_code(stmt_ty *, opcode_list_ty *);
+ *
+ * DESCRIPTION
+ *      The stmt_cascade_code function is used to generate the opcodes
+ *      for a cascade statement.
+ */
+
+func stmt_cascade_code(sp *stmt_ty, olp *opcode_list_ty) int {
+	this, ok := sp.this.(*stmt_cascade_ty)
+	assert(ok, "sp.this.(*stmt_cascade_ty)")
+	opcode_list_append(olp, opcode_push_new())
+	expr_list_code(this.target, olp)
+	opcode_list_append(olp, opcode_push_new())
+	expr_list_code(this.ingredient, olp)
+	opcode_list_append(olp, opcode_cascade_new(&sp.s_position))
+	return 0
+}
+
+var stmt_cascade_method = stmt_method_ty{
+	name:       "cascade",
+	destructor: stmt_cascade_destructor,
+	code:       stmt_cascade_code,
+}
+
+/*
+ * NAME
+ *      stmt_cascade_new - create a cascade statement
+ *
+ * SYNOPSIS
+ *      stmt_ty *stmt_cascade_new(expr_list_ty *target,
+ *              expr_list_ty *ingredient, expr_position_ty *pp);
+ *
+ * DESCRIPTION
+ *      The stmt_cascade_new function is used to create a new cascade
+ *      statement node.  The expression lists are taken over.
+ */
+
+func stmt_cascade_new(target, ingredient *expr_list_ty, pp *expr_position_ty) *stmt_ty {
+	this := &stmt_cascade_ty{
+		target:     target,
+		ingredient: ingredient,
+	}
+	return stmt_instance_new(&stmt_cascade_method, this, pp)
+}
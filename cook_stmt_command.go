@@ -0,0 +1,94 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+/*
+ * A command statement is a shell command, optionally with flags from
+ * a "set" clause and text from a "data" clause for its standard input.
+ */
+
+type stmt_command_ty struct {
+	args  *expr_list_ty
+	flags *expr_list_ty /* NULL if no set clause */
+	data  *string_ty    /* NULL if no data clause */
+}
+
+func stmt_command_destructor(sp *stmt_ty) {
+	this, ok := sp.this.(*stmt_command_ty)
+	assert(ok, "sp.this.(*stmt_command_ty)")
+	expr_list_delete(this.args)
+	expr_list_delete(this.flags)
+	if this.data != nil {
+		str_free(this.data)
+	}
+}
+
+/*
+ * NAME
+ *      stmt_command_code
+ *
+ * SYNOPSIS
+ *      int stmt_command_code(stmt_ty *, opcode_list_ty *);
+ *
+ * DESCRIPTION
+ *      The stmt_command_code function is used to generate the opcodes
+ *      for a command statement.
+ */
+
+func stmt_command_code(sp *stmt_ty, olp *opcode_list_ty) int {
+	this, ok := sp.this.(*stmt_command_ty)
+	assert(ok, "sp.this.(*stmt_command_ty)")
+	opcode_list_append(olp, opcode_push_new())
+	expr_list_code(this.args, olp)
+	if this.flags != nil {
+		opcode_list_append(olp, opcode_push_new())
+		expr_list_code(this.flags, olp)
+	}
+	opcode_list_append(olp, opcode_command_new(this.flags != nil, this.data, &sp.s_position))
+	return 0
+}
+
+var stmt_command_method = stmt_method_ty{
+	name:       "command",
+	destructor: stmt_command_destructor,
+	code:       stmt_command_code,
+}
+
+/*
+ * NAME
+ *      stmt_command_new - create a command statement
+ *
+ * SYNOPSIS
+ *      stmt_ty *stmt_command_new(expr_list_ty *args, expr_list_ty *flags,
+ *              string_ty *data, expr_position_ty *pp);
+ *
+ * DESCRIPTION
+ *      The stmt_command_new function is used to create a new command
+ *      statement node.  The expression lists are taken over, not
+ *      copied; the data string is copied.
+ */
+
+func stmt_command_new(args, flags *expr_list_ty, data *string_ty, pp *expr_position_ty) *stmt_ty {
+	this := &stmt_command_ty{args: args, flags: flags}
+	if data != nil {
+		this.data = str_copy(data)
+	}
+	return stmt_instance_new(&stmt_command_method, this, pp)
+}
@@ -0,0 +1,102 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+/*
+ * A compound statement is a brace-enclosed list of statements.  The
+ * whole cookbook is also represented as a compound statement.
+ */
+
+type stmt_compound_ty struct {
+	list []*stmt_ty
+}
+
+func stmt_compound_destructor(sp *stmt_ty) {
+	this, ok := sp.this.(*stmt_compound_ty)
+	assert(ok, "sp.this.(*stmt_compound_ty)")
+	for _, child := range this.list {
+		stmt_delete(child)
+	}
+	this.list = nil
+}
+
+/*
+ * NAME
+ *      stmt_compound_code
+ *
+ * SYNOPSIS
+ *      int stmt_compound_code(stmt_ty *, opcode_list_ty *);
+ *
+ * DESCRIPTION
+ *      The stmt_compound_code function is used to generate the opcodes
+ *      for a compound statement, the statements in turn.
+ */
+
+func stmt_compound_code(sp *stmt_ty, olp *opcode_list_ty) int {
+	this, ok := sp.this.(*stmt_compound_ty)
+	assert(ok, "sp.this.(*stmt_compound_ty)")
+	status := 0
+	for _, child := range this.list {
+		if stmt_code(child, olp) < 0 {
+			status = -1
+		}
+	}
+	return status
+}
+
+var stmt_compound_method = stmt_method_ty{
+	name:       "compound",
+	destructor: stmt_compound_destructor,
+	code:       stmt_compound_code,
+}
+
+/*
+ * NAME
+ *      stmt_compound_new - create a compound statement
+ *
+ * SYNOPSIS
+ *      stmt_ty *stmt_compound_new(expr_position_ty *);
+ *
+ * DESCRIPTION
+ *      The stmt_compound_new function is used to create a new, empty,
+ *      compound statement.  Use stmt_compound_append to add to it.
+ */
+
+func stmt_compound_new(pp *expr_position_ty) *stmt_ty {
+	return stmt_instance_new(&stmt_compound_method, &stmt_compound_ty{}, pp)
+}
+
+/*
+ * NAME
+ *      stmt_compound_append - add to a compound statement
+ *
+ * SYNOPSIS
+ *      void stmt_compound_append(stmt_ty *, stmt_ty *);
+ *
+ * DESCRIPTION
+ *      The stmt_compound_append function is used to append a statement
+ *      to a compound statement.  The statement is copied.
+ */
+
+func stmt_compound_append(sp *stmt_ty, child *stmt_ty) {
+	this, ok := sp.this.(*stmt_compound_ty)
+	assert(ok, "sp.this.(*stmt_compound_ty)")
+	this.list = append(this.list, stmt_copy(child))
+}
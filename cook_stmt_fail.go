@@ -0,0 +1,78 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+/*
+ * A fail statement, "fail message;", reports the message and causes
+ * the enclosing recipe (or the cookbook) to fail.
+ */
+
+type stmt_fail_ty struct {
+	value *expr_list_ty /* NULL if no message */
+}
+
+func stmt_fail_destructor(sp *stmt_ty) {
+	this, ok := sp.this.(*stmt_fail_ty)
+	assert(ok, "sp.this.(*stmt_fail_ty)")
+	expr_list_delete(this.value)
+}
+
+/*
+ * NAME
+ *      stmt_fail_code
+ *
+ * SYNOPSIS
+ *      int stmt_fail_code(stmt_ty *, opcode_list_ty *);
+ *
+ * DESCRIPTION
+ *      The stmt_fail_code function is used to generate the opcodes for
+ *      a fail statement.
+ */
+
+func stmt_fail_code(sp *stmt_ty, olp *opcode_list_ty) int {
+	this, ok := sp.this.(*stmt_fail_ty)
+	assert(ok, "sp.this.(*stmt_fail_ty)")
+	opcode_list_append(olp, opcode_push_new())
+	expr_list_code(this.value, olp)
+	opcode_list_append(olp, opcode_fail_new(&sp.s_position))
+	return 0
+}
+
+var stmt_fail_method = stmt_method_ty{
+	name:       "fail",
+	destructor: stmt_fail_destructor,
+	code:       stmt_fail_code,
+}
+
+/*
+ * NAME
+ *      stmt_fail_new - create a fail statement
+ *
+ * SYNOPSIS
+ *      stmt_ty *stmt_fail_new(expr_list_ty *value, expr_position_ty *pp);
+ *
+ * DESCRIPTION
+ *      The stmt_fail_new function is used to create a new fail
+ *      statement node.  The expression list is taken over.
+ */
+
+func stmt_fail_new(value *expr_list_ty, pp *expr_position_ty) *stmt_ty {
+	return stmt_instance_new(&stmt_fail_method, &stmt_fail_ty{value: value}, pp)
+}
@@ -0,0 +1,87 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+/*
+ * A function definition, "function name = { ... }".
+ */
+
+type stmt_function_ty struct {
+	name *expr_list_ty
+	body *stmt_ty
+}
+
+func stmt_function_destructor(sp *stmt_ty) {
+	this, ok := sp.this.(*stmt_function_ty)
+	assert(ok, "sp.this.(*stmt_function_ty)")
+	expr_list_delete(this.name)
+	stmt_delete(this.body)
+}
+
+/*
+ * NAME
+ *      stmt_function_code
+ *
+ * SYNOPSIS
+ *      int stmt_function_code(stmt_ty *, opcode_list_ty *);
+ *
+ * DESCRIPTION
+ *      The stmt_function_code function is used to generate the opcodes
+ *      for a function definition.  The body is compiled into an opcode
+ *      list of its own.
+ */
+
+func stmt_function_code(sp *stmt_ty, olp *opcode_list_ty) int {
+	this, ok := sp.this.(*stmt_function_ty)
+	assert(ok, "sp.this.(*stmt_function_ty)")
+	body := stmt_compile(this.body)
+	if body == nil {
+		return -1
+	}
+	opcode_list_append(olp, opcode_push_new())
+	expr_list_code(this.name, olp)
+	opcode_list_append(olp, opcode_function_define_new(body, &sp.s_position))
+	return 0
+}
+
+var stmt_function_method = stmt_method_ty{
+	name:       "function",
+	destructor: stmt_function_destructor,
+	code:       stmt_function_code,
+}
+
+/*
+ * NAME
+ *      stmt_function_new - create a function definition
+ *
+ * SYNOPSIS
+ *      stmt_ty *stmt_function_new(expr_list_ty *name, stmt_ty *body,
+ *              expr_position_ty *pp);
+ *
+ * DESCRIPTION
+ *      The stmt_function_new function is used to create a new function
+ *      definition statement node.  The name is taken over, the body is
+ *      copied.
+ */
+
+func stmt_function_new(name *expr_list_ty, body *stmt_ty, pp *expr_position_ty) *stmt_ty {
+	this := &stmt_function_ty{name: name, body: stmt_copy(body)}
+	return stmt_instance_new(&stmt_function_method, this, pp)
+}
@@ -0,0 +1,81 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+/*
+ * A function call statement, "function name args;", calls a function
+ * for its side effects.  The result is discarded.
+ */
+
+type stmt_gosub_ty struct {
+	args *expr_list_ty
+}
+
+func stmt_gosub_destructor(sp *stmt_ty) {
+	this, ok := sp.this.(*stmt_gosub_ty)
+	assert(ok, "sp.this.(*stmt_gosub_ty)")
+	expr_list_delete(this.args)
+}
+
+/*
+ * NAME
+ *      stmt_gosub_code
+ *
+ * SYNOPSIS
+ *      int stmt_gosub_code(stmt_ty *, opcode_list_ty *);
+ *
+ * DESCRIPTION
+ *      The stmt_gosub_code function is used to generate the opcodes for
+ *      a function call statement.  The result of the function is
+ *      discarded.
+ */
+
+func stmt_gosub_code(sp *stmt_ty, olp *opcode_list_ty) int {
+	this, ok := sp.this.(*stmt_gosub_ty)
+	assert(ok, "sp.this.(*stmt_gosub_ty)")
+	opcode_list_append(olp, opcode_push_new())
+	opcode_list_append(olp, opcode_push_new())
+	expr_list_code(this.args, olp)
+	opcode_list_append(olp, opcode_function_new(&sp.s_position))
+	opcode_list_append(olp, opcode_pop_new())
+	return 0
+}
+
+var stmt_gosub_method = stmt_method_ty{
+	name:       "gosub",
+	destructor: stmt_gosub_destructor,
+	code:       stmt_gosub_code,
+}
+
+/*
+ * NAME
+ *      stmt_gosub_new - create a function call statement
+ *
+ * SYNOPSIS
+ *      stmt_ty *stmt_gosub_new(expr_list_ty *args, expr_position_ty *pp);
+ *
+ * DESCRIPTION
+ *      The stmt_gosub_new function is used to create a new function
+ *      call statement node.  The expression list is taken over.
+ */
+
+func stmt_gosub_new(args *expr_list_ty, pp *expr_position_ty) *stmt_ty {
+	return stmt_instance_new(&stmt_gosub_method, &stmt_gosub_ty{args: args}, pp)
+}
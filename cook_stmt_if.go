@@ -0,0 +1,107 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+/*
+ * An if statement, "if condition then statement else statement".
+ * The condition is false if it evaluates to an empty list, or a list
+ * of empty strings.
+ */
+
+type stmt_if_ty struct {
+	condition   *expr_list_ty
+	then_clause *stmt_ty
+	else_clause *stmt_ty /* NULL if no else clause */
+}
+
+func stmt_if_destructor(sp *stmt_ty) {
+	this, ok := sp.this.(*stmt_if_ty)
+	assert(ok, "sp.this.(*stmt_if_ty)")
+	expr_list_delete(this.condition)
+	stmt_delete(this.then_clause)
+	stmt_delete(this.else_clause)
+}
+
+/*
+ * NAME
+ *      stmt_if_code
+ *
+ * SYNOPSIS
+ *      int stmt_if_code(stmt_ty *, opcode_list_ty *);
+ *
+ * DESCRIPTION
+ *      The stmt_if_code function is used to generate the opcodes for an
+ *      if statement.  The condition is evaluated, and a jmpf opcode
+ *      skips the then clause if it is false.
+ */
+
+func stmt_if_code(sp *stmt_ty, olp *opcode_list_ty) int {
+	this, ok := sp.this.(*stmt_if_ty)
+	assert(ok, "sp.this.(*stmt_if_ty)")
+	else_label := opcode_label_new()
+	opcode_list_append(olp, opcode_push_new())
+	expr_list_code(this.condition, olp)
+	opcode_list_append(olp, opcode_jmpf_new(else_label))
+	status := stmt_code(this.then_clause, olp)
+	if this.else_clause != nil {
+		end_label := opcode_label_new()
+		opcode_list_append(olp, opcode_goto_new(end_label))
+		opcode_label_define(else_label, olp)
+		if stmt_code(this.else_clause, olp) < 0 {
+			status = -1
+		}
+		opcode_label_define(end_label, olp)
+		opcode_label_delete(end_label)
+	} else {
+		opcode_label_define(else_label, olp)
+	}
+	opcode_label_delete(else_label)
+	return status
+}
+
+var stmt_if_method = stmt_method_ty{
+	name:       "if",
+	destructor: stmt_if_destructor,
+	code:       stmt_if_code,
+}
+
+/*
+ * NAME
+ *      stmt_if_new - create an if statement
+ *
+ * SYNOPSIS
+ *      stmt_ty *stmt_if_new(expr_list_ty *condition, stmt_ty *then_clause,
+ *              stmt_ty *else_clause, expr_position_ty *pp);
+ *
+ * DESCRIPTION
+ *      The stmt_if_new function is used to create a new if statement
+ *      node.  The condition is taken over, the clauses are copied.
+ */
+
+func stmt_if_new(condition *expr_list_ty, then_clause, else_clause *stmt_ty, pp *expr_position_ty) *stmt_ty {
+	this := &stmt_if_ty{
+		condition:   condition,
+		then_clause: stmt_copy(then_clause),
+	}
+	if else_clause != nil {
+		this.else_clause = stmt_copy(else_clause)
+	}
+	return stmt_instance_new(&stmt_if_method, this, pp)
+}
@@ -0,0 +1,91 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+/*
+ * A loop statement, "loop { ... }", repeats until a loopstop statement
+ * is executed.
+ */
+
+type stmt_loop_ty struct {
+	body *stmt_ty
+}
+
+func stmt_loop_destructor(sp *stmt_ty) {
+	this, ok := sp.this.(*stmt_loop_ty)
+	assert(ok, "sp.this.(*stmt_loop_ty)")
+	stmt_delete(this.body)
+}
+
+/*
+ * NAME
+ *      stmt_loop_code
+ *
+ * SYNOPSIS
+ *      int stmt_loop_code(stmt_ty *, opcode_list_ty *);
+ *
+ * DESCRIPTION
+ *      The stmt_loop_code function is used to generate the opcodes for
+ *      a loop statement.  The body is executed repeatedly, until a
+ *      loopstop statement jumps to the break label.
+ */
+
+func stmt_loop_code(sp *stmt_ty, olp *opcode_list_ty) int {
+	this, ok := sp.this.(*stmt_loop_ty)
+	assert(ok, "sp.this.(*stmt_loop_ty)")
+	old_break := olp.break_label
+	old_continue := olp.continue_label
+	olp.break_label = opcode_label_new()
+	olp.continue_label = opcode_label_new()
+
+	opcode_label_define(olp.continue_label, olp)
+	status := stmt_code(this.body, olp)
+	opcode_list_append(olp, opcode_goto_new(olp.continue_label))
+	opcode_label_define(olp.break_label, olp)
+
+	opcode_label_delete(olp.break_label)
+	opcode_label_delete(olp.continue_label)
+	olp.break_label = old_break
+	olp.continue_label = old_continue
+	return status
+}
+
+var stmt_loop_method = stmt_method_ty{
+	name:       "loop",
+	destructor: stmt_loop_destructor,
+	code:       stmt_loop_code,
+}
+
+/*
+ * NAME
+ *      stmt_loop_new - create a loop statement
+ *
+ * SYNOPSIS
+ *      stmt_ty *stmt_loop_new(stmt_ty *body, expr_position_ty *pp);
+ *
+ * DESCRIPTION
+ *      The stmt_loop_new function is used to create a new loop
+ *      statement node.  The body is copied.
+ */
+
+func stmt_loop_new(body *stmt_ty, pp *expr_position_ty) *stmt_ty {
+	this := &stmt_loop_ty{body: stmt_copy(body)}
+	return stmt_instance_new(&stmt_loop_method, this, pp)
+}
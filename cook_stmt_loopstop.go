@@ -0,0 +1,69 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+/*
+ * A loopstop statement terminates the innermost loop.
+ */
+
+type stmt_loopstop_ty struct{}
+
+/*
+ * NAME
+ *      stmt_loopstop_code
+ *
+ * SYNOPSIS
+ *      int stmt_loopstop_code(stmt_ty *, opcode_list_ty *);
+ *
+ * DESCRIPTION
+ *      The stmt_loopstop_code function is used to generate the opcodes
+ *      for a loopstop statement, a jump to the break label of the
+ *      innermost loop.
+ */
+
+func stmt_loopstop_code(sp *stmt_ty, olp *opcode_list_ty) int {
+	if olp.break_label == nil {
+		error_with_position(&sp.s_position, nil, i18n("loopstop statement not within a loop"))
+		return -1
+	}
+	opcode_list_append(olp, opcode_goto_new(olp.break_label))
+	return 0
+}
+
+var stmt_loopstop_method = stmt_method_ty{
+	name: "loopstop",
+	code: stmt_loopstop_code,
+}
+
+/*
+ * NAME
+ *      stmt_loopstop_new - create a loopstop statement
+ *
+ * SYNOPSIS
+ *      stmt_ty *stmt_loopstop_new(expr_position_ty *pp);
+ *
+ * DESCRIPTION
+ *      The stmt_loopstop_new function is used to create a new loopstop
+ *      statement node.
+ */
+
+func stmt_loopstop_new(pp *expr_position_ty) *stmt_ty {
+	return stmt_instance_new(&stmt_loopstop_method, &stmt_loopstop_ty{}, pp)
+}
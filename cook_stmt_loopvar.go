@@ -0,0 +1,107 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+/*
+ * A loop variable statement, "loop name = words { ... }", executes the
+ * body once for each word, with the variable set to the word.
+ */
+
+type stmt_loopvar_ty struct {
+	name   *expr_list_ty
+	values *expr_list_ty
+	body   *stmt_ty
+}
+
+func stmt_loopvar_destructor(sp *stmt_ty) {
+	this, ok := sp.this.(*stmt_loopvar_ty)
+	assert(ok, "sp.this.(*stmt_loopvar_ty)")
+	expr_list_delete(this.name)
+	expr_list_delete(this.values)
+	stmt_delete(this.body)
+}
+
+/*
+ * NAME
+ *      stmt_loopvar_code
+ *
+ * SYNOPSIS
+ *      int stmt_loopvar_code(stmt_ty *, opcode_list_ty *);
+ *
+ * DESCRIPTION
+ *      The stmt_loopvar_code function is used to generate the opcodes
+ *      for a loop statement with a variable.  The name and the values
+ *      stay on the value stack for the duration of the loop; the
+ *      loop_next opcode assigns each value in turn, and jumps to the
+ *      break label when they run out.
+ */
+
+func stmt_loopvar_code(sp *stmt_ty, olp *opcode_list_ty) int {
+	this, ok := sp.this.(*stmt_loopvar_ty)
+	assert(ok, "sp.this.(*stmt_loopvar_ty)")
+	opcode_list_append(olp, opcode_push_new())
+	expr_list_code(this.name, olp)
+	opcode_list_append(olp, opcode_push_new())
+	expr_list_code(this.values, olp)
+
+	old_break := olp.break_label
+	old_continue := olp.continue_label
+	olp.break_label = opcode_label_new()
+	olp.continue_label = opcode_label_new()
+
+	opcode_label_define(olp.continue_label, olp)
+	opcode_list_append(olp, opcode_loop_next_new(olp.break_label, &sp.s_position))
+	status := stmt_code(this.body, olp)
+	opcode_list_append(olp, opcode_goto_new(olp.continue_label))
+	opcode_label_define(olp.break_label, olp)
+	opcode_list_append(olp, opcode_pop_new())
+	opcode_list_append(olp, opcode_pop_new())
+
+	opcode_label_delete(olp.break_label)
+	opcode_label_delete(olp.continue_label)
+	olp.break_label = old_break
+	olp.continue_label = old_continue
+	return status
+}
+
+var stmt_loopvar_method = stmt_method_ty{
+	name:       "loopvar",
+	destructor: stmt_loopvar_destructor,
+	code:       stmt_loopvar_code,
+}
+
+/*
+ * NAME
+ *      stmt_loopvar_new - create a loop variable statement
+ *
+ * SYNOPSIS
+ *      stmt_ty *stmt_loopvar_new(expr_list_ty *name, expr_list_ty *values,
+ *              stmt_ty *body, expr_position_ty *pp);
+ *
+ * DESCRIPTION
+ *      The stmt_loopvar_new function is used to create a new loop
+ *      variable statement node.  The expression lists are taken over,
+ *      the body is copied.
+ */
+
+func stmt_loopvar_new(name, values *expr_list_ty, body *stmt_ty, pp *expr_position_ty) *stmt_ty {
+	this := &stmt_loopvar_ty{name: name, values: values, body: stmt_copy(body)}
+	return stmt_instance_new(&stmt_loopvar_method, this, pp)
+}
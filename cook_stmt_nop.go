@@ -0,0 +1,47 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+/*
+ * A nop statement does nothing.  It is produced by empty statements,
+ * and by the parser when recovering from syntax errors.
+ */
+
+type stmt_nop_ty struct{}
+
+var stmt_nop_method = stmt_method_ty{
+	name: "nop",
+}
+
+/*
+ * NAME
+ *      stmt_nop_new - create a nop statement
+ *
+ * SYNOPSIS
+ *      stmt_ty *stmt_nop_new(expr_position_ty *pp);
+ *
+ * DESCRIPTION
+ *      The stmt_nop_new function is used to create a new nop statement
+ *      node.
+ */
+
+func stmt_nop_new(pp *expr_position_ty) *stmt_ty {
+	return stmt_instance_new(&stmt_nop_method, &stmt_nop_ty{}, pp)
+}
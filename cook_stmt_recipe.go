@@ -0,0 +1,168 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+/*
+ * A recipe statement:
+ *
+ *      targets : need1 [ : need2 ]
+ *              [ set flags ]
+ *              [ if precondition ]
+ *              [ single-thread words ]
+ *              [ host-binding words ]
+ *      { out_of_date } [ else { up_to_date } ]
+ *
+ * or the same header terminated by a semicolon, for a recipe which only
+ * contributes ingredients.  Two colons make the recipe "multiple".
+ */
+
+type stmt_recipe_ty struct {
+	target        *expr_list_ty
+	need1         *expr_list_ty
+	need2         *expr_list_ty /* NULL if no second ingredients list */
+	flags         *expr_list_ty /* NULL if no set clause */
+	precondition  *expr_list_ty /* NULL if no if clause */
+	single_thread *expr_list_ty /* NULL if no single-thread clause */
+	host_binding  *expr_list_ty /* NULL if no host-binding clause */
+	out_of_date   *stmt_ty      /* NULL if no body */
+	up_to_date    *stmt_ty      /* NULL if no else clause */
+	multiple      int
+}
+
+func stmt_recipe_destructor(sp *stmt_ty) {
+	this, ok := sp.this.(*stmt_recipe_ty)
+	assert(ok, "sp.this.(*stmt_recipe_ty)")
+	expr_list_delete(this.target)
+	expr_list_delete(this.need1)
+	expr_list_delete(this.need2)
+	expr_list_delete(this.flags)
+	expr_list_delete(this.precondition)
+	expr_list_delete(this.single_thread)
+	expr_list_delete(this.host_binding)
+	stmt_delete(this.out_of_date)
+	stmt_delete(this.up_to_date)
+}
+
+/*
+ * NAME
+ *      stmt_recipe_code
+ *
+ * SYNOPSIS
+ *      int stmt_recipe_code(stmt_ty *, opcode_list_ty *);
+ *
+ * DESCRIPTION
+ *      The stmt_recipe_code function is used to generate the opcodes
+ *      for a recipe definition.  The targets and flags are evaluated
+ *      when the recipe is defined; the ingredients, the clauses and the
+ *      bodies are compiled into opcode lists of their own, to be
+ *      evaluated when the recipe is used.
+ */
+
+func stmt_recipe_code(sp *stmt_ty, olp *opcode_list_ty) int {
+	this, ok := sp.this.(*stmt_recipe_ty)
+	assert(ok, "sp.this.(*stmt_recipe_ty)")
+	status := 0
+	var out_of_date, up_to_date *opcode_list_ty
+	if this.out_of_date != nil {
+		out_of_date = stmt_compile(this.out_of_date)
+		if out_of_date == nil {
+			status = -1
+		}
+	}
+	if this.up_to_date != nil {
+		up_to_date = stmt_compile(this.up_to_date)
+		if up_to_date == nil {
+			status = -1
+		}
+	}
+	if status < 0 {
+		opcode_list_delete(out_of_date)
+		opcode_list_delete(up_to_date)
+		return -1
+	}
+
+	opcode_list_append(olp, opcode_push_new())
+	expr_list_code(this.target, olp)
+	opcode_list_append(olp, opcode_push_new())
+	expr_list_code(this.flags, olp)
+	opcode_list_append(
+		olp,
+		opcode_recipe_new(
+			expr_list_compile(this.need1),
+			expr_list_compile(this.need2),
+			expr_list_compile(this.precondition),
+			expr_list_compile(this.single_thread),
+			expr_list_compile(this.host_binding),
+			out_of_date,
+			up_to_date,
+			this.multiple,
+			&sp.s_position,
+		),
+	)
+	return 0
+}
+
+var stmt_recipe_method = stmt_method_ty{
+	name:       "recipe",
+	destructor: stmt_recipe_destructor,
+	code:       stmt_recipe_code,
+}
+
+/*
+ * NAME
+ *      stmt_recipe_new - create a recipe statement
+ *
+ * SYNOPSIS
+ *      stmt_ty *stmt_recipe_new(expr_list_ty *target, expr_list_ty *need1,
+ *              expr_list_ty *need2, expr_list_ty *flags,
+ *              expr_list_ty *precondition, expr_list_ty *single_thread,
+ *              expr_list_ty *host_binding, stmt_ty *out_of_date,
+ *              stmt_ty *up_to_date, int multiple, expr_position_ty *pp);
+ *
+ * DESCRIPTION
+ *      The stmt_recipe_new function is used to create a new recipe
+ *      statement node.  The expression lists are taken over, the
+ *      bodies are copied.  The position is that of the colon.
+ */
+
+func stmt_recipe_new(
+	target, need1, need2, flags, precondition, single_thread, host_binding *expr_list_ty,
+	out_of_date, up_to_date *stmt_ty,
+	multiple int,
+	pp *expr_position_ty,
+) *stmt_ty {
+	this := &stmt_recipe_ty{
+		target:        target,
+		need1:         need1,
+		need2:         need2,
+		flags:         flags,
+		precondition:  precondition,
+		single_thread: single_thread,
+		host_binding:  host_binding,
+		multiple:      multiple,
+	}
+	if out_of_date != nil {
+		this.out_of_date = stmt_copy(out_of_date)
+	}
+	if up_to_date != nil {
+		this.up_to_date = stmt_copy(up_to_date)
+	}
+	return stmt_instance_new(&stmt_recipe_method, this, pp)
+}
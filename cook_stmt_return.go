@@ -0,0 +1,80 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+/*
+ * A return statement, "return value;", leaves the current function or
+ * recipe body.  The value is only meaningful in functions.
+ */
+
+type stmt_return_ty struct {
+	value *expr_list_ty /* NULL if no value */
+}
+
+func stmt_return_destructor(sp *stmt_ty) {
+	this, ok := sp.this.(*stmt_return_ty)
+	assert(ok, "sp.this.(*stmt_return_ty)")
+	expr_list_delete(this.value)
+}
+
+/*
+ * NAME
+ *      stmt_return_code
+ *
+ * SYNOPSIS
+ *      int stmt_return_code(stmt_ty *, opcode_list_ty *);
+ *
+ * DESCRIPTION
+ *      The stmt_return_code function is used to generate the opcodes
+ *      for a return statement.  The value is left on the value stack,
+ *      and control jumps to the return label.
+ */
+
+func stmt_return_code(sp *stmt_ty, olp *opcode_list_ty) int {
+	this, ok := sp.this.(*stmt_return_ty)
+	assert(ok, "sp.this.(*stmt_return_ty)")
+	assert(olp.return_label != nil, "olp.return_label != nil")
+	opcode_list_append(olp, opcode_push_new())
+	expr_list_code(this.value, olp)
+	opcode_list_append(olp, opcode_goto_new(olp.return_label))
+	return 0
+}
+
+var stmt_return_method = stmt_method_ty{
+	name:       "return",
+	destructor: stmt_return_destructor,
+	code:       stmt_return_code,
+}
+
+/*
+ * NAME
+ *      stmt_return_new - create a return statement
+ *
+ * SYNOPSIS
+ *      stmt_ty *stmt_return_new(expr_list_ty *value, expr_position_ty *pp);
+ *
+ * DESCRIPTION
+ *      The stmt_return_new function is used to create a new return
+ *      statement node.  The expression list is taken over.
+ */
+
+func stmt_return_new(value *expr_list_ty, pp *expr_position_ty) *stmt_ty {
+	return stmt_instance_new(&stmt_return_method, &stmt_return_ty{value: value}, pp)
+}
@@ -0,0 +1,78 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+/*
+ * A set statement, "set flags;", sets recipe flags for the whole
+ * cookbook.
+ */
+
+type stmt_set_ty struct {
+	flags *expr_list_ty
+}
+
+func stmt_set_destructor(sp *stmt_ty) {
+	this, ok := sp.this.(*stmt_set_ty)
+	assert(ok, "sp.this.(*stmt_set_ty)")
+	expr_list_delete(this.flags)
+}
+
+/*
+ * NAME
+ *      stmt_set_code
+ *
+ * SYNOPSIS
+ *      int stmt_set_code(stmt_ty *, opcode_list_ty *);
+ *
+ * DESCRIPTION
+ *      The stmt_set_code function is used to generate the opcodes for
+ *      a set statement.
+ */
+
+func stmt_set_code(sp *stmt_ty, olp *opcode_list_ty) int {
+	this, ok := sp.this.(*stmt_set_ty)
+	assert(ok, "sp.this.(*stmt_set_ty)")
+	opcode_list_append(olp, opcode_push_new())
+	expr_list_code(this.flags, olp)
+	opcode_list_append(olp, opcode_set_new(&sp.s_position))
+	return 0
+}
+
+var stmt_set_method = stmt_method_ty{
+	name:       "set",
+	destructor: stmt_set_destructor,
+	code:       stmt_set_code,
+}
+
+/*
+ * NAME
+ *      stmt_set_new - create a set statement
+ *
+ * SYNOPSIS
+ *      stmt_ty *stmt_set_new(expr_list_ty *flags, expr_position_ty *pp);
+ *
+ * DESCRIPTION
+ *      The stmt_set_new function is used to create a new set statement
+ *      node.  The expression list is taken over.
+ */
+
+func stmt_set_new(flags *expr_list_ty, pp *expr_position_ty) *stmt_ty {
+	return stmt_instance_new(&stmt_set_method, &stmt_set_ty{flags: flags}, pp)
+}
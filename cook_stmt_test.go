@@ -0,0 +1,74 @@
+/*
+ * cook - file construction tool
+ * Copyright (C) 2021 Michael D Henderson
+ * Copyright (C) 1993-2010 Peter Miller
+ *
+ * This program is free software; you can redistribute it and/or modify
+ * it under the terms of the GNU General Public License as published by
+ * the Free Software Foundation; either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program. If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import "testing"
+
+func TestStmtCompileControlFlow(t *testing.T) {
+	tests := []struct {
+		name string
+		book string
+		want string
+	}{
+		{
+			name: "if then else",
+			book: "if [in a x a] then r = yes; else r = no;\n",
+			want: "yes",
+		},
+		{
+			name: "else",
+			book: "if [in b x a] then r = yes; else r = no;\n",
+			want: "no",
+		},
+		{
+			name: "loop over a list",
+			book: "r = ;\nloop w = a b c { r += [w]; }\n",
+			want: "a b c",
+		},
+		{
+			name: "loopstop leaves the inner loop only",
+			book: "r = ;\n" +
+				"loop x = a b {\n" +
+				"    loop y = 1 2 3 { if [in [y] 2] then loopstop; r += [x][y]; }\n" +
+				"    r += [x];\n" +
+				"}\n",
+			want: "a1 a b1 b",
+		},
+		{
+			name: "loop until loopstop",
+			book: "r = ;\n" +
+				"n = a b c;\n" +
+				"loop { if [not [n]] then loopstop; r += [head [n]]; n = [tail [n]]; }\n",
+			want: "a b c",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cookbook_test_read(t, tt.book)
+			got := id_variable_query("r")
+			if got == nil {
+				t.Fatalf("r is not set")
+			}
+			if s := wl2str(got, 0, len(got.strings)-1, " ").str; s != tt.want {
+				t.Errorf("r = %q, want %q", s, tt.want)
+			}
+		})
+	}
+}
@@ -19,6 +19,12 @@
 
 package signals
 
+import (
+	"fmt"
+	"os/signal"
+	"syscall"
+)
+
 func Signal(s string, a string) {
 	switch s {
 	case "SIGCHLD":